@@ -0,0 +1,18 @@
+# Snapshot the controller before the WAN configuration changes.
+resource "unifi_wan" "primary" {
+  # ...
+
+  lifecycle {
+    action_trigger {
+      events  = [before_update]
+      actions = [action.unifi_backup.pre_apply]
+    }
+  }
+}
+
+action "unifi_backup" "pre_apply" {
+  config {
+    path = "${path.root}/backups/pre-apply-${formatdate("YYYYMMDDhhmmss", timestamp())}.unf"
+    days = 0
+  }
+}
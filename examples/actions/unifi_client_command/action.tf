@@ -0,0 +1,20 @@
+# Disconnect a client so that it reconnects, e.g. to pick up a new VLAN.
+action "unifi_client_command" "kick_laptop" {
+  config {
+    mac     = "01:23:45:67:89:ab"
+    command = "kick"
+  }
+}
+
+# Let a visitor onto the guest network for a day, capped at 10 Mbps down,
+# 2 Mbps up and 1 GB of data.
+action "unifi_client_command" "visitor" {
+  config {
+    mac           = "01:23:45:67:89:cd"
+    command       = "authorize_guest"
+    minutes       = 1440
+    download_kbps = 10000
+    upload_kbps   = 2000
+    data_limit_mb = 1024
+  }
+}
@@ -0,0 +1,23 @@
+# Blink the LED of an access point for two minutes.
+action "unifi_device_command" "locate_lobby_ap" {
+  config {
+    device_mac     = "00:27:22:00:00:01"
+    command        = "locate"
+    locate_seconds = 120
+  }
+}
+
+# Adopt a switch in a branch office that cannot reach the controller by
+# layer 2 discovery. The switch is in its factory state, so the controller
+# has not seen its SSH host key yet.
+action "unifi_device_command" "adopt_branch_switch" {
+  config {
+    device_mac     = "00:27:22:00:00:02"
+    command        = "set_inform"
+    ip             = "10.40.0.2"
+    inform_url     = "http://unifi.example.com:8080/inform"
+    ssh_username   = "ubnt"
+    ssh_password   = var.factory_ssh_password
+    ssh_key_verify = false
+  }
+}
@@ -0,0 +1,34 @@
+# The unifi_device_restart action restarts a UniFi device and waits until it
+# has reconnected to the controller, so later steps can rely on it being back.
+
+# Soft restart of an access point.
+action "unifi_device_restart" "office_ap" {
+  config {
+    device_mac = "01:23:45:67:89:ab"
+  }
+}
+
+# Power-cycle a PoE-powered access point through the switch port feeding it.
+action "unifi_device_restart" "office_ap_hard" {
+  config {
+    device_mac  = "01:23:45:67:89:ab"
+    reboot_type = "hard"
+
+    timeouts {
+      invoke = "15m"
+    }
+  }
+}
+
+# Restart the access point after its configuration changes (Terraform >= 1.14).
+resource "unifi_device" "office_ap" {
+  mac  = "01:23:45:67:89:ab"
+  name = "office-ap"
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.unifi_device_restart.office_ap]
+    }
+  }
+}
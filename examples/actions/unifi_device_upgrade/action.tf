@@ -0,0 +1,32 @@
+# The unifi_device_upgrade action rolls firmware out to a list of devices in
+# batches. Each batch must reconnect on the new firmware before the next one
+# starts; if a device does not come back, the rollout stops and the remaining
+# devices are left untouched.
+
+# Upgrade the access points to the latest firmware, two at a time.
+action "unifi_device_upgrade" "access_points" {
+  config {
+    device_macs = [
+      "01:23:45:67:89:a1",
+      "01:23:45:67:89:a2",
+      "01:23:45:67:89:a3",
+      "01:23:45:67:89:a4",
+    ]
+    batch_size    = 2
+    batch_timeout = "20m"
+  }
+}
+
+# Install a specific firmware image on a switch and check the version it
+# reports afterwards.
+action "unifi_device_upgrade" "core_switch" {
+  config {
+    device_macs      = ["01:23:45:67:89:ab"]
+    firmware_url     = "https://fw-download.ubnt.com/data/usw/example.bin"
+    firmware_version = "7.1.26"
+
+    timeouts {
+      invoke = "45m"
+    }
+  }
+}
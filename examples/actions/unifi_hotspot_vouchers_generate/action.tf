@@ -0,0 +1,12 @@
+# Create a fresh stack of conference room vouchers with
+#   terraform apply -invoke=action.unifi_hotspot_vouchers_generate.conference
+# The codes are shown in the action's output and written to vouchers/conference.txt.
+action "unifi_hotspot_vouchers_generate" "conference" {
+  config {
+    quantity       = 20
+    quota          = 0
+    expire_minutes = 480
+    note           = "Conference room"
+    path           = "${path.root}/vouchers/conference.txt"
+  }
+}
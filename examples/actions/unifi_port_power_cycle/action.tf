@@ -0,0 +1,20 @@
+# Power-cycle the PoE port of a hung camera and wait for it to come back.
+action "unifi_port_power_cycle" "camera" {
+  config {
+    device_mac        = "01:23:45:67:89:ab"
+    port_numbers      = [7]
+    wait_for_neighbor = true
+
+    timeouts {
+      invoke = "5m"
+    }
+  }
+}
+
+# Power-cycle several access points on the same switch without waiting.
+action "unifi_port_power_cycle" "access_points" {
+  config {
+    device_mac   = "01:23:45:67:89:ab"
+    port_numbers = [1, 2, 3]
+  }
+}
@@ -0,0 +1,18 @@
+# Check the ISP circuit after changing the WAN configuration.
+resource "unifi_wan" "primary" {
+  # ...
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.unifi_speedtest.circuit]
+    }
+  }
+}
+
+action "unifi_speedtest" "circuit" {
+  config {
+    min_download_mbps = 800
+    min_upload_mbps   = 35
+  }
+}
@@ -0,0 +1,26 @@
+# Rotate the guest Wi-Fi passphrase with
+#   terraform apply -invoke=action.unifi_wlan_rotate_passphrase.guests
+# The new passphrase is written to a file readable only by the current user,
+# and never shown in the output or stored in state.
+action "unifi_wlan_rotate_passphrase" "guests" {
+  config {
+    wlan_id           = var.guest_wlan_id
+    length            = 12
+    exclude_ambiguous = true
+    path              = "${path.root}/secrets/guest-wifi.txt"
+  }
+}
+
+# Rotate the key of one apartment on a WLAN with private pre-shared keys,
+# using a passphrase generated elsewhere.
+ephemeral "unifi_generated_passphrase" "unit_102" {
+  length = 16
+}
+
+action "unifi_wlan_rotate_passphrase" "unit_102" {
+  config {
+    wlan_id                     = var.residents_wlan_id
+    private_preshared_key_index = 1
+    passphrase                  = ephemeral.unifi_generated_passphrase.unit_102.result
+  }
+}
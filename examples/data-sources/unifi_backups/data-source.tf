@@ -0,0 +1,5 @@
+data "unifi_backups" "all" {}
+
+output "latest_autobackup" {
+  value = try(data.unifi_backups.all.backups[0].filename, null)
+}
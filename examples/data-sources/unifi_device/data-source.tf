@@ -0,0 +1,25 @@
+# Look up a switch by name and report where it is patched in and which of its
+# ports are drawing PoE power.
+data "unifi_device" "core" {
+  name = "Core Switch"
+}
+
+output "core_uplink" {
+  value = data.unifi_device.core.uplink
+}
+
+output "core_poe_ports" {
+  value = {
+    for port in data.unifi_device.core.port_table :
+    port.port_idx => port.poe_power_watts if port.poe_power_watts > 0
+  }
+}
+
+# Access points can be looked up by MAC address as well.
+data "unifi_device" "lobby_ap" {
+  mac = "00:27:22:00:00:02"
+}
+
+output "lobby_ap_channels" {
+  value = [for radio in data.unifi_device.lobby_ap.radio_table : "${radio.radio}: ${radio.channel}"]
+}
@@ -0,0 +1,9 @@
+# Reference an address group maintained by another team.
+data "unifi_firewall_group" "monitoring" {
+  name = "Monitoring Servers"
+  type = "address-group"
+}
+
+output "monitoring_servers" {
+  value = data.unifi_firewall_group.monitoring.members
+}
@@ -1,5 +1,5 @@
 # Look up a zone-based firewall zone by its display name
-# (UniFi Network 8.x+). Useful for wiring zone IDs into
+# (UniFi Network 9.0+). Useful for wiring zone IDs into
 # unifi_firewall_policy resources.
 data "unifi_firewall_zone" "internal" {
   name = "Internal"
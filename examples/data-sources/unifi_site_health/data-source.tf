@@ -0,0 +1,22 @@
+data "unifi_site_health" "default" {
+}
+
+# Flag an apply that leaves the site without internet access.
+check "wan_online" {
+  assert {
+    condition     = data.unifi_site_health.default.wan.status == "ok"
+    error_message = "The WAN of site ${data.unifi_site_health.default.site} is ${data.unifi_site_health.default.wan.status}."
+  }
+}
+
+# Flag devices that dropped off after an apply.
+check "devices_connected" {
+  assert {
+    condition     = data.unifi_site_health.default.num_disconnected == 0
+    error_message = "${data.unifi_site_health.default.num_disconnected} adopted device(s) are disconnected."
+  }
+}
+
+output "public_ip" {
+  value = data.unifi_site_health.default.wan.public_ip
+}
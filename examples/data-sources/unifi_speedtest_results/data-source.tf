@@ -0,0 +1,7 @@
+data "unifi_speedtest_results" "last_week" {
+  lookback = "168h"
+}
+
+output "latest_download_mbps" {
+  value = try(data.unifi_speedtest_results.last_week.results[0].download_mbps, null)
+}
@@ -0,0 +1,8 @@
+# Look up a WiFi network created in the controller UI.
+data "unifi_wlan" "guest" {
+  name = "Guest"
+}
+
+output "guest_network_id" {
+  value = data.unifi_wlan.guest.network_id
+}
@@ -0,0 +1,18 @@
+# Generate a guest Wi-Fi passphrase without writing it to state.
+ephemeral "unifi_generated_passphrase" "guest" {
+  length            = 16
+  exclude_ambiguous = true
+}
+
+data "unifi_client_qos_rate" "default" {
+}
+
+# The write-only passphrase is sent to the controller but never stored. A new
+# passphrase is generated on every run and is applied whenever the WLAN is
+# created or updated.
+resource "unifi_wlan" "guest" {
+  name          = "guest"
+  security      = "wpapsk"
+  user_group_id = data.unifi_client_qos_rate.default.id
+  passphrase_wo = ephemeral.unifi_generated_passphrase.guest.result
+}
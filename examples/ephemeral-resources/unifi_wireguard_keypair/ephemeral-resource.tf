@@ -0,0 +1,28 @@
+# Generate a WireGuard key pair without writing it to state.
+ephemeral "unifi_wireguard_keypair" "client" {}
+
+# Feed the private key to a write-only attribute so it never lands in state.
+# A new key pair is generated on every run, so bump private_key_wo_version
+# only when you intend to rotate the key (and register the new public key
+# with the remote peer).
+resource "unifi_vpn_client" "office" {
+  name    = "office-wireguard"
+  enabled = true
+  subnet  = "10.0.0.2/24"
+
+  wireguard = {
+    private_key_wo         = ephemeral.unifi_wireguard_keypair.client.private_key
+    private_key_wo_version = 1
+    interface              = "wan"
+
+    peer = {
+      ip         = "203.0.113.1"
+      port       = 51820
+      public_key = var.server_public_key
+    }
+  }
+}
+
+variable "server_public_key" {
+  type = string
+}
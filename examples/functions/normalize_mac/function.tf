@@ -0,0 +1,5 @@
+# Accepts colon, hyphen, Cisco dot and bare hex notation.
+resource "unifi_client" "printer" {
+  mac  = provider::unifi::normalize_mac("AABB.CCDD.EEFF") # "aa:bb:cc:dd:ee:ff"
+  name = "Printer"
+}
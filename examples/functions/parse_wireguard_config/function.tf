@@ -0,0 +1,7 @@
+locals {
+  upstream = provider::unifi::parse_wireguard_config(file("${path.module}/upstream.conf"))
+}
+
+output "upstream_endpoint" {
+  value = "${local.upstream.endpoint_ip}:${local.upstream.endpoint_port}"
+}
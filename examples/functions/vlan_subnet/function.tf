@@ -0,0 +1,16 @@
+variable "vlans" {
+  type = map(number)
+  default = {
+    iot    = 20
+    guests = 30
+  }
+}
+
+resource "unifi_network" "vlan" {
+  for_each = var.vlans
+
+  name    = each.key
+  purpose = "corporate"
+  vlan    = each.value
+  subnet  = provider::unifi::vlan_subnet("10.0.0.0/16", each.value, 24) # e.g. "10.0.20.1/24"
+}
@@ -0,0 +1,37 @@
+resource "unifi_vpn_server" "wireguard" {
+  name   = "WireGuard"
+  subnet = "10.100.0.1/24"
+
+  dns = {
+    servers = ["10.100.0.1"]
+  }
+
+  wireguard = {}
+}
+
+variable "laptop_private_key" {
+  type      = string
+  sensitive = true
+}
+
+variable "laptop_public_key" {
+  type = string
+}
+
+resource "unifi_wireguard_peer" "laptop" {
+  network_id   = unifi_vpn_server.wireguard.id
+  name         = "laptop"
+  interface_ip = "10.100.0.5"
+  public_key   = var.laptop_public_key
+}
+
+output "laptop_config" {
+  sensitive = true
+  value = provider::unifi::wireguard_client_config(
+    unifi_vpn_server.wireguard,
+    unifi_wireguard_peer.laptop,
+    var.laptop_private_key,
+    "vpn.example.com",
+    ["0.0.0.0/0"],
+  )
+}
@@ -0,0 +1,25 @@
+# List all voucher batches in the default site
+list "unifi_hotspot_vouchers" "all" {
+  provider = unifi
+}
+
+# List voucher batches in a specific site
+list "unifi_hotspot_vouchers" "site_vouchers" {
+  provider = unifi
+
+  config {
+    site = "my-site"
+  }
+}
+
+# List voucher batches by note
+list "unifi_hotspot_vouchers" "front_desk" {
+  provider = unifi
+
+  config {
+    filter {
+      name  = "note"
+      value = "Front desk"
+    }
+  }
+}
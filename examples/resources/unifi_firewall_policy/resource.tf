@@ -35,7 +35,7 @@ resource "unifi_network" "iot" {
   }
 }
 
-# Zone-based firewall zones (UniFi OS 8.x+) grouping the networks above.
+# Zone-based firewall zones (UniFi Network 9.0+) grouping the networks above.
 resource "unifi_firewall_zone" "lan" {
   name        = "LAN"
   network_ids = [unifi_network.lan.id]
@@ -1,4 +1,4 @@
-# Zone-based firewall zones (UniFi OS 8.x+) group one or more networks.
+# Zone-based firewall zones (UniFi Network 9.0+) group one or more networks.
 # Reference a zone's id from unifi_firewall_policy source/destination zone_id.
 
 # Networks to place into the zones below.
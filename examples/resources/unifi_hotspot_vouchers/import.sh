@@ -0,0 +1,5 @@
+# Voucher batches can be imported using the batch's create time in Unix seconds.
+terraform import unifi_hotspot_vouchers.example 1760000000
+
+# For a non-default site, prefix the ID with the site name and a colon.
+terraform import unifi_hotspot_vouchers.example default:1760000000
@@ -0,0 +1,13 @@
+# A stack of single-use, one-day vouchers for the front desk.
+resource "unifi_hotspot_vouchers" "front_desk" {
+  quantity       = 50
+  expire_minutes = 1440
+  download_kbps  = 20000
+  upload_kbps    = 5000
+  note           = "Front desk"
+}
+
+output "front_desk_voucher_codes" {
+  value     = unifi_hotspot_vouchers.front_desk.codes
+  sensitive = true
+}
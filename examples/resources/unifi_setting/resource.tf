@@ -55,3 +55,29 @@ resource "unifi_setting" "radius_only" {
     auth_port          = 1812
   }
 }
+
+# Configure the guest portal of a hotel site
+resource "unifi_setting" "hotel_portal" {
+  site = "hotel"
+
+  guest_access = {
+    auth             = "hotspot"
+    portal_enabled   = true
+    voucher_enabled  = true
+    password_enabled = false
+    redirect_url     = "https://hotel.example.com/welcome"
+    terms_of_service = "Access is provided for guests of the hotel only."
+    portal_hostname  = "wifi.hotel.example.com"
+
+    # The booking site stays reachable before guests sign in.
+    allowed_subnets = ["203.0.113.0/24"]
+
+    customization = {
+      enabled          = true
+      title            = "Hotel Wi-Fi"
+      welcome_text     = "Enter the voucher code from your key card."
+      background_color = "#1d3557"
+      button_color     = "#e63946"
+    }
+  }
+}
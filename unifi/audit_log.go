@@ -0,0 +1,308 @@
+package unifi
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// auditRedacted replaces masked values, as tflog does.
+const auditRedacted = "***"
+
+// auditEntry is one line of the audit log.
+//
+// Entries are chained: PrevSHA256 is the SHA-256 of the previous line, so
+// editing or removing a line breaks the chain for every line after it.
+type auditEntry struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	Site         string          `json:"site,omitempty"`
+	ResourceType string          `json:"resource_type,omitempty"`
+	ObjectID     string          `json:"object_id,omitempty"`
+	Method       string          `json:"method"`
+	Endpoint     string          `json:"endpoint"`
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	Status       int             `json:"status,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	PrevSHA256   string          `json:"prev_sha256"`
+}
+
+// auditLog appends entries to a JSONL file.
+type auditLog struct {
+	path string
+
+	mu sync.Mutex
+	// prev is the hex SHA-256 of the last line in the file.
+	prev string
+}
+
+var (
+	auditLogsMu sync.Mutex
+	// auditLogs is keyed by absolute path, so provider configurations that
+	// share a file also share its hash chain.
+	auditLogs = map[string]*auditLog{}
+)
+
+// openAuditLog returns the audit log at path, creating the file if needed
+// and continuing the hash chain of an existing one.
+func openAuditLog(path string) (*auditLog, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogsMu.Lock()
+	defer auditLogsMu.Unlock()
+
+	if l, ok := auditLogs[abs]; ok {
+		return l, nil
+	}
+
+	f, err := os.OpenFile(abs, os.O_RDONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	l := &auditLog{path: abs}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			l.prev = auditLineHash(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", abs, err)
+	}
+
+	auditLogs[abs] = l
+	return l, nil
+}
+
+func (l *auditLog) write(e auditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.PrevSHA256 = l.prev
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	l.prev = auditLineHash(line)
+	return nil
+}
+
+func auditLineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// auditTransport records every request that can change the controller.
+type auditTransport struct {
+	next http.RoundTripper
+	log  *auditLog
+	now  func() time.Time
+}
+
+func newAuditTransport(next http.RoundTripper, log *auditLog) http.RoundTripper {
+	if log == nil {
+		return next
+	}
+	return &auditTransport{next: next, log: log, now: time.Now}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if readOnlyAllowed(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	site, resourceType, objectID := auditTarget(req.URL.Path)
+	entry := auditEntry{
+		Timestamp:    t.now().UTC(),
+		Site:         site,
+		ResourceType: resourceType,
+		ObjectID:     objectID,
+		Method:       req.Method,
+		Endpoint:     req.URL.Path,
+		RequestBody:  redactJSON(body),
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		if entry.ObjectID == "" {
+			var respBody []byte
+			respBody, resp.Body, err = peekBody(resp.Body)
+			if err != nil {
+				// The controller has received the request, so it is still
+				// recorded, without the ID of the object it created.
+				entry.Error = "reading response: " + err.Error()
+				resp = nil
+			} else {
+				entry.ObjectID = auditObjectID(respBody, body)
+			}
+		}
+	}
+
+	// The change has been made either way; failing the request now would
+	// make Terraform lose track of it, so a write error is only logged.
+	if werr := t.log.write(entry); werr != nil {
+		tflog.Error(req.Context(), "Unable to write UniFi audit log entry", map[string]any{
+			"path":  t.log.path,
+			"error": werr.Error(),
+		})
+	}
+
+	return resp, err
+}
+
+// peekBody reads body and returns its contents along with a replacement.
+func peekBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, io.NopCloser(bytes.NewReader(b)), nil
+}
+
+// auditTarget returns the site, the collection and, when the path names one,
+// the ID of the object a request is for.
+func auditTarget(path string) (string, string, string) {
+	site, collection := cacheCollection(path)
+
+	var rest []string
+	switch {
+	case strings.Contains(path, "/api/s/"):
+		// {site}/{kind}/{name}/{id}
+		rest = strings.Split(strings.Trim(path[strings.Index(path, "/api/s/")+len("/api/s/"):], "/"), "/")
+		if len(rest) >= 3 && collection == "" {
+			collection = rest[1] + "/" + rest[2]
+		}
+		rest = rest[min(len(rest), 3):]
+	case strings.Contains(path, "/v2/api/site/"):
+		// {site}/{collection}/{id}
+		rest = strings.Split(strings.Trim(path[strings.Index(path, "/v2/api/site/")+len("/v2/api/site/"):], "/"), "/")
+		rest = rest[min(len(rest), 2):]
+	}
+
+	var id string
+	if len(rest) > 0 {
+		id = rest[len(rest)-1]
+	}
+	return site, collection, id
+}
+
+// auditObjectID finds the ID of the object a request created or acted on in
+// the response, or failing that in the request body.
+func auditObjectID(respBody, reqBody []byte) string {
+	var v1 struct {
+		Data []map[string]any `json:"data"`
+	}
+	if json.Unmarshal(respBody, &v1) == nil && len(v1.Data) > 0 {
+		if id, ok := v1.Data[0]["_id"].(string); ok {
+			return id
+		}
+	}
+
+	for _, b := range [][]byte{respBody, reqBody} {
+		var obj map[string]any
+		if json.Unmarshal(b, &obj) != nil {
+			continue
+		}
+		for _, key := range []string{"_id", "mac"} {
+			if id, ok := obj[key].(string); ok && id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// redactJSON masks the values of maskedFieldKeys, and of the `x_`-prefixed
+// fields the controller uses for secrets such as `x_passphrase`, anywhere in a
+// JSON body. A body that is not JSON is replaced by its length.
+func redactJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		b, _ := json.Marshal(fmt.Sprintf("%d bytes, not JSON", len(body)))
+		return b
+	}
+	redactValue(v)
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func redactValue(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if isMaskedKey(k) {
+				v[k] = auditRedacted
+				continue
+			}
+			redactValue(x)
+		}
+	case []any:
+		for _, x := range v {
+			redactValue(x)
+		}
+	}
+}
+
+func isMaskedKey(key string) bool {
+	key = strings.ToLower(key)
+	return slices.Contains(maskedFieldKeys, key) || strings.HasPrefix(key, "x_")
+}
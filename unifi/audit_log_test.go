@@ -0,0 +1,203 @@
+package unifi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func readAuditLog(t *testing.T, path string) ([]auditEntry, []string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []auditEntry
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return entries, lines
+}
+
+func Test_redactJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"no secrets", `{"name":"iot","vlan":20}`, `{"name":"iot","vlan":20}`},
+		{
+			"masked keys",
+			`{"name":"guest","Password":"p","x_passphrase":"s","nested":[{"api_key":"k","ok":1}]}`,
+			`{"Password":"***","name":"guest","nested":[{"api_key":"***","ok":1}],"x_passphrase":"***"}`,
+		},
+		{"not json", "user=admin&password=secret", `"26 bytes, not JSON"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactJSON([]byte(tt.in))); got != tt.want {
+				t.Errorf("redactJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_auditTarget(t *testing.T) {
+	tests := []struct {
+		path               string
+		site, resource, id string
+	}{
+		{"/api/s/default/rest/networkconf", "default", "networkconf", ""},
+		{"/proxy/network/api/s/default/rest/networkconf/abc", "default", "networkconf", "abc"},
+		{"/api/s/lab/set/setting/mgmt/abc", "lab", "setting", "abc"},
+		{"/api/s/default/cmd/devmgr", "default", "device", ""},
+		{"/api/s/default/cmd/sitemgr", "default", "cmd/sitemgr", ""},
+		{"/v2/api/site/default/trafficroutes/abc", "default", "v2/trafficroutes", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			site, resource, id := auditTarget(tt.path)
+			if site != tt.site || resource != tt.resource || id != tt.id {
+				t.Errorf("auditTarget() = %q, %q, %q, want %q, %q, %q", site, resource, id, tt.site, tt.resource, tt.id)
+			}
+		})
+	}
+}
+
+func Test_auditTransport(t *testing.T) {
+	srv := fakecontroller.New()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newHTTPClient(transportConfig{AllowInsecure: true, AuditLog: log})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, url, body string) {
+		t.Helper()
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		closeBody(resp)
+	}
+
+	do(http.MethodPost, srv.URL+"/api/login", `{"username":"admin","password":"admin"}`)
+	do(http.MethodPost, srv.URL+"/api/s/default/rest/wlanconf", `{"name":"guest","x_passphrase":"hunter22"}`)
+	created := srv.List("default", "wlanconf")
+	if len(created) != 1 {
+		t.Fatalf("wlanconf = %v, want one object", created)
+	}
+	id, _ := created[0]["_id"].(string)
+	do(http.MethodGet, srv.URL+"/api/s/default/rest/wlanconf", "")
+	do(http.MethodPost, srv.URL+"/api/s/default/stat/report/archive.speedtest", `{"attrs":["time"]}`)
+	do(http.MethodPost, srv.URL+"/api/s/default/cmd/devmgr", `{"cmd":"speedtest-status"}`)
+	do(http.MethodPut, srv.URL+"/api/s/default/rest/wlanconf/"+id, `{"name":"guests"}`)
+	do(http.MethodDelete, srv.URL+"/api/s/default/rest/wlanconf/"+id, "")
+
+	entries, lines := readAuditLog(t, path)
+	if len(entries) != 3 {
+		t.Fatalf("audit log has %d entries, want 3:\n%s", len(entries), strings.Join(lines, "\n"))
+	}
+
+	for i, want := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		e := entries[i]
+		if e.Method != want || e.Site != "default" || e.ResourceType != "wlanconf" || e.ObjectID != id {
+			t.Errorf("entry %d = %+v, want %s of wlanconf %s in default", i, e, want, id)
+		}
+		if e.Status != http.StatusOK {
+			t.Errorf("entry %d status = %d", i, e.Status)
+		}
+		if e.Timestamp.IsZero() {
+			t.Errorf("entry %d has no timestamp", i)
+		}
+	}
+	if strings.Contains(lines[0], "hunter22") || !strings.Contains(lines[0], `"x_passphrase":"***"`) {
+		t.Errorf("passphrase not redacted: %s", lines[0])
+	}
+
+	if entries[0].PrevSHA256 != "" {
+		t.Errorf("first entry prev_sha256 = %q, want empty", entries[0].PrevSHA256)
+	}
+	for i := 1; i < len(entries); i++ {
+		if got, want := entries[i].PrevSHA256, auditLineHash([]byte(lines[i-1])); got != want {
+			t.Errorf("entry %d prev_sha256 = %q, want %q", i, got, want)
+		}
+	}
+
+	t.Run("reopened file continues the chain", func(t *testing.T) {
+		auditLogsMu.Lock()
+		delete(auditLogs, path)
+		auditLogsMu.Unlock()
+
+		reopened, err := openAuditLog(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := auditLineHash([]byte(lines[len(lines)-1])); reopened.prev != want {
+			t.Errorf("prev = %q, want %q", reopened.prev, want)
+		}
+	})
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func Test_auditTransport_unreadableResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := newAuditTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(io.MultiReader(strings.NewReader(`{"data":[`), errReader{errors.New("connection reset")})),
+		}, nil
+	}), log)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://unifi.example/api/s/default/rest/networkconf", strings.NewReader(`{"name":"iot"}`))
+	if _, err := tr.RoundTrip(req); err == nil {
+		t.Fatal("expected the body read error")
+	}
+
+	entries, _ := readAuditLog(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("audit log has %d entries, want 1", len(entries))
+	}
+	if e := entries[0]; e.Status != http.StatusOK || !strings.Contains(e.Error, "connection reset") {
+		t.Errorf("entry = %+v, want status 200 and the read error", e)
+	}
+}
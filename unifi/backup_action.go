@@ -0,0 +1,230 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action              = &backupAction{}
+	_ action.ActionWithConfigure = &backupAction{}
+)
+
+// NewBackupAction returns a new instance of the controller backup action.
+func NewBackupAction() action.Action {
+	return &backupAction{}
+}
+
+type backupAction struct {
+	client *Client
+}
+
+// backupActionModel describes the action request data model.
+type backupActionModel struct {
+	Path      types.String   `tfsdk:"path"`
+	Days      types.Int64    `tfsdk:"days"`
+	Overwrite types.Bool     `tfsdk:"overwrite"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (a *backupAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_backup"
+}
+
+func (a *backupAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a backup of the controller and downloads the `.unf` file to a local path. " +
+			"The file contains the controller's secrets and is written with mode `0600`. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Local path to write the backup to. Parent directories are created as needed.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"days": schema.Int64Attribute{
+				MarkdownDescription: "Days of statistics history to include. `0` backs up the settings only, " +
+					"`-1` includes all history. Defaults to `0`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(-1),
+				},
+			},
+			"overwrite": schema.BoolAttribute{
+				MarkdownDescription: "Replace the file at `path` if it exists. Otherwise the action fails " +
+					"before generating a backup. Defaults to `false`.",
+				Optional: true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *backupAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *backupAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_backup")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config backupActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	dest := config.Path.ValueString()
+	if !config.Overwrite.ValueBool() {
+		if _, err := os.Stat(dest); err == nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("path"),
+				"Backup File Exists",
+				fmt.Sprintf("%s already exists. Set overwrite = true to replace it.", dest),
+			)
+			return
+		}
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Generating controller backup"})
+
+	src, err := a.client.generateBackup(ctx, a.client.Site, config.Days.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Generating Backup", err.Error())
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Downloading " + src})
+
+	n, err := a.client.downloadBackup(ctx, src, dest)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Downloading Backup", err.Error())
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Wrote %d bytes to %s", n, dest),
+	})
+	tflog.Info(ctx, "Controller backup written", map[string]any{
+		"path":  dest,
+		"bytes": n,
+	})
+}
+
+// generateBackup asks the controller to generate a backup with days of
+// history and returns the path it can be downloaded from, relative to the
+// API base.
+func (c *Client) generateBackup(ctx context.Context, site string, days int64) (string, error) {
+	var out []struct {
+		URL string `json:"url"`
+	}
+	if err := c.executeCommand(ctx, site, "backup", map[string]any{"cmd": "backup", "days": days}, &out); err != nil {
+		return "", err
+	}
+	if len(out) == 0 || out[0].URL == "" {
+		return "", errors.New("the controller did not return a download URL for the backup")
+	}
+	return out[0].URL, nil
+}
+
+// downloadBackup downloads the backup at src to dest, returning the number of
+// bytes written. The file is written next to dest and renamed into place, so
+// an interrupted download never leaves a truncated backup at dest.
+func (c *Client) downloadBackup(ctx context.Context, src, dest string) (int64, error) {
+	if c.controller.APIBaseURL == "" || c.httpClient == nil {
+		return 0, c.controllerURLError()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.controller.APIBaseURL+"/"+strings.TrimPrefix(src, "/"), nil)
+	if err != nil {
+		return 0, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-KEY", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("GET %s returned %s", src, resp.Status)
+	}
+
+	tmp, n, err := stagePrivateFileFrom(dest, resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	// Once renamed, there is nothing left to remove.
+	defer os.Remove(tmp)
+
+	if n == 0 {
+		return 0, fmt.Errorf("GET %s returned an empty backup", src)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
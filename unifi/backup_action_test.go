@@ -0,0 +1,143 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+func TestNewBackupAction(t *testing.T) {
+	got := NewBackupAction()
+	if got == nil {
+		t.Fatal("NewBackupAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_backupAction_Metadata(t *testing.T) {
+	a := &backupAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_backup" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_backup")
+	}
+}
+
+func Test_backupAction_Schema(t *testing.T) {
+	a := &backupAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"path", "days", "overwrite", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	if !resp.Schema.Attributes["path"].IsRequired() {
+		t.Error("path should be required")
+	}
+}
+
+func Test_backupAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &backupAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestClient_backup(t *testing.T) {
+	backup := []byte("not really a .unf file")
+	var days any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/proxy/network/api/s/default/cmd/backup":
+			var cmd map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&cmd)
+			days = cmd["days"]
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"meta": map[string]any{"rc": "ok"},
+				"data": []map[string]any{{"url": "/dl/backup/9.0.114.unf"}},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/proxy/network/dl/backup/9.0.114.unf":
+			_, _ = w.Write(backup)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL + "/proxy/network"},
+		httpClient: httpClient,
+	}
+
+	src, err := c.generateBackup(context.Background(), "default", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days != float64(7) {
+		t.Errorf("days = %v, want 7", days)
+	}
+
+	dest := filepath.Join(t.TempDir(), "backups", "pre-apply.unf")
+	n, err := c.downloadBackup(context.Background(), src, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(backup)) {
+		t.Errorf("downloadBackup() = %d bytes, want %d", n, len(backup))
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(backup) {
+		t.Errorf("backup = %q, want %q", got, backup)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %v, want 0600", perm)
+	}
+	entries, err := os.ReadDir(filepath.Dir(dest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the backup", len(entries))
+	}
+
+	if _, err := c.downloadBackup(context.Background(), "/dl/backup/missing.unf", dest); err == nil {
+		t.Error("downloadBackup() of a missing backup succeeded, want an error")
+	}
+}
@@ -0,0 +1,202 @@
+package unifi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &backupsDataSource{}
+
+func NewBackupsDataSource() datasource.DataSource {
+	return &backupsDataSource{}
+}
+
+type backupsDataSource struct {
+	client *Client
+}
+
+type backupsDataSourceModel struct {
+	Site     types.String   `tfsdk:"site"`
+	Backups  types.List     `tfsdk:"backups"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// backupAttrTypes returns the attribute types of a backup.
+func backupAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"filename":   types.StringType,
+		"timestamp":  timetypes.RFC3339Type{},
+		"size_bytes": types.Int64Type,
+		"version":    types.StringType,
+		"days":       types.Int64Type,
+	}
+}
+
+func (d *backupsDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_backups"
+}
+
+func (d *backupsDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the autobackups stored on the controller. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site to send the request to. Autobackups cover the " +
+					"whole controller, so this only matters for accounts limited to some sites.",
+				Optional: true,
+				Computed: true,
+			},
+			"backups": schema.ListNestedAttribute{
+				MarkdownDescription: "Autobackups, newest first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"filename": schema.StringAttribute{
+							MarkdownDescription: "The file name of the backup on the controller.",
+							Computed:            true,
+						},
+						"timestamp": schema.StringAttribute{
+							MarkdownDescription: "When the backup was taken, in RFC 3339 format.",
+							CustomType:          timetypes.RFC3339Type{},
+							Computed:            true,
+						},
+						"size_bytes": schema.Int64Attribute{
+							MarkdownDescription: "The size of the backup in bytes.",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "The Network Application version that took the backup.",
+							Computed:            true,
+						},
+						"days": schema.Int64Attribute{
+							MarkdownDescription: "Days of statistics history in the backup; `0` for settings " +
+								"only, `-1` for all history.",
+							Computed: true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *backupsDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	if client, ok := req.ProviderData.(*Client); ok {
+		d.client = client
+	} else {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+	}
+}
+
+func (d *backupsDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data backupsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := data.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = d.client.Site
+	}
+
+	backups, err := d.client.listBackups(ctx, site)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Backups",
+			"Could not list the controller's backups: "+err.Error(),
+		)
+		return
+	}
+
+	objects := make([]attr.Value, len(backups))
+	for i, b := range backups {
+		o, diags := types.ObjectValue(backupAttrTypes(), map[string]attr.Value{
+			"filename":   types.StringValue(b.Filename),
+			"timestamp":  timetypes.NewRFC3339TimeValue(time.UnixMilli(b.Time).UTC()),
+			"size_bytes": types.Int64Value(b.Size),
+			"version":    types.StringValue(b.Version),
+			"days":       types.Int64Value(b.Days),
+		})
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			return
+		}
+		objects[i] = o
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: backupAttrTypes()}, objects)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Backups = list
+	data.Site = types.StringValue(site)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// controllerBackup is an entry of the `list-backups` command.
+type controllerBackup struct {
+	Filename string `json:"filename"`
+	Time     int64  `json:"time"`
+	Size     int64  `json:"size"`
+	Version  string `json:"version"`
+	Days     int64  `json:"days"`
+}
+
+// listBackups returns the controller's autobackups, newest first.
+func (c *Client) listBackups(ctx context.Context, site string) ([]controllerBackup, error) {
+	var backups []controllerBackup
+	if err := c.executeCommand(ctx, site, "backup", map[string]any{"cmd": "list-backups"}, &backups); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(backups, func(a, b controllerBackup) int { return cmp.Compare(b.Time, a.Time) })
+	return backups, nil
+}
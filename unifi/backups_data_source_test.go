@@ -0,0 +1,138 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBackupsDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackupsDataSourceConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"data.unifi_backups.test",
+						"backups.#",
+					),
+					resource.TestCheckResourceAttr(
+						"data.unifi_backups.test",
+						"site",
+						"default",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccBackupsDataSourceConfig_basic() string {
+	return `
+data "unifi_backups" "test" {}
+`
+}
+
+func TestNewBackupsDataSource(t *testing.T) {
+	d := NewBackupsDataSource()
+	if d == nil {
+		t.Fatal("NewBackupsDataSource() returned nil")
+	}
+}
+
+func Test_backupsDataSource_Metadata(t *testing.T) {
+	d := &backupsDataSource{}
+	resp := &fwdatasource.MetadataResponse{}
+	d.Metadata(context.Background(), fwdatasource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_backups" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_backups")
+	}
+}
+
+func Test_backupsDataSource_Schema(t *testing.T) {
+	d := &backupsDataSource{}
+	resp := &fwdatasource.SchemaResponse{}
+	d.Schema(context.Background(), fwdatasource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("Schema() produced errors: %v", resp.Diagnostics)
+	}
+	for _, attr := range []string{"site", "backups", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("missing attribute %q", attr)
+		}
+	}
+}
+
+func Test_backupsDataSource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      any
+		wantError bool
+	}{
+		{"nil provider data", nil, false},
+		{"wrong type", "wrong", true},
+		{"correct client type", &Client{Site: "default"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &backupsDataSource{}
+			resp := &fwdatasource.ConfigureResponse{}
+			d.Configure(
+				context.Background(),
+				fwdatasource.ConfigureRequest{ProviderData: tt.data},
+				resp,
+			)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v (diags: %v)", resp.Diagnostics.HasError(), tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestClient_listBackups(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmd map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&cmd)
+		if r.URL.Path != "/api/s/default/cmd/backup" || cmd["cmd"] != "list-backups" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"meta": map[string]any{"rc": "ok"},
+			"data": []map[string]any{
+				{"filename": "autobackup_9.0.114_20260101_0000_1000.unf", "time": 1000, "size": 2048, "version": "9.0.114", "days": 30},
+				{"filename": "autobackup_9.0.114_20260103_0000_3000.unf", "time": 3000, "size": 4096, "version": "9.0.114", "days": 30},
+				{"filename": "autobackup_9.0.108_20260102_0000_2000.unf", "time": 2000, "size": 3072, "version": "9.0.108", "days": 0},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+
+	backups, err := c.listBackups(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("listBackups() in read-only mode: %v", err)
+	}
+	if len(backups) != 3 || backups[0].Time != 3000 || backups[2].Time != 1000 {
+		t.Fatalf("backups = %+v, want newest first", backups)
+	}
+	if backups[0].Size != 4096 || backups[1].Version != "9.0.108" {
+		t.Errorf("backups = %+v, want sizes and versions decoded", backups)
+	}
+}
@@ -102,8 +102,13 @@ type frrNeighborData struct {
 var (
 	_ resource.Resource                = &bgpResource{}
 	_ resource.ResourceWithImportState = &bgpResource{}
+	_ resource.ResourceWithModifyPlan  = &bgpResource{}
 )
 
+// bgpMinimumVersion is the oldest Network Application release that accepts an
+// uploaded FRR BGP configuration.
+const bgpMinimumVersion = "8.0.0"
+
 func NewBGPResource() resource.Resource {
 	return &bgpResource{}
 }
@@ -290,6 +295,18 @@ func (r *bgpResource) Configure(
 	r.client = client
 }
 
+func (r *bgpResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if req.Plan.Raw.IsNull() {
+		return // resource is being destroyed
+	}
+
+	resp.Diagnostics.Append(r.client.checkMinimumVersion("unifi_bgp", bgpMinimumVersion)...)
+}
+
 func (r *bgpResource) Create(
 	ctx context.Context,
 	req resource.CreateRequest,
@@ -16,6 +16,8 @@
 )
 
 func TestAccBGPConfig_basic(t *testing.T) {
+	skipOnFakeController(t, "expects the controller to reject BGP without a UniFi gateway; the fake accepts any payload")
+
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:                 func() { preCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -37,6 +39,8 @@ func TestAccBGPConfig_basic(t *testing.T) {
 `
 
 func TestAccBGPConfig_structured(t *testing.T) {
+	skipOnFakeController(t, "expects the controller to reject BGP without a UniFi gateway; the fake accepts any payload")
+
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:                 func() { preCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
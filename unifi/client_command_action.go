@@ -0,0 +1,260 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action                   = &clientCommandAction{}
+	_ action.ActionWithConfigure      = &clientCommandAction{}
+	_ action.ActionWithValidateConfig = &clientCommandAction{}
+)
+
+// clientCommands maps the action's commands to the controller's `stamgr`
+// commands.
+var clientCommands = map[string]string{
+	"kick":              "kick-sta",
+	"block":             "block-sta",
+	"unblock":           "unblock-sta",
+	"forget":            "forget-sta",
+	"authorize_guest":   "authorize-guest",
+	"unauthorize_guest": "unauthorize-guest",
+}
+
+// NewClientCommandAction returns a new instance of the client command action.
+func NewClientCommandAction() action.Action {
+	return &clientCommandAction{}
+}
+
+type clientCommandAction struct {
+	client *Client
+}
+
+// clientCommandActionModel describes the action request data model.
+type clientCommandActionModel struct {
+	MAC          hwtypes.MACAddress `tfsdk:"mac"`
+	Command      types.String       `tfsdk:"command"`
+	Minutes      types.Int64        `tfsdk:"minutes"`
+	UploadKbps   types.Int64        `tfsdk:"upload_kbps"`
+	DownloadKbps types.Int64        `tfsdk:"download_kbps"`
+	DataLimitMB  types.Int64        `tfsdk:"data_limit_mb"`
+	Timeouts     timeouts.Value     `tfsdk:"timeouts"`
+}
+
+func (a *clientCommandAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_client_command"
+}
+
+func (a *clientCommandAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sends a one-off command for a client, such as disconnecting it or authorizing " +
+			"it on the guest portal. Use the `blocked` attribute of `unifi_client` instead to keep a " +
+			"client blocked as part of the desired state. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"mac": schema.StringAttribute{
+				MarkdownDescription: "The MAC address of the client.",
+				CustomType:          hwtypes.MACAddressType{},
+				Required:            true,
+			},
+			"command": schema.StringAttribute{
+				MarkdownDescription: "The command to send. `kick` disconnects the client so that it has to " +
+					"reconnect; `block` and `unblock` block it from or readmit it to the network; `forget` " +
+					"removes the client and its history from the controller; `authorize_guest` and " +
+					"`unauthorize_guest` grant or revoke guest portal access.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						"kick", "block", "unblock", "forget", "authorize_guest", "unauthorize_guest",
+					),
+				},
+			},
+			"minutes": schema.Int64Attribute{
+				MarkdownDescription: "How long the guest is authorized for, in minutes. Only valid with " +
+					"`authorize_guest`. Defaults to the guest portal's setting.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"upload_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Upload bandwidth limit for the guest, in kbps. Only valid with " +
+					"`authorize_guest`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"download_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Download bandwidth limit for the guest, in kbps. Only valid with " +
+					"`authorize_guest`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"data_limit_mb": schema.Int64Attribute{
+				MarkdownDescription: "Total data the guest may transfer, in megabytes. Only valid with " +
+					"`authorize_guest`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *clientCommandAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *clientCommandAction) ValidateConfig(
+	ctx context.Context,
+	req action.ValidateConfigRequest,
+	resp *action.ValidateConfigResponse,
+) {
+	var config clientCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Command.IsUnknown() || config.Command.ValueString() == "authorize_guest" {
+		return
+	}
+	guestAttributes := []struct {
+		name  string
+		value types.Int64
+	}{
+		{"minutes", config.Minutes},
+		{"upload_kbps", config.UploadKbps},
+		{"download_kbps", config.DownloadKbps},
+		{"data_limit_mb", config.DataLimitMB},
+	}
+	for _, attr := range guestAttributes {
+		if !attr.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Invalid Attribute Combination",
+				fmt.Sprintf(
+					"%q can only be set with the authorize_guest command, not %s.",
+					attr.name,
+					config.Command.ValueString(),
+				),
+			)
+		}
+	}
+}
+
+func (a *clientCommandAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_client_command")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config clientCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 2*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	mac, err := normalizeMAC(config.MAC.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Client MAC Address", err.Error())
+		return
+	}
+
+	command := config.Command.ValueString()
+	err = a.client.executeCommand(ctx, a.client.Site, "stamgr", clientCommand(mac, &config), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Sending Client Command",
+			fmt.Sprintf("Could not send %s for client %s: %s", command, mac, err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Client command sent", map[string]any{
+		"mac":     mac,
+		"command": command,
+	})
+}
+
+// clientCommand builds the `stamgr` command for config.
+func clientCommand(mac string, config *clientCommandActionModel) map[string]any {
+	cmd := map[string]any{"cmd": clientCommands[config.Command.ValueString()]}
+	if config.Command.ValueString() == "forget" {
+		cmd["macs"] = []string{mac}
+		return cmd
+	}
+	cmd["mac"] = mac
+
+	for key, v := range map[string]types.Int64{
+		"minutes": config.Minutes,
+		"up":      config.UploadKbps,
+		"down":    config.DownloadKbps,
+		"bytes":   config.DataLimitMB,
+	} {
+		if !v.IsNull() {
+			cmd[key] = v.ValueInt64()
+		}
+	}
+	return cmd
+}
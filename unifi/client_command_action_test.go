@@ -0,0 +1,161 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func TestNewClientCommandAction(t *testing.T) {
+	got := NewClientCommandAction()
+	if got == nil {
+		t.Fatal("NewClientCommandAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+	if _, ok := got.(fwaction.ActionWithValidateConfig); !ok {
+		t.Error("expected ActionWithValidateConfig interface")
+	}
+}
+
+func Test_clientCommandAction_Metadata(t *testing.T) {
+	a := &clientCommandAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_client_command" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_client_command")
+	}
+}
+
+func Test_clientCommandAction_Schema(t *testing.T) {
+	a := &clientCommandAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"mac", "command", "minutes", "upload_kbps", "download_kbps", "data_limit_mb", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_clientCommandAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &clientCommandAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+// clientCommandConfig builds an action config from values, leaving every
+// other attribute null.
+func clientCommandConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	resp := &fwaction.SchemaResponse{}
+	(&clientCommandAction{}).Schema(ctx, fwaction.SchemaRequest{}, resp)
+	typ, ok := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("schema type is not an object")
+	}
+	attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return tfsdk.Config{Schema: resp.Schema, Raw: tftypes.NewValue(typ, attrs)}
+}
+
+func Test_clientCommandAction_ValidateConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   tftypes.Value
+		minutes   tftypes.Value
+		wantError bool
+	}{
+		{"kick", tftypes.NewValue(tftypes.String, "kick"), tftypes.NewValue(tftypes.Number, nil), false},
+		{"guest_with_minutes", tftypes.NewValue(tftypes.String, "authorize_guest"), tftypes.NewValue(tftypes.Number, 60), false},
+		{"block_with_minutes", tftypes.NewValue(tftypes.String, "block"), tftypes.NewValue(tftypes.Number, 60), true},
+		{"unknown_command", tftypes.NewValue(tftypes.String, tftypes.UnknownValue), tftypes.NewValue(tftypes.Number, 60), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := clientCommandConfig(t, map[string]tftypes.Value{
+				"mac":     tftypes.NewValue(tftypes.String, "aa:bb:cc:dd:ee:ff"),
+				"command": tt.command,
+				"minutes": tt.minutes,
+			})
+			resp := &fwaction.ValidateConfigResponse{}
+			(&clientCommandAction{}).ValidateConfig(context.Background(), fwaction.ValidateConfigRequest{Config: config}, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v (diags: %v)", resp.Diagnostics.HasError(), tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func Test_clientCommand(t *testing.T) {
+	const mac = "aa:bb:cc:dd:ee:ff"
+
+	got := clientCommand(mac, &clientCommandActionModel{
+		Command:      types.StringValue("authorize_guest"),
+		Minutes:      types.Int64Value(60),
+		UploadKbps:   types.Int64Null(),
+		DownloadKbps: types.Int64Value(5000),
+		DataLimitMB:  types.Int64Null(),
+	})
+	want := map[string]any{"cmd": "authorize-guest", "mac": mac, "minutes": int64(60), "down": int64(5000)}
+	if len(got) != len(want) {
+		t.Errorf("authorize_guest command = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("authorize_guest command[%q] = %v, want %v", k, got[k], v)
+		}
+	}
+
+	got = clientCommand(mac, &clientCommandActionModel{Command: types.StringValue("forget")})
+	if macs, ok := got["macs"].([]string); got["cmd"] != "forget-sta" || !ok || len(macs) != 1 || macs[0] != mac {
+		t.Errorf("forget command = %v, want forget-sta with macs", got)
+	}
+}
+
+func TestClient_clientCommand_forget(t *testing.T) {
+	const mac = "aa:bb:cc:dd:ee:ff"
+	srv := fakecontroller.New(fakecontroller.WithAPIKey("key"))
+	defer srv.Close()
+	srv.Create("default", "user", map[string]any{"mac": mac})
+
+	c := newTestCommandClient(t, srv, "key")
+
+	cmd := clientCommand(mac, &clientCommandActionModel{Command: types.StringValue("forget")})
+	if err := c.executeCommand(context.Background(), "default", "stamgr", cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if users := srv.List("default", "user"); len(users) != 0 {
+		t.Errorf("users after forget = %v, want none", users)
+	}
+}
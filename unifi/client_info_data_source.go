@@ -152,7 +152,8 @@ func (d *clientInfoDataSource) Read(
 		return
 	}
 
-	clientInfo, err := d.client.GetClientInfo(ctx, site, mac)
+	// Client info is live state, so it bypasses the read cache.
+	clientInfo, err := d.client.GetClientInfo(withFreshReads(ctx), site, mac)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Client Info",
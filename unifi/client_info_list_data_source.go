@@ -117,7 +117,8 @@ func (d *clientInfoListDataSource) Read(
 		site = d.client.Site
 	}
 
-	clientInfoList, err := d.client.ListClientInfo(ctx, site)
+	// Client info is live state, so it bypasses the read cache.
+	clientInfoList, err := d.client.ListClientInfo(withFreshReads(ctx), site)
 	if err != nil {
 		var notFoundErr *gounifi.NotFoundError
 		if errors.As(err, &notFoundErr) && !siteExplicitlySet {
@@ -453,7 +453,7 @@ func (d *clientListDataSource) Read(
 	// Build lookup maps keyed by user_id (which maps to Client.ID).
 	infoByUserID := make(map[string]*gounifi.ClientInfo)
 
-	activeClients, err := d.client.ListClientInfo(ctx, site)
+	activeClients, err := d.client.ListClientInfo(withFreshReads(ctx), site)
 	if err != nil {
 		resp.Diagnostics.AddWarning(
 			"Unable to Fetch Active Client Info",
@@ -0,0 +1,113 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// errNoControllerURL is returned for requests the provider sends itself when
+// the controller's API address is unknown, as with Cloud Connector.
+var errNoControllerURL = errors.New(
+	"the controller's API address is unknown; this operation is not supported with cloud_connector",
+)
+
+// directConnectionDescription ends the description of the actions, data
+// sources and resources built on controllerRequest.
+const directConnectionDescription = "Requires a direct connection to the controller; not supported with " +
+	"`cloud_connector`."
+
+// controllerURLError explains why requests the provider sends itself cannot
+// be made: detecting the controller failed in Configure, or it is reached
+// through Cloud Connector.
+func (c *Client) controllerURLError() error {
+	if c.controllerErr != nil {
+		return fmt.Errorf("the controller's API address could not be detected: %w", c.controllerErr)
+	}
+	return errNoControllerURL
+}
+
+// controllerRequest sends a request to the Network Application API for the
+// endpoints go-unifi has no method for. path is relative to the API base, e.g.
+// `/api/s/default/cmd/devmgr`. A v1 `meta`/`data` envelope is unwrapped: an
+// `rc` other than `ok` is returned as an error and `data` is decoded into
+// out. Other responses are decoded into out as they are.
+func (c *Client) controllerRequest(ctx context.Context, method, path string, body, out any) error {
+	if c.controller.APIBaseURL == "" || c.httpClient == nil {
+		return c.controllerURLError()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.controller.APIBaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-KEY", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Meta *struct {
+			RC  string `json:"rc"`
+			Msg string `json:"msg"`
+		} `json:"meta"`
+		Data json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(respBody, &envelope) == nil && envelope.Meta != nil {
+		if envelope.Meta.RC != "ok" {
+			msg := envelope.Meta.Msg
+			if msg == "" {
+				msg = resp.Status
+			}
+			return fmt.Errorf("%s %s: %s", method, path, msg)
+		}
+		respBody = envelope.Data
+	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s returned %s", method, path, resp.Status)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// executeCommand posts cmd to the site's `cmd/{manager}` endpoint, decoding
+// the response data into out if it is not nil.
+func (c *Client) executeCommand(ctx context.Context, site, manager string, cmd, out any) error {
+	return c.controllerRequest(
+		ctx,
+		http.MethodPost,
+		"/api/s/"+url.PathEscape(site)+"/cmd/"+url.PathEscape(manager),
+		cmd,
+		out,
+	)
+}
@@ -0,0 +1,99 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func newTestCommandClient(t *testing.T, srv *fakecontroller.Server, apiKey string) *Client {
+	t.Helper()
+	c, err := newHTTPClient(transportConfig{AllowInsecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: c,
+		apiKey:     apiKey,
+	}
+}
+
+func TestClient_executeCommand(t *testing.T) {
+	srv := fakecontroller.New(fakecontroller.WithAPIKey("key"))
+	defer srv.Close()
+
+	c := newTestCommandClient(t, srv, "key")
+	err := c.executeCommand(context.Background(), "default", "devmgr", map[string]any{
+		"cmd": "restart",
+		"mac": "aa:bb:cc:dd:ee:ff",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmds := srv.Commands()
+	if len(cmds) != 1 || cmds[0].Manager != "devmgr" || cmds[0].Body["cmd"] != "restart" {
+		t.Errorf("commands = %+v, want one devmgr restart", cmds)
+	}
+
+	t.Run("controller error", func(t *testing.T) {
+		err := c.executeCommand(context.Background(), "missing", "devmgr", map[string]any{"cmd": "restart"}, nil)
+		if err == nil || !strings.Contains(err.Error(), "api.err.NoSiteContext") {
+			t.Errorf("error = %v, want the controller's message", err)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		c := newTestCommandClient(t, srv, "wrong")
+		if err := c.executeCommand(context.Background(), "default", "devmgr", nil, nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestClient_controllerRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/api/site/default/thing":
+			_, _ = w.Write([]byte(`[{"id":"a"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{controller: controllerInfo{APIBaseURL: srv.URL}, httpClient: httpClient}
+
+	var out []map[string]string
+	if err := c.controllerRequest(context.Background(), http.MethodGet, "/v2/api/site/default/thing", nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0]["id"] != "a" {
+		t.Errorf("out = %v", out)
+	}
+
+	if err := c.controllerRequest(context.Background(), http.MethodGet, "/missing", nil, nil); err == nil {
+		t.Error("expected an error for a 404")
+	}
+
+	if err := (&Client{}).controllerRequest(context.Background(), http.MethodGet, "/", nil, nil); !errors.Is(err, errNoControllerURL) {
+		t.Errorf("error = %v, want %v", err, errNoControllerURL)
+	}
+
+	detectErr := errors.New("connection refused")
+	err := (&Client{controllerErr: detectErr}).controllerRequest(context.Background(), http.MethodGet, "/", nil, nil)
+	if !errors.Is(err, detectErr) || errors.Is(err, errNoControllerURL) {
+		t.Errorf("error = %v, want the detection error %v", err, detectErr)
+	}
+}
@@ -0,0 +1,190 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// consoleType is the kind of controller the provider is connected to.
+type consoleType string
+
+const (
+	// consoleTypeUniFiOS is a UniFi OS console (Dream Machine, Cloud Gateway,
+	// Cloud Key Gen2 or UniFi OS Server), which serves the Network
+	// Application under /proxy/network.
+	consoleTypeUniFiOS consoleType = "unifi_os"
+	// consoleTypeStandalone is a self-hosted Network Application.
+	consoleTypeStandalone consoleType = "standalone"
+	// consoleTypeCloudConnector is any console reached through api.ui.com.
+	consoleTypeCloudConnector consoleType = "cloud_connector"
+)
+
+// controllerInfo describes the controller, as detected once in Configure.
+type controllerInfo struct {
+	// Version is the Network Application version, such as "9.0.114". It is
+	// empty if it could not be detected.
+	Version string
+	Console consoleType
+	// APIBaseURL is the URL the Network Application API paths, such as
+	// /api/s/{site}/..., are relative to.
+	APIBaseURL string
+}
+
+// detectController finds out the console type and Network Application
+// version of the controller at baseURL. Like go-unifi, it tells UniFi OS
+// consoles from standalone controllers by how they answer the root URL, then
+// reads the version from the unauthenticated status endpoint, falling back to
+// the site's sysinfo on versions that no longer report it there.
+func detectController(
+	ctx context.Context,
+	httpClient *http.Client,
+	baseURL, site, apiKey string,
+) (controllerInfo, error) {
+	info := controllerInfo{Console: consoleTypeStandalone}
+
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return info, err
+	}
+
+	noRedirects := *httpClient
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := controllerGet(ctx, &noRedirects, base.String()+"/", apiKey)
+	if err != nil {
+		return info, err
+	}
+	closeBody(resp)
+	prefix := base.String()
+	if resp.StatusCode == http.StatusOK {
+		info.Console = consoleTypeUniFiOS
+		prefix += "/proxy/network"
+	}
+	info.APIBaseURL = prefix
+
+	var status struct {
+		Meta struct {
+			ServerVersion string `json:"server_version"`
+		} `json:"meta"`
+	}
+	if err := controllerGetJSON(ctx, httpClient, prefix+"/status", apiKey, &status); err == nil &&
+		status.Meta.ServerVersion != "" {
+		info.Version = status.Meta.ServerVersion
+		return info, nil
+	}
+
+	var sysinfo struct {
+		Data []struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	err = controllerGetJSON(ctx, httpClient, prefix+"/api/s/"+url.PathEscape(site)+"/stat/sysinfo", apiKey, &sysinfo)
+	if err != nil {
+		return info, err
+	}
+	if len(sysinfo.Data) == 0 || sysinfo.Data[0].Version == "" {
+		return info, errors.New("the controller did not report its version")
+	}
+	info.Version = sysinfo.Data[0].Version
+
+	return info, nil
+}
+
+func controllerGet(ctx context.Context, c *http.Client, u, apiKey string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-API-KEY", apiKey)
+	}
+	return c.Do(req)
+}
+
+func controllerGetJSON(ctx context.Context, c *http.Client, u, apiKey string, v any) error {
+	resp, err := controllerGet(ctx, c, u, apiKey)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", resp.Request.URL.Redacted(), resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// compareVersions compares dotted version numbers such as "9.0.114". Missing
+// components count as 0, and anything after the leading digits of a
+// component, such as "-rc1", is ignored.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		x, y := versionComponent(as, i), versionComponent(bs, i)
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionMatches reports whether version is target or a build of it: every
+// component target gives must match, so "6.6.55" matches "6.6.55.15189" but
+// not "6.6.56".
+func versionMatches(version, target string) bool {
+	vs, ts := strings.Split(version, "."), strings.Split(target, ".")
+	for i := range ts {
+		if versionComponent(vs, i) != versionComponent(ts, i) {
+			return false
+		}
+	}
+	return true
+}
+
+func versionComponent(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	digits := parts[i]
+	if end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+		digits = digits[:end]
+	}
+	n, _ := strconv.Atoi(digits)
+	return n
+}
+
+// checkMinimumVersion reports an error diagnostic if the controller's Network
+// Application is older than minimum. Nothing is reported when the provider is
+// not configured yet or the version is unknown, so detection failures never
+// block a plan.
+func (c *Client) checkMinimumVersion(typeName, minimum string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if c == nil || c.controller.Version == "" {
+		return diags
+	}
+	if compareVersions(c.controller.Version, minimum) < 0 {
+		diags.AddError(
+			"Unsupported Controller Version",
+			fmt.Sprintf(
+				"%s requires UniFi Network Application %s or later, but the controller runs %s. "+
+					"Upgrade the Network Application or remove the resource from the configuration.",
+				typeName, minimum, c.controller.Version,
+			),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,140 @@
+package unifi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func Test_compareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"9.0.114", "9.0.114", 0},
+		{"9.0.114", "9.0.0", 1},
+		{"8.6.9", "9.0.0", -1},
+		{"10.0.1", "9.4.19", 1},
+		{"9.0", "9.0.0", 0},
+		{"9.1.0-rc1", "9.1.0", 0},
+		{"7.3.83", "7.3.0", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_versionMatches(t *testing.T) {
+	tests := []struct {
+		version, target string
+		want            bool
+	}{
+		{"6.6.55.15189", "6.6.55.15189", true},
+		{"6.6.55.15189", "6.6.55", true},
+		{"6.6.55.15189", "6.6", true},
+		{"6.6.55.15189", "6.6.5", false},
+		{"6.6.56.15201", "6.6.55", false},
+		{"6.6.55", "6.6.55.15189", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" vs "+tt.target, func(t *testing.T) {
+			if got := versionMatches(tt.version, tt.target); got != tt.want {
+				t.Errorf("versionMatches(%q, %q) = %v, want %v", tt.version, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_checkMinimumVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  *Client
+		wantErr bool
+	}{
+		{"unconfigured provider", nil, false},
+		{"unknown version", &Client{}, false},
+		{"older controller", &Client{controller: controllerInfo{Version: "7.5.187"}}, true},
+		{"8.x controller", &Client{controller: controllerInfo{Version: "8.6.9"}}, true},
+		{"same version", &Client{controller: controllerInfo{Version: "9.0.0"}}, false},
+		{"newer controller", &Client{controller: controllerInfo{Version: "9.4.19"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := tt.client.checkMinimumVersion("unifi_firewall_zone", firewallZoneMinimumVersion)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("checkMinimumVersion() diagnostics = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(diags[0].Detail(), "requires UniFi Network Application 9.0.0") {
+				t.Errorf("detail = %q, want it to name the required version", diags[0].Detail())
+			}
+		})
+	}
+}
+
+func Test_detectController_standalone(t *testing.T) {
+	srv := fakecontroller.New(fakecontroller.WithVersion("8.6.9"))
+	defer srv.Close()
+
+	c, err := newHTTPClient(transportConfig{AllowInsecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := detectController(context.Background(), c, srv.URL, "default", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := controllerInfo{Version: "8.6.9", Console: consoleTypeStandalone, APIBaseURL: srv.URL}
+	if got != want {
+		t.Errorf("detectController() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_detectController_unifiOS(t *testing.T) {
+	const apiKey = "secret"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = io.WriteString(w, "<html></html>")
+		case "/proxy/network/status":
+			// Recent versions no longer report the version here.
+			_, _ = io.WriteString(w, `{"meta":{"rc":"ok","up":true}}`)
+		case "/proxy/network/api/s/default/stat/sysinfo":
+			if r.Header.Get("X-API-KEY") != apiKey {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = io.WriteString(w, `{"meta":{"rc":"ok"},"data":[{"version":"9.0.114"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := newHTTPClient(transportConfig{AllowInsecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := detectController(context.Background(), c, srv.URL+"/", "default", apiKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := controllerInfo{Version: "9.0.114", Console: consoleTypeUniFiOS, APIBaseURL: srv.URL + "/proxy/network"}
+	if got != want {
+		t.Errorf("detectController() = %+v, want %+v", got, want)
+	}
+
+	if _, err := detectController(context.Background(), c, srv.URL, "default", "wrong"); err == nil {
+		t.Error("expected an error when the version cannot be read")
+	}
+}
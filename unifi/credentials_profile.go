@@ -0,0 +1,130 @@
+package unifi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// credentialsProfile is one table of the credentials file, for example:
+//
+//	[home]
+//	api_url = "https://192.168.1.1"
+//	api_key = "..."
+//	site    = "default"
+//	pinned_cert_sha256 = ["AB:CD:..."]
+type credentialsProfile struct {
+	APIURL        string `toml:"api_url"`
+	APIKey        string `toml:"api_key"`
+	Username      string `toml:"username"`
+	Password      string `toml:"password"`
+	Site          string `toml:"site"`
+	AllowInsecure bool   `toml:"allow_insecure"`
+
+	CACertPEM        string   `toml:"ca_cert_pem"`
+	CACertFile       string   `toml:"ca_cert_file"`
+	PinnedCertSHA256 []string `toml:"pinned_cert_sha256"`
+	ClientCertPEM    string   `toml:"client_cert_pem"`
+	ClientCertFile   string   `toml:"client_cert_file"`
+	ClientKeyPEM     string   `toml:"client_key_pem"`
+	ClientKeyFile    string   `toml:"client_key_file"`
+}
+
+// defaultCredentialsFile returns $XDG_CONFIG_HOME/unifi/credentials.toml,
+// falling back to ~/.config/unifi/credentials.toml on every platform.
+func defaultCredentialsFile() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "unifi", "credentials.toml"), nil
+}
+
+// loadCredentialsProfile reads the named profile from the credentials file,
+// or from the default location if file is empty. Relative certificate and key
+// paths are resolved against the directory of the credentials file.
+func loadCredentialsProfile(file, name string) (credentialsProfile, error) {
+	var profile credentialsProfile
+
+	if file == "" {
+		var err error
+		if file, err = defaultCredentialsFile(); err != nil {
+			return profile, fmt.Errorf("could not locate the credentials file: %w", err)
+		}
+	}
+
+	var profiles map[string]credentialsProfile
+	md, err := toml.DecodeFile(file, &profiles)
+	if err != nil {
+		return profile, fmt.Errorf("could not read credentials file %s: %w", file, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, k := range undecoded {
+			keys = append(keys, k.String())
+		}
+		return profile, fmt.Errorf("unknown keys in credentials file %s: %s", file, strings.Join(keys, ", "))
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return profile, fmt.Errorf("credentials file %s defines no profiles", file)
+		}
+		return profile, fmt.Errorf(
+			"profile %q not found in credentials file %s; available profiles: %s",
+			name, file, strings.Join(names, ", "),
+		)
+	}
+
+	dir := filepath.Dir(file)
+	for _, p := range []*string{&profile.CACertFile, &profile.ClientCertFile, &profile.ClientKeyFile} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+
+	return profile, nil
+}
+
+// fillAuth fills in the profile's credentials if neither an API key nor a
+// username or password was set by an argument or environment variable. They
+// are never mixed: go-unifi prefers an API key, so a profile api_key would
+// otherwise silently replace configured username and password.
+func (p credentialsProfile) fillAuth(apiKey, username, password *string) {
+	if *apiKey != "" || *username != "" || *password != "" {
+		return
+	}
+	*apiKey, *username, *password = p.APIKey, p.Username, p.Password
+}
+
+// fillTLS fills in the TLS settings that were not set by an argument or
+// environment variable. The PEM and file variants of a setting are taken from
+// the profile only if neither is already set, so they cannot conflict.
+func (p credentialsProfile) fillTLS(s *tlsSettings) {
+	if s.CACertPEM == "" && s.CACertFile == "" {
+		s.CACertPEM, s.CACertFile = p.CACertPEM, p.CACertFile
+	}
+	if len(s.PinnedCertSHA256) == 0 {
+		s.PinnedCertSHA256 = p.PinnedCertSHA256
+	}
+	if s.ClientCertPEM == "" && s.ClientCertFile == "" {
+		s.ClientCertPEM, s.ClientCertFile = p.ClientCertPEM, p.ClientCertFile
+	}
+	if s.ClientKeyPEM == "" && s.ClientKeyFile == "" {
+		s.ClientKeyPEM, s.ClientKeyFile = p.ClientKeyPEM, p.ClientKeyFile
+	}
+}
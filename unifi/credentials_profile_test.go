@@ -0,0 +1,164 @@
+package unifi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, dir, contents string) string {
+	t.Helper()
+	file := filepath.Join(dir, "credentials.toml")
+	if err := os.WriteFile(file, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func Test_loadCredentialsProfile(t *testing.T) {
+	dir := t.TempDir()
+	file := writeCredentialsFile(t, dir, `
+[home]
+api_url = "https://192.168.1.1"
+api_key = "home-key"
+site = "main"
+ca_cert_file = "certs/home-ca.pem"
+client_key_file = "/etc/unifi/client.key"
+pinned_cert_sha256 = ["AB:CD", "EF01"]
+
+[lab]
+api_url = "https://10.0.0.1"
+username = "admin"
+password = "secret"
+allow_insecure = true
+`)
+
+	got, err := loadCredentialsProfile(file, "home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := credentialsProfile{
+		APIURL:           "https://192.168.1.1",
+		APIKey:           "home-key",
+		Site:             "main",
+		CACertFile:       filepath.Join(dir, "certs", "home-ca.pem"),
+		ClientKeyFile:    "/etc/unifi/client.key",
+		PinnedCertSHA256: []string{"AB:CD", "EF01"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadCredentialsProfile(home) = %+v, want %+v", got, want)
+	}
+
+	got, err = loadCredentialsProfile(file, "lab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = credentialsProfile{
+		APIURL:        "https://10.0.0.1",
+		Username:      "admin",
+		Password:      "secret",
+		AllowInsecure: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadCredentialsProfile(lab) = %+v, want %+v", got, want)
+	}
+}
+
+func Test_loadCredentialsProfile_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		profile  string
+		wantErr  string
+	}{
+		{"missing profile", "[home]\napi_key = \"k\"\n[lab]\napi_key = \"k\"\n", "office", "available profiles: home, lab"},
+		{"no profiles", "", "home", "defines no profiles"},
+		{"unknown key", "[home]\napi_token = \"k\"\n", "home", "home.api_token"},
+		{"invalid toml", "[home\n", "home", "could not read credentials file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := writeCredentialsFile(t, t.TempDir(), tt.contents)
+			_, err := loadCredentialsProfile(file, tt.profile)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadCredentialsProfile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadCredentialsProfile(filepath.Join(t.TempDir(), "missing.toml"), "home")
+		if err == nil {
+			t.Error("expected an error for a missing credentials file")
+		}
+	})
+}
+
+func Test_loadCredentialsProfile_defaultFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "unifi"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeCredentialsFile(t, filepath.Join(dir, "unifi"), "[home]\napi_url = \"https://unifi.home\"\n")
+
+	got, err := loadCredentialsProfile("", "home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.APIURL != "https://unifi.home" {
+		t.Errorf("APIURL = %q, want %q", got.APIURL, "https://unifi.home")
+	}
+}
+
+func Test_credentialsProfile_fillTLS(t *testing.T) {
+	profile := credentialsProfile{
+		CACertFile:       "/profile/ca.pem",
+		PinnedCertSHA256: []string{"profile-pin"},
+		ClientCertFile:   "/profile/client.pem",
+		ClientKeyFile:    "/profile/client.key",
+	}
+
+	s := tlsSettings{
+		CACertPEM:     "inline CA",
+		ClientCertPEM: "inline cert",
+	}
+	profile.fillTLS(&s)
+
+	want := tlsSettings{
+		CACertPEM:        "inline CA",
+		PinnedCertSHA256: []string{"profile-pin"},
+		ClientCertPEM:    "inline cert",
+		ClientKeyFile:    "/profile/client.key",
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("fillTLS() = %+v, want %+v", s, want)
+	}
+}
+
+func Test_credentialsProfile_fillAuth(t *testing.T) {
+	profile := credentialsProfile{APIKey: "profile-key", Username: "profile-user", Password: "profile-pass"}
+
+	tests := []struct {
+		name                            string
+		apiKey, username, password      string
+		wantKey, wantUser, wantPassword string
+	}{
+		{"nothing configured", "", "", "", "profile-key", "profile-user", "profile-pass"},
+		{"username and password configured", "", "admin", "secret", "", "admin", "secret"},
+		{"api key configured", "key", "", "", "key", "", ""},
+		{"only username configured", "", "admin", "", "", "admin", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKey, username, password := tt.apiKey, tt.username, tt.password
+			profile.fillAuth(&apiKey, &username, &password)
+			if apiKey != tt.wantKey || username != tt.wantUser || password != tt.wantPassword {
+				t.Errorf("fillAuth() = %q, %q, %q, want %q, %q, %q",
+					apiKey, username, password, tt.wantKey, tt.wantUser, tt.wantPassword)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package unifi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_csrfTransport(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/auth/login":
+			w.Header().Set("X-Csrf-Token", "first")
+		case "/api/auth/logout":
+		default:
+			mu.Lock()
+			got = append(got, r.Header.Get("X-Csrf-Token"))
+			mu.Unlock()
+			if r.URL.Path == "/rotate" {
+				w.Header().Set("X-Updated-Csrf-Token", "second")
+			}
+		}
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: &csrfTransport{next: http.DefaultTransport}}
+	do := func(path, token string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("X-Csrf-Token", token)
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		closeBody(resp)
+	}
+
+	do("/before-login", "")
+	do("/api/auth/login", "")
+	do("/cmd", "")
+	do("/own-token", "go-unifi")
+	do("/rotate", "")
+	do("/cmd", "")
+	do("/api/auth/logout", "")
+	do("/cmd", "")
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"", "first", "go-unifi", "first", "second", ""}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tokens sent = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,366 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/iptypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action                   = &deviceCommandAction{}
+	_ action.ActionWithConfigure      = &deviceCommandAction{}
+	_ action.ActionWithValidateConfig = &deviceCommandAction{}
+)
+
+// defaultLocateSeconds is how long `locate` blinks a device's LED by default.
+const defaultLocateSeconds = 30
+
+// NewDeviceCommandAction returns a new instance of the device command action.
+func NewDeviceCommandAction() action.Action {
+	return &deviceCommandAction{}
+}
+
+type deviceCommandAction struct {
+	client *Client
+}
+
+// deviceCommandActionModel describes the action request data model.
+type deviceCommandActionModel struct {
+	DeviceMAC     hwtypes.MACAddress  `tfsdk:"device_mac"`
+	Command       types.String        `tfsdk:"command"`
+	LocateSeconds types.Int64         `tfsdk:"locate_seconds"`
+	IP            iptypes.IPv4Address `tfsdk:"ip"`
+	InformURL     types.String        `tfsdk:"inform_url"`
+	SSHUsername   types.String        `tfsdk:"ssh_username"`
+	SSHPassword   types.String        `tfsdk:"ssh_password"`
+	SSHPort       types.Int64         `tfsdk:"ssh_port"`
+	SSHKeyVerify  types.Bool          `tfsdk:"ssh_key_verify"`
+	Timeouts      timeouts.Value      `tfsdk:"timeouts"`
+}
+
+func (a *deviceCommandAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_device_command"
+}
+
+func (a *deviceCommandAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sends a one-off command to a UniFi device, such as blinking its LED or " +
+			"adopting it across a layer 3 boundary. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"device_mac": schema.StringAttribute{
+				MarkdownDescription: "MAC address of the device.",
+				CustomType:          hwtypes.MACAddressType{},
+				Required:            true,
+			},
+			"command": schema.StringAttribute{
+				MarkdownDescription: "The command to send. `locate` blinks the device's LED for " +
+					"`locate_seconds` and then stops; `force_provision` pushes the device's configuration " +
+					"to it again; `set_inform` logs in to the device at `ip` over SSH and points it at " +
+					"`inform_url`, adopting a device on another layer 3 network; `rf_scan` starts an RF " +
+					"environment scan on an access point, which disconnects its clients while it runs.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("locate", "force_provision", "set_inform", "rf_scan"),
+				},
+			},
+			"locate_seconds": schema.Int64Attribute{
+				MarkdownDescription: "How long to blink the LED, in seconds. Only valid with `locate`. " +
+					"Defaults to `30`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 3600),
+				},
+			},
+			"ip": schema.StringAttribute{
+				MarkdownDescription: "The IP address to reach the device at over SSH. Required with `set_inform`.",
+				CustomType:          iptypes.IPv4AddressType{},
+				Optional:            true,
+			},
+			"inform_url": schema.StringAttribute{
+				MarkdownDescription: "The inform URL to set on the device. Only valid with `set_inform`. " +
+					"Defaults to port `8080` of the controller's host, e.g. `http://unifi.example.com:8080/inform`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^https?://[^/]+/`),
+						"must be an http:// or https:// URL",
+					),
+				},
+			},
+			"ssh_username": schema.StringAttribute{
+				MarkdownDescription: "The SSH username of the device. Required with `set_inform`; `ubnt` " +
+					"for a device in its factory state.",
+				Optional: true,
+			},
+			"ssh_password": schema.StringAttribute{
+				MarkdownDescription: "The SSH password of the device. Required with `set_inform`; `ubnt` " +
+					"for a device in its factory state.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"ssh_port": schema.Int64Attribute{
+				MarkdownDescription: "The SSH port of the device. Only valid with `set_inform`. Defaults to `22`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"ssh_key_verify": schema.BoolAttribute{
+				MarkdownDescription: "Whether the controller verifies the device's SSH host key before " +
+					"sending the credentials. Only valid with `set_inform`. Defaults to `true`; set to " +
+					"`false` for a device whose host key the controller has not seen, such as one that was " +
+					"factory reset.",
+				Optional: true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *deviceCommandAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *deviceCommandAction) ValidateConfig(
+	ctx context.Context,
+	req action.ValidateConfigRequest,
+	resp *action.ValidateConfigResponse,
+) {
+	var config deviceCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Command.IsUnknown() {
+		return
+	}
+	command := config.Command.ValueString()
+
+	attributes := []struct {
+		name    string
+		value   attr.Value
+		command string
+	}{
+		{"locate_seconds", config.LocateSeconds, "locate"},
+		{"ip", config.IP, "set_inform"},
+		{"inform_url", config.InformURL, "set_inform"},
+		{"ssh_username", config.SSHUsername, "set_inform"},
+		{"ssh_password", config.SSHPassword, "set_inform"},
+		{"ssh_port", config.SSHPort, "set_inform"},
+		{"ssh_key_verify", config.SSHKeyVerify, "set_inform"},
+	}
+	for _, field := range attributes {
+		if field.command != command && !field.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(field.name),
+				"Invalid Attribute Combination",
+				fmt.Sprintf("%q can only be set with the %s command, not %s.", field.name, field.command, command),
+			)
+		}
+	}
+
+	if command != "set_inform" {
+		return
+	}
+	for _, field := range []struct {
+		name  string
+		value attr.Value
+	}{
+		{"ip", config.IP},
+		{"ssh_username", config.SSHUsername},
+		{"ssh_password", config.SSHPassword},
+	} {
+		if field.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(field.name),
+				"Missing Required Attribute",
+				fmt.Sprintf("%q is required with the set_inform command.", field.name),
+			)
+		}
+	}
+}
+
+func (a *deviceCommandAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_device_command")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config deviceCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	locateSeconds := int64(defaultLocateSeconds)
+	if !config.LocateSeconds.IsNull() {
+		locateSeconds = config.LocateSeconds.ValueInt64()
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 2*time.Minute+time.Duration(locateSeconds)*time.Second)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	mac, err := normalizeMAC(config.DeviceMAC.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Device MAC Address", err.Error())
+		return
+	}
+	site := a.client.Site
+	command := config.Command.ValueString()
+
+	switch command {
+	case "locate":
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Blinking the LED of %s for %ds", mac, locateSeconds),
+		})
+		err = a.client.locateDevice(ctx, site, mac, time.Duration(locateSeconds)*time.Second)
+	case "set_inform":
+		var cmd map[string]any
+		cmd, err = a.client.setInformCommand(mac, &config)
+		if err == nil {
+			resp.SendProgress(action.InvokeProgressEvent{
+				Message: fmt.Sprintf("Setting the inform URL of %s to %s", mac, cmd["url"]),
+			})
+			err = a.client.executeCommand(ctx, site, "devmgr", cmd, nil)
+		}
+	default:
+		err = a.client.executeCommand(ctx, site, "devmgr", map[string]any{
+			"cmd": deviceCommands[command],
+			"mac": mac,
+		}, nil)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Sending Device Command",
+			fmt.Sprintf("Could not send %s to device %s: %s", command, mac, err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Device command sent", map[string]any{
+		"mac":     mac,
+		"command": command,
+	})
+}
+
+// deviceCommands maps the action's single-request commands to the
+// controller's `devmgr` commands.
+var deviceCommands = map[string]string{
+	"force_provision": "force-provision",
+	"rf_scan":         "spectrum-scan",
+}
+
+// locateDevice blinks the LED of the device for d. The LED is switched off
+// again even if ctx ends first, so an interrupted apply does not leave a
+// device blinking.
+func (c *Client) locateDevice(ctx context.Context, site, mac string, d time.Duration) error {
+	if err := c.executeCommand(ctx, site, "devmgr", map[string]any{"cmd": "set-locate", "mac": mac}, nil); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+	defer cancel()
+	if err := c.executeCommand(stopCtx, site, "devmgr", map[string]any{"cmd": "unset-locate", "mac": mac}, nil); err != nil {
+		return fmt.Errorf("could not stop locating the device: %w", err)
+	}
+	return ctx.Err()
+}
+
+// setInformCommand builds the `adv-adopt` command for config. Without
+// inform_url, the device is pointed at port 8080 of the controller's host, and
+// the device's SSH host key is verified unless ssh_key_verify is false.
+func (c *Client) setInformCommand(mac string, config *deviceCommandActionModel) (map[string]any, error) {
+	informURL := config.InformURL.ValueString()
+	if informURL == "" {
+		base, err := url.Parse(c.controller.APIBaseURL)
+		if err != nil || base.Hostname() == "" {
+			return nil, errors.New("could not derive an inform URL from the controller URL; set inform_url")
+		}
+		informURL = (&url.URL{Scheme: "http", Host: base.Hostname() + ":8080", Path: "/inform"}).String()
+	}
+
+	port := int64(22)
+	if !config.SSHPort.IsNull() {
+		port = config.SSHPort.ValueInt64()
+	}
+
+	verify := true
+	if !config.SSHKeyVerify.IsNull() {
+		verify = config.SSHKeyVerify.ValueBool()
+	}
+
+	return map[string]any{
+		"cmd":            "adv-adopt",
+		"mac":            mac,
+		"ip":             config.IP.ValueString(),
+		"username":       config.SSHUsername.ValueString(),
+		"password":       config.SSHPassword.ValueString(),
+		"url":            informURL,
+		"port":           port,
+		"ssh_key_verify": verify,
+	}, nil
+}
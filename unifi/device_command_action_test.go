@@ -0,0 +1,229 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func TestNewDeviceCommandAction(t *testing.T) {
+	got := NewDeviceCommandAction()
+	if got == nil {
+		t.Fatal("NewDeviceCommandAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+	if _, ok := got.(fwaction.ActionWithValidateConfig); !ok {
+		t.Error("expected ActionWithValidateConfig interface")
+	}
+}
+
+func Test_deviceCommandAction_Metadata(t *testing.T) {
+	a := &deviceCommandAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_device_command" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_device_command")
+	}
+}
+
+func Test_deviceCommandAction_Schema(t *testing.T) {
+	a := &deviceCommandAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{
+		"device_mac", "command", "locate_seconds", "ip", "inform_url",
+		"ssh_username", "ssh_password", "ssh_port", "ssh_key_verify", "timeouts",
+	} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	if !resp.Schema.Attributes["ssh_password"].IsSensitive() {
+		t.Error("ssh_password should be sensitive")
+	}
+}
+
+func Test_deviceCommandAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &deviceCommandAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+// deviceCommandConfig builds an action config from values, leaving every
+// other attribute null.
+func deviceCommandConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	resp := &fwaction.SchemaResponse{}
+	(&deviceCommandAction{}).Schema(ctx, fwaction.SchemaRequest{}, resp)
+	typ, ok := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("schema type is not an object")
+	}
+	attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+		} else {
+			attrs[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return tfsdk.Config{Schema: resp.Schema, Raw: tftypes.NewValue(typ, attrs)}
+}
+
+func Test_deviceCommandAction_ValidateConfig(t *testing.T) {
+	str := func(v any) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+	num := func(v any) tftypes.Value { return tftypes.NewValue(tftypes.Number, v) }
+
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		wantError bool
+	}{
+		{"force_provision", map[string]tftypes.Value{"command": str("force_provision")}, false},
+		{"locate_with_seconds", map[string]tftypes.Value{"command": str("locate"), "locate_seconds": num(60)}, false},
+		{"rf_scan_with_seconds", map[string]tftypes.Value{"command": str("rf_scan"), "locate_seconds": num(60)}, true},
+		{"set_inform", map[string]tftypes.Value{
+			"command":      str("set_inform"),
+			"ip":           str("10.20.0.15"),
+			"ssh_username": str("ubnt"),
+			"ssh_password": str("ubnt"),
+		}, false},
+		{"set_inform_without_ip", map[string]tftypes.Value{
+			"command":      str("set_inform"),
+			"ssh_username": str("ubnt"),
+			"ssh_password": str("ubnt"),
+		}, true},
+		{"locate_with_ip", map[string]tftypes.Value{"command": str("locate"), "ip": str("10.20.0.15")}, true},
+		{"locate_with_ssh_key_verify", map[string]tftypes.Value{
+			"command":        str("locate"),
+			"ssh_key_verify": tftypes.NewValue(tftypes.Bool, false),
+		}, true},
+		{"unknown_command", map[string]tftypes.Value{"command": str(tftypes.UnknownValue), "ip": str("10.20.0.15")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]tftypes.Value{"device_mac": str("aa:bb:cc:dd:ee:ff")}
+			for k, v := range tt.values {
+				values[k] = v
+			}
+			resp := &fwaction.ValidateConfigResponse{}
+			(&deviceCommandAction{}).ValidateConfig(
+				context.Background(),
+				fwaction.ValidateConfigRequest{Config: deviceCommandConfig(t, values)},
+				resp,
+			)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v (diags: %v)", resp.Diagnostics.HasError(), tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestClient_locateDevice(t *testing.T) {
+	const mac = "aa:bb:cc:dd:ee:ff"
+
+	t.Run("stops after the duration", func(t *testing.T) {
+		srv := fakecontroller.New(fakecontroller.WithAPIKey("key"))
+		defer srv.Close()
+		c := newTestCommandClient(t, srv, "key")
+
+		if err := c.locateDevice(context.Background(), "default", mac, 10*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+		assertDeviceCommands(t, srv.Commands(), "set-locate", "unset-locate")
+	})
+
+	t.Run("stops when interrupted", func(t *testing.T) {
+		srv := fakecontroller.New(fakecontroller.WithAPIKey("key"))
+		defer srv.Close()
+		c := newTestCommandClient(t, srv, "key")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if err := c.locateDevice(ctx, "default", mac, time.Hour); err == nil {
+			t.Error("locateDevice() = nil, want the context error")
+		}
+		assertDeviceCommands(t, srv.Commands(), "set-locate", "unset-locate")
+	})
+}
+
+func assertDeviceCommands(t *testing.T, got []fakecontroller.Command, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("commands = %v, want %v", got, want)
+	}
+	for i, cmd := range got {
+		if cmd.Manager != "devmgr" || cmd.Body["cmd"] != want[i] {
+			t.Errorf("command %d = %s %v, want devmgr %s", i, cmd.Manager, cmd.Body, want[i])
+		}
+	}
+}
+
+func TestClient_setInformCommand(t *testing.T) {
+	const mac = "aa:bb:cc:dd:ee:ff"
+	c := &Client{controller: controllerInfo{APIBaseURL: "https://unifi.example.com/proxy/network"}}
+
+	config := &deviceCommandActionModel{
+		SSHUsername: types.StringValue("ubnt"),
+		SSHPassword: types.StringValue("ubnt"),
+	}
+	got, err := c.setInformCommand(mac, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["cmd"] != "adv-adopt" || got["url"] != "http://unifi.example.com:8080/inform" || got["port"] != int64(22) {
+		t.Errorf("set_inform command = %v, want adv-adopt to the controller's inform URL on port 22", got)
+	}
+	if got["ssh_key_verify"] != true {
+		t.Errorf("set_inform command = %v, want the SSH host key verified by default", got)
+	}
+
+	config.InformURL = types.StringValue("http://10.0.0.2:8080/inform")
+	config.SSHPort = types.Int64Value(2222)
+	got, err = c.setInformCommand(mac, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["url"] != "http://10.0.0.2:8080/inform" || got["port"] != int64(2222) {
+		t.Errorf("set_inform command = %v, want the configured URL and port", got)
+	}
+
+	config.SSHKeyVerify = types.BoolValue(false)
+	got, err = c.setInformCommand(mac, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["ssh_key_verify"] != false {
+		t.Errorf("set_inform command = %v, want ssh_key_verify false", got)
+	}
+
+	if _, err := (&Client{}).setInformCommand(mac, &deviceCommandActionModel{}); err == nil {
+		t.Error("setInformCommand() without a controller URL succeeded, want an error")
+	}
+}
@@ -0,0 +1,586 @@
+package unifi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/iptypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &deviceDataSource{}
+
+func NewDeviceDataSource() datasource.DataSource {
+	return &deviceDataSource{}
+}
+
+// deviceDataSource defines the data source implementation.
+type deviceDataSource struct {
+	client *Client
+}
+
+// deviceDataSourceModel describes the data source data model.
+type deviceDataSourceModel struct {
+	// Lookup keys
+	Site types.String       `tfsdk:"site"`
+	MAC  hwtypes.MACAddress `tfsdk:"mac"`
+	Name types.String       `tfsdk:"name"`
+
+	ID         types.String        `tfsdk:"id"`
+	Type       types.String        `tfsdk:"type"`
+	Model      types.String        `tfsdk:"model"`
+	Version    types.String        `tfsdk:"version"`
+	IP         iptypes.IPv4Address `tfsdk:"ip"`
+	State      types.Int64         `tfsdk:"state"`
+	Adopted    types.Bool          `tfsdk:"adopted"`
+	Uptime     types.Int64         `tfsdk:"uptime"`
+	Uplink     types.Object        `tfsdk:"uplink"`
+	PortTable  types.List          `tfsdk:"port_table"`
+	RadioTable types.List          `tfsdk:"radio_table"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// deviceUplinkAttrTypes returns the attribute types of a device's uplink.
+func deviceUplinkAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"mac":        hwtypes.MACAddressType{},
+		"name":       types.StringType,
+		"port":       types.Int64Type,
+		"local_port": types.Int64Type,
+		"type":       types.StringType,
+		"speed_mbps": types.Int64Type,
+	}
+}
+
+// lldpNeighborAttrTypes returns the attribute types of a port's LLDP
+// neighbor.
+func lldpNeighborAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"chassis_id": types.StringType,
+		"port_id":    types.StringType,
+	}
+}
+
+// devicePortAttrTypes returns the attribute types of a `port_table` entry.
+func devicePortAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"port_idx":        types.Int64Type,
+		"name":            types.StringType,
+		"up":              types.BoolType,
+		"speed_mbps":      types.Int64Type,
+		"full_duplex":     types.BoolType,
+		"poe":             types.BoolType,
+		"poe_power_watts": types.Float64Type,
+		"stp_state":       types.StringType,
+		"connected_macs":  types.ListType{ElemType: types.StringType},
+		"lldp_neighbor":   types.ObjectType{AttrTypes: lldpNeighborAttrTypes()},
+	}
+}
+
+// deviceRadioAttrTypes returns the attribute types of a `radio_table` entry.
+func deviceRadioAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":        types.StringType,
+		"radio":       types.StringType,
+		"channel":     types.Int64Type,
+		"tx_power":    types.Int64Type,
+		"utilization": types.Int64Type,
+		"num_clients": types.Int64Type,
+	}
+}
+
+func (d *deviceDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_device"
+}
+
+func (d *deviceDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`unifi_device` data source can be used to look up an adopted device by MAC " +
+			"address or name, along with the live state of its ports, radios and uplink. The live state " +
+			"needs a direct connection to the controller; with `cloud_connector`, `uplink`, `port_table` " +
+			"and `radio_table` are null.",
+
+		Attributes: map[string]schema.Attribute{
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site the device is adopted on.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"mac": schema.StringAttribute{
+				MarkdownDescription: "The MAC address of the device. Conflicts with `name`.",
+				CustomType:          hwtypes.MACAddressType{},
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("name"),
+					}...),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the device. Conflicts with `mac`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("mac"),
+					}...),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the device.",
+				Computed:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The device type, such as `usw` or `uap`.",
+				Computed:            true,
+			},
+			"model": schema.StringAttribute{
+				MarkdownDescription: "The device model.",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The firmware version the device is running.",
+				Computed:            true,
+			},
+			"ip": schema.StringAttribute{
+				MarkdownDescription: "The IPv4 address of the device.",
+				CustomType:          iptypes.IPv4AddressType{},
+				Computed:            true,
+			},
+			"state": schema.Int64Attribute{
+				MarkdownDescription: "The device state; `1` when connected.",
+				Computed:            true,
+			},
+			"adopted": schema.BoolAttribute{
+				MarkdownDescription: "Whether the device is adopted.",
+				Computed:            true,
+			},
+			"uptime": schema.Int64Attribute{
+				MarkdownDescription: "The device uptime in seconds.",
+				Computed:            true,
+			},
+			"uplink": schema.SingleNestedAttribute{
+				MarkdownDescription: "The device's link towards the gateway. Null for the gateway itself, " +
+					"for devices that are not connected, and without a direct connection to the controller.",
+				Computed: true,
+				Attributes: map[string]schema.Attribute{
+					"mac": schema.StringAttribute{
+						MarkdownDescription: "The MAC address of the upstream device.",
+						CustomType:          hwtypes.MACAddressType{},
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "The name of the upstream device.",
+						Computed:            true,
+					},
+					"port": schema.Int64Attribute{
+						MarkdownDescription: "The port on the upstream device the device is connected to.",
+						Computed:            true,
+					},
+					"local_port": schema.Int64Attribute{
+						MarkdownDescription: "The port on this device that carries the uplink.",
+						Computed:            true,
+					},
+					"type": schema.StringAttribute{
+						MarkdownDescription: "The uplink type, `wire` or `wireless`.",
+						Computed:            true,
+					},
+					"speed_mbps": schema.Int64Attribute{
+						MarkdownDescription: "The uplink speed in Mbps.",
+						Computed:            true,
+					},
+				},
+			},
+			"port_table": schema.ListNestedAttribute{
+				MarkdownDescription: "The live state of the device's ports, ordered by port index. Null " +
+					"without a direct connection to the controller.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"port_idx": schema.Int64Attribute{
+							MarkdownDescription: "The port index.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The port name.",
+							Computed:            true,
+						},
+						"up": schema.BoolAttribute{
+							MarkdownDescription: "Whether the port has link.",
+							Computed:            true,
+						},
+						"speed_mbps": schema.Int64Attribute{
+							MarkdownDescription: "The negotiated link speed in Mbps; `0` when the port is down.",
+							Computed:            true,
+						},
+						"full_duplex": schema.BoolAttribute{
+							MarkdownDescription: "Whether the link is full duplex.",
+							Computed:            true,
+						},
+						"poe": schema.BoolAttribute{
+							MarkdownDescription: "Whether the port can supply PoE.",
+							Computed:            true,
+						},
+						"poe_power_watts": schema.Float64Attribute{
+							MarkdownDescription: "The power the port is currently supplying, in watts.",
+							Computed:            true,
+						},
+						"stp_state": schema.StringAttribute{
+							MarkdownDescription: "The spanning tree state of the port, such as `forwarding`, " +
+								"`blocking` or `disabled`.",
+							Computed: true,
+						},
+						"connected_macs": schema.ListAttribute{
+							MarkdownDescription: "The MAC addresses the device has learned on the port.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"lldp_neighbor": schema.SingleNestedAttribute{
+							MarkdownDescription: "The neighbor the port sees over LLDP, if any.",
+							Computed:            true,
+							Attributes: map[string]schema.Attribute{
+								"chassis_id": schema.StringAttribute{
+									MarkdownDescription: "The neighbor's chassis ID, usually its MAC address.",
+									Computed:            true,
+								},
+								"port_id": schema.StringAttribute{
+									MarkdownDescription: "The neighbor's port ID.",
+									Computed:            true,
+								},
+							},
+						},
+					},
+				},
+			},
+			"radio_table": schema.ListNestedAttribute{
+				MarkdownDescription: "The live state of the device's radios. Empty for devices without " +
+					"radios, and null without a direct connection to the controller.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The interface name of the radio, such as `wifi0`.",
+							Computed:            true,
+						},
+						"radio": schema.StringAttribute{
+							MarkdownDescription: "The radio band (`ng`, `na`, `6e` or `ad`).",
+							Computed:            true,
+						},
+						"channel": schema.Int64Attribute{
+							MarkdownDescription: "The channel the radio is using.",
+							Computed:            true,
+						},
+						"tx_power": schema.Int64Attribute{
+							MarkdownDescription: "The transmit power in dBm.",
+							Computed:            true,
+						},
+						"utilization": schema.Int64Attribute{
+							MarkdownDescription: "The channel utilization in percent.",
+							Computed:            true,
+						},
+						"num_clients": schema.Int64Attribute{
+							MarkdownDescription: "The number of clients associated with the radio.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *deviceDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *deviceDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var config deviceDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := config.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := config.Site.ValueString()
+	if site == "" {
+		site = d.client.Site
+	}
+
+	// The live state is only in the raw `stat/device` response, which is read
+	// with controllerRequest. Without it, the device is read with go-unifi.
+	live := d.client.controller.APIBaseURL != ""
+
+	var mac string
+	switch {
+	case !config.MAC.IsNull() && !config.MAC.IsUnknown():
+		var err error
+		mac, err = normalizeMAC(config.MAC.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("mac"), "Invalid Device MAC Address", err.Error())
+			return
+		}
+	case !config.Name.IsNull() && !config.Name.IsUnknown():
+		name := config.Name.ValueString()
+		devices, err := d.client.ListDevice(ctx, site)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Listing Devices",
+				"Could not list devices: "+err.Error(),
+			)
+			return
+		}
+		var matches []string
+		for _, device := range devices {
+			if device.Name == name {
+				matches = append(matches, device.MAC)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			resp.Diagnostics.AddError(
+				"Device Not Found",
+				fmt.Sprintf("No device named %q on site %s", name, site),
+			)
+			return
+		case 1:
+			var err error
+			mac, err = normalizeMAC(matches[0])
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid Device MAC Address", err.Error())
+				return
+			}
+		default:
+			resp.Diagnostics.AddError(
+				"Multiple Devices Found",
+				fmt.Sprintf("%d devices are named %q on site %s; look the device up by mac instead", len(matches), name, site),
+			)
+			return
+		}
+	default:
+		resp.Diagnostics.AddError(
+			"Missing Required Attribute",
+			"Either 'mac' or 'name' must be specified",
+		)
+		return
+	}
+
+	// The port, radio and uplink tables change from one read to the next, so
+	// the whole model comes from a single read that bypasses the cache.
+	read := d.client.getDeviceStatus
+	if !live {
+		read = d.client.getDeviceSummary
+	}
+	status, err := read(ctx, site, mac)
+	if err != nil {
+		if errors.Is(err, errDeviceNotFound) {
+			resp.Diagnostics.AddError(
+				"Device Not Found",
+				fmt.Sprintf("Device with MAC %s not found on site %s", mac, site),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading Device",
+			fmt.Sprintf("Could not read device with MAC %s: %s", mac, err),
+		)
+		return
+	}
+
+	state := deviceDataSourceModel{
+		Site:     types.StringValue(site),
+		MAC:      util.MACValueOrNull(status.MAC),
+		Name:     util.StringValueOrNull(status.Name),
+		ID:       types.StringValue(status.ID),
+		Type:     types.StringValue(status.Type),
+		Model:    types.StringValue(status.Model),
+		Version:  util.StringValueOrNull(status.Version),
+		IP:       util.IPv4ValueOrNull(status.IP),
+		State:    types.Int64Value(int64(status.State)),
+		Adopted:  types.BoolValue(status.Adopted),
+		Uptime:   types.Int64Value(status.Uptime),
+		Timeouts: config.Timeouts,
+	}
+
+	if !live {
+		state.Uplink = types.ObjectNull(deviceUplinkAttrTypes())
+		state.PortTable = types.ListNull(types.ObjectType{AttrTypes: devicePortAttrTypes()})
+		state.RadioTable = types.ListNull(types.ObjectType{AttrTypes: deviceRadioAttrTypes()})
+		resp.Diagnostics.AddWarning(
+			"Live Device State Unavailable",
+			fmt.Sprintf(
+				"The uplink, port_table and radio_table of device %s are null: %s",
+				mac,
+				d.client.controllerURLError(),
+			),
+		)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	var diags diag.Diagnostics
+	state.Uplink, diags = deviceUplinkValue(status.Uplink)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	state.PortTable, diags = devicePortTableValue(status.PortTable, status.LLDPTable)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	state.RadioTable, diags = deviceRadioTableValue(status.RadioTableStats)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// deviceUplinkValue converts a device's uplink to its Terraform value, null
+// when the device has no upstream device.
+func deviceUplinkValue(uplink *deviceUplink) (types.Object, diag.Diagnostics) {
+	if uplink == nil || uplink.UplinkMAC == "" {
+		return types.ObjectNull(deviceUplinkAttrTypes()), nil
+	}
+	return types.ObjectValue(deviceUplinkAttrTypes(), map[string]attr.Value{
+		"mac":        util.MACValueOrNull(uplink.UplinkMAC),
+		"name":       util.StringValueOrNull(uplink.UplinkDeviceName),
+		"port":       types.Int64Value(uplink.UplinkRemotePort),
+		"local_port": types.Int64Value(uplink.PortIdx),
+		"type":       util.StringValueOrNull(uplink.Type),
+		"speed_mbps": types.Int64Value(uplink.Speed),
+	})
+}
+
+// devicePortTableValue converts a device's port table to its Terraform value,
+// attaching each port's LLDP neighbor from lldp.
+func devicePortTableValue(ports []devicePort, lldp []lldpNeighbor) (types.List, diag.Diagnostics) {
+	ports = slices.Clone(ports)
+	slices.SortFunc(ports, func(a, b devicePort) int { return cmp.Compare(a.PortIdx, b.PortIdx) })
+
+	var diags diag.Diagnostics
+	objects := make([]attr.Value, 0, len(ports))
+	for _, p := range ports {
+		macs := make([]attr.Value, len(p.MACTable))
+		for i, entry := range p.MACTable {
+			macs[i] = types.StringValue(entry.MAC)
+		}
+		connected, d := types.ListValue(types.StringType, macs)
+		diags.Append(d...)
+
+		neighbor := types.ObjectNull(lldpNeighborAttrTypes())
+		for _, n := range lldp {
+			if n.LocalPortIdx == p.PortIdx {
+				neighbor, d = types.ObjectValue(lldpNeighborAttrTypes(), map[string]attr.Value{
+					"chassis_id": types.StringValue(n.ChassisID),
+					"port_id":    util.StringValueOrNull(n.PortID),
+				})
+				diags.Append(d...)
+				break
+			}
+		}
+
+		o, d := types.ObjectValue(devicePortAttrTypes(), map[string]attr.Value{
+			"port_idx":        types.Int64Value(p.PortIdx),
+			"name":            util.StringValueOrNull(p.Name),
+			"up":              types.BoolValue(p.Up),
+			"speed_mbps":      types.Int64Value(p.Speed),
+			"full_duplex":     types.BoolValue(p.FullDuplex),
+			"poe":             types.BoolValue(p.PortPoe),
+			"poe_power_watts": types.Float64Value(float64(p.PoePower)),
+			"stp_state":       util.StringValueOrNull(p.StpState),
+			"connected_macs":  connected,
+			"lldp_neighbor":   neighbor,
+		})
+		diags.Append(d...)
+		objects = append(objects, o)
+	}
+	if diags.HasError() {
+		return types.ListNull(types.ObjectType{AttrTypes: devicePortAttrTypes()}), diags
+	}
+
+	value, d := types.ListValue(types.ObjectType{AttrTypes: devicePortAttrTypes()}, objects)
+	diags.Append(d...)
+	return value, diags
+}
+
+// deviceRadioTableValue converts a device's radio stats to its Terraform
+// value.
+func deviceRadioTableValue(radios []radioStats) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	objects := make([]attr.Value, 0, len(radios))
+	for _, r := range radios {
+		o, d := types.ObjectValue(deviceRadioAttrTypes(), map[string]attr.Value{
+			"name":        util.StringValueOrNull(r.Name),
+			"radio":       types.StringValue(r.Radio),
+			"channel":     types.Int64Value(r.Channel),
+			"tx_power":    types.Int64Value(r.TxPower),
+			"utilization": types.Int64Value(r.CuTotal),
+			"num_clients": types.Int64Value(r.NumSta),
+		})
+		diags.Append(d...)
+		objects = append(objects, o)
+	}
+	if diags.HasError() {
+		return types.ListNull(types.ObjectType{AttrTypes: deviceRadioAttrTypes()}), diags
+	}
+
+	value, d := types.ListValue(types.ObjectType{AttrTypes: deviceRadioAttrTypes()}, objects)
+	diags.Append(d...)
+	return value, diags
+}
@@ -0,0 +1,197 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceDataSource_basic(t *testing.T) {
+	skipOnFakeController(t, "needs an adopted device, which the fake does not simulate")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceDataSourceConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.unifi_device.test", "id"),
+					resource.TestCheckResourceAttrSet("data.unifi_device.test", "model"),
+					resource.TestCheckResourceAttrSet("data.unifi_device.test", "port_table.#"),
+					resource.TestCheckResourceAttr("data.unifi_device.test", "adopted", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDeviceDataSourceConfig_basic() string {
+	return `
+data "unifi_device" "test" {
+  mac = "00:27:22:00:00:02"
+}
+`
+}
+
+func TestNewDeviceDataSource(t *testing.T) {
+	d := NewDeviceDataSource()
+	if d == nil {
+		t.Fatal("NewDeviceDataSource() returned nil")
+	}
+}
+
+func Test_deviceDataSource_Metadata(t *testing.T) {
+	d := &deviceDataSource{}
+	resp := &fwdatasource.MetadataResponse{}
+	d.Metadata(context.Background(), fwdatasource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_device" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_device")
+	}
+}
+
+func Test_deviceDataSource_Schema(t *testing.T) {
+	d := &deviceDataSource{}
+	resp := &fwdatasource.SchemaResponse{}
+	d.Schema(context.Background(), fwdatasource.SchemaRequest{}, resp)
+	for _, attr := range []string{"site", "mac", "name", "id", "model", "version", "ip", "uplink", "port_table", "radio_table", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_deviceDataSource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwdatasource.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwdatasource.ConfigureRequest{}, false},
+		{"wrong_type", fwdatasource.ConfigureRequest{ProviderData: "wrong"}, true},
+		{"correct_client", fwdatasource.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &deviceDataSource{}
+			resp := &fwdatasource.ConfigureResponse{}
+			d.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_jsonFloat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    jsonFloat
+		wantErr bool
+	}{
+		{`"3.45"`, 3.45, false},
+		{`2.5`, 2.5, false},
+		{`""`, 0, false},
+		{`null`, 0, false},
+		{`"n/a"`, 0, true},
+	}
+	for _, tt := range tests {
+		var got jsonFloat
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_devicePortTableValue(t *testing.T) {
+	var ports []devicePort
+	err := json.Unmarshal([]byte(`[
+		{"port_idx": 2, "name": "Port 2", "up": false, "speed": 0, "port_poe": true, "poe_power": "0.00", "stp_state": "disabled"},
+		{"port_idx": 1, "name": "Port 1", "up": true, "speed": 1000, "full_duplex": true, "port_poe": true,
+		 "poe_power": "4.51", "stp_state": "forwarding", "mac_table": [{"mac": "aa:aa:aa:aa:aa:01"}, {"mac": "aa:aa:aa:aa:aa:02"}]}
+	]`), &ports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lldp := []lldpNeighbor{{LocalPortIdx: 1, ChassisID: "22:22:22:22:22:22", PortID: "eth0"}}
+
+	value, diags := devicePortTableValue(ports, lldp)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(value.Elements()) != 2 {
+		t.Fatalf("got %d ports, want 2", len(value.Elements()))
+	}
+
+	first := value.Elements()[0].(types.Object).Attributes()
+	if got := first["port_idx"].(types.Int64).ValueInt64(); got != 1 {
+		t.Errorf("first port_idx = %d, want 1", got)
+	}
+	if got := first["speed_mbps"].(types.Int64).ValueInt64(); got != 1000 {
+		t.Errorf("speed_mbps = %d, want 1000", got)
+	}
+	if got := first["poe_power_watts"].(types.Float64).ValueFloat64(); got != 4.51 {
+		t.Errorf("poe_power_watts = %v, want 4.51", got)
+	}
+	if got := first["stp_state"].(types.String).ValueString(); got != "forwarding" {
+		t.Errorf("stp_state = %q, want %q", got, "forwarding")
+	}
+	if got := len(first["connected_macs"].(types.List).Elements()); got != 2 {
+		t.Errorf("connected_macs has %d entries, want 2", got)
+	}
+	neighbor := first["lldp_neighbor"].(types.Object)
+	if neighbor.IsNull() {
+		t.Fatal("lldp_neighbor is null, want port 1's neighbor")
+	}
+	if got := neighbor.Attributes()["chassis_id"].(types.String).ValueString(); got != "22:22:22:22:22:22" {
+		t.Errorf("chassis_id = %q, want %q", got, "22:22:22:22:22:22")
+	}
+
+	second := value.Elements()[1].(types.Object).Attributes()
+	if !second["lldp_neighbor"].IsNull() {
+		t.Errorf("second lldp_neighbor = %s, want null", second["lldp_neighbor"])
+	}
+	if got := len(second["connected_macs"].(types.List).Elements()); got != 0 {
+		t.Errorf("second connected_macs has %d entries, want 0", got)
+	}
+}
+
+func Test_deviceUplinkValue(t *testing.T) {
+	if v, _ := deviceUplinkValue(nil); !v.IsNull() {
+		t.Errorf("deviceUplinkValue(nil) = %s, want null", v)
+	}
+	if v, _ := deviceUplinkValue(&deviceUplink{Type: "wire"}); !v.IsNull() {
+		t.Errorf("uplink without uplink_mac = %s, want null", v)
+	}
+
+	v, diags := deviceUplinkValue(&deviceUplink{
+		Type:             "wire",
+		PortIdx:          25,
+		Speed:            10000,
+		UplinkMAC:        "11:11:11:11:11:11",
+		UplinkDeviceName: "Core",
+		UplinkRemotePort: 3,
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	attrs := v.Attributes()
+	if got := attrs["name"].(types.String).ValueString(); got != "Core" {
+		t.Errorf("name = %q, want %q", got, "Core")
+	}
+	if got := attrs["port"].(types.Int64).ValueInt64(); got != 3 {
+		t.Errorf("port = %d, want 3", got)
+	}
+	if got := attrs["local_port"].(types.Int64).ValueInt64(); got != 25 {
+		t.Errorf("local_port = %d, want 25", got)
+	}
+}
@@ -45,6 +45,7 @@
 	_ resource.ResourceWithImportState  = &deviceResource{}
 	_ resource.ResourceWithIdentity     = &deviceResource{}
 	_ resource.ResourceWithUpgradeState = &deviceResource{}
+	_ resource.ResourceWithModifyPlan   = &deviceResource{}
 )
 
 // Ensure provider defined types fully satisfy list interfaces.
@@ -1068,6 +1069,32 @@ func (r *deviceResource) Configure(
 	r.client = client
 }
 
+func (r *deviceResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if r.client == nil || r.client.lockout == nil {
+		return
+	}
+
+	var plan *deviceResourceModel
+	if !req.Plan.Raw.IsNull() {
+		plan = &deviceResourceModel{}
+		resp.Diagnostics.Append(req.Plan.Get(ctx, plan)...)
+	}
+	var state *deviceResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &deviceResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.client.lockout.checkDevice(ctx, state, plan)...)
+}
+
 func (r *deviceResource) Create(
 	ctx context.Context,
 	req resource.CreateRequest,
@@ -1106,7 +1133,7 @@ func (r *deviceResource) Create(
 	device := new(unifi.Device)
 
 	err := retry.RetryContext(ctx, 2*time.Minute, func() *retry.RetryError {
-		d, err := r.client.GetDeviceByMAC(ctx, site, mac)
+		d, err := r.client.GetDeviceByMAC(withFreshReads(ctx), site, mac)
 		if err != nil {
 			return retry.RetryableError(err)
 		}
@@ -2708,6 +2735,8 @@ func (r *deviceResource) frameworkToPortOverrides(
 	return pos, diags
 }
 
+// waitForDeviceState waits for a device the resource manages, reading it with
+// GetDeviceByMAC.
 func (r *deviceResource) waitForDeviceState(
 	ctx context.Context,
 	site, mac string,
@@ -2715,52 +2744,28 @@ func (r *deviceResource) waitForDeviceState(
 	pendingStates []unifi.DeviceState,
 	timeout time.Duration,
 ) (*unifi.Device, error) {
-	// Always consider unknown to be a pending state.
-	pendingStates = append(pendingStates, unifi.DeviceStateUnknown)
-
-	var pending []string
-	for _, state := range pendingStates {
-		pending = append(pending, state.String())
-	}
+	read := func(ctx context.Context) (*unifi.Device, unifi.DeviceState, bool, error) {
+		// The state changes on the controller's side, so every poll must
+		// reach it rather than the read cache.
+		device, err := r.client.GetDeviceByMAC(withFreshReads(ctx), site, mac)
 
-	wait := retry.StateChangeConf{
-		Pending: pending,
-		Target:  []string{targetState.String()},
-		Refresh: func() (any, string, error) {
-			device, err := r.client.GetDeviceByMAC(ctx, site, mac)
-
-			if _, ok := err.(*unifi.NotFoundError); ok {
-				err = nil
-			}
-
-			// When a device is forgotten, it will disappear from the UI for a few seconds before reappearing.
-			// During this time, `device.GetDeviceByMAC` will return a 400.
-			if err != nil && strings.Contains(err.Error(), "api.err.UnknownDevice") {
-				err = nil
-			}
-
-			var state string
-			if device != nil {
-				state = device.State.String()
-			}
-
-			if device == nil {
-				return nil, state, err
-			}
-
-			return device, state, err
-		},
-		Timeout:        timeout,
-		NotFoundChecks: 30,
-	}
+		if _, ok := err.(*unifi.NotFoundError); ok {
+			err = nil
+		}
 
-	outputRaw, err := wait.WaitForStateContext(ctx)
+		// When a device is forgotten, it will disappear from the UI for a few seconds before reappearing.
+		// During this time, `device.GetDeviceByMAC` will return a 400.
+		if err != nil && strings.Contains(err.Error(), "api.err.UnknownDevice") {
+			err = nil
+		}
 
-	if output, ok := outputRaw.(*unifi.Device); ok {
-		return output, err
+		if device == nil {
+			return nil, 0, false, err
+		}
+		return device, device.State, true, err
 	}
 
-	return nil, err
+	return waitForDeviceState(ctx, read, targetState, pendingStates, timeout, nil)
 }
 
 // cleanMAC normalizes MAC address format.
@@ -307,6 +307,8 @@ func Test_buildMinimalUpdateDevice_meshStaVapEnabled(t *testing.T) {
 }
 
 func TestAccDeviceFramework_basic(t *testing.T) {
+	skipOnFakeController(t, "needs an adopted device, which the fake does not simulate")
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { preCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -0,0 +1,180 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action              = &deviceRestartAction{}
+	_ action.ActionWithConfigure = &deviceRestartAction{}
+)
+
+// NewDeviceRestartAction returns a new instance of the device restart action.
+func NewDeviceRestartAction() action.Action {
+	return &deviceRestartAction{}
+}
+
+type deviceRestartAction struct {
+	client *Client
+}
+
+// deviceRestartActionModel describes the action request data model.
+type deviceRestartActionModel struct {
+	DeviceMAC  hwtypes.MACAddress `tfsdk:"device_mac"`
+	RebootType types.String       `tfsdk:"reboot_type"`
+	Timeouts   timeouts.Value     `tfsdk:"timeouts"`
+}
+
+func (a *deviceRestartAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_device_restart"
+}
+
+func (a *deviceRestartAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Restarts a UniFi device and waits until it is connected to the controller " +
+			"again. " + directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"device_mac": schema.StringAttribute{
+				MarkdownDescription: "MAC address of the device to restart.",
+				CustomType:          hwtypes.MACAddressType{},
+				Required:            true,
+			},
+			"reboot_type": schema.StringAttribute{
+				MarkdownDescription: "How to restart the device. `soft` reboots it; `hard` power-cycles the " +
+					"PoE port that powers it, which only works for devices powered by a UniFi switch. " +
+					"Defaults to `soft`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("soft", "hard"),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *deviceRestartAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *deviceRestartAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_device_restart")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config deviceRestartActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	mac, err := normalizeMAC(config.DeviceMAC.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Device MAC Address", err.Error())
+		return
+	}
+	rebootType := config.RebootType.ValueString()
+	if rebootType == "" {
+		rebootType = "soft"
+	}
+	site := a.client.Site
+
+	before, err := a.client.getDeviceStatus(ctx, site, mac)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Finding Device",
+			fmt.Sprintf("Could not find device with MAC address %s: %s", mac, err.Error()),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Restarting %s (%s restart)", before.displayName(), rebootType),
+	})
+
+	sentAt := time.Now()
+	err = a.client.executeCommand(ctx, site, "devmgr", map[string]any{
+		"cmd":         "restart",
+		"mac":         mac,
+		"reboot_type": rebootType,
+	}, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Restarting Device",
+			fmt.Sprintf("Could not restart device %s: %s", mac, err.Error()),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Waiting for %s to reconnect", before.displayName()),
+	})
+
+	if _, err := a.client.waitForDeviceRestart(ctx, site, mac, sentAt, nil); err != nil {
+		resp.Diagnostics.AddError(
+			"Error Waiting for Device",
+			fmt.Sprintf("Device %s was restarted but did not reconnect: %s", mac, err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Device restarted", map[string]any{
+		"mac":         mac,
+		"reboot_type": rebootType,
+		"duration":    time.Since(sentAt).String(),
+	})
+}
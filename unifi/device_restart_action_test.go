@@ -0,0 +1,60 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+func TestNewDeviceRestartAction(t *testing.T) {
+	got := NewDeviceRestartAction()
+	if got == nil {
+		t.Fatal("NewDeviceRestartAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_deviceRestartAction_Metadata(t *testing.T) {
+	a := &deviceRestartAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_device_restart" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_device_restart")
+	}
+}
+
+func Test_deviceRestartAction_Schema(t *testing.T) {
+	a := &deviceRestartAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"device_mac", "reboot_type", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_deviceRestartAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &deviceRestartAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ui "github.com/ubiquiti-community/go-unifi/unifi"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util/retry"
+)
+
+// devicePollInterval is how often device state is polled while waiting.
+var devicePollInterval = 5 * time.Second
+
+// deviceStatus is the part of a `stat/device` entry the device actions and
+// the device data source need.
+type deviceStatus struct {
+	ID                string         `json:"_id"`
+	MAC               string         `json:"mac"`
+	Name              string         `json:"name"`
+	Type              string         `json:"type"`
+	Model             string         `json:"model"`
+	Adopted           bool           `json:"adopted"`
+	IP                string         `json:"ip"`
+	State             ui.DeviceState `json:"state"`
+	Uptime            int64          `json:"uptime"`
+	Version           string         `json:"version"`
+	Upgradable        bool           `json:"upgradable"`
+	UpgradeToFirmware string         `json:"upgrade_to_firmware"`
+	Uplink            *deviceUplink  `json:"uplink"`
+	PortTable         []devicePort   `json:"port_table"`
+	LLDPTable         []lldpNeighbor `json:"lldp_table"`
+	RadioTableStats   []radioStats   `json:"radio_table_stats"`
+}
+
+// deviceUplink is a device's `uplink`, the link towards the gateway.
+type deviceUplink struct {
+	Type             string `json:"type"`
+	PortIdx          int64  `json:"port_idx"`
+	Speed            int64  `json:"speed"`
+	UplinkMAC        string `json:"uplink_mac"`
+	UplinkDeviceName string `json:"uplink_device_name"`
+	UplinkRemotePort int64  `json:"uplink_remote_port"`
+}
+
+// devicePort is an entry of a device's `port_table`.
+type devicePort struct {
+	PortIdx    int64          `json:"port_idx"`
+	Name       string         `json:"name"`
+	Up         bool           `json:"up"`
+	Speed      int64          `json:"speed"`
+	FullDuplex bool           `json:"full_duplex"`
+	PortPoe    bool           `json:"port_poe"`
+	PoePower   jsonFloat      `json:"poe_power"`
+	StpState   string         `json:"stp_state"`
+	MACTable   []portMACEntry `json:"mac_table"`
+}
+
+// portMACEntry is an entry of a port's `mac_table`, a client learned on the
+// port.
+type portMACEntry struct {
+	MAC string `json:"mac"`
+}
+
+// lldpNeighbor is an entry of a device's `lldp_table`.
+type lldpNeighbor struct {
+	LocalPortIdx int64  `json:"local_port_idx"`
+	ChassisID    string `json:"chassis_id"`
+	PortID       string `json:"port_id"`
+}
+
+// radioStats is an entry of a device's `radio_table_stats`, the live state of
+// a radio.
+type radioStats struct {
+	Name    string `json:"name"`
+	Radio   string `json:"radio"`
+	Channel int64  `json:"channel"`
+	TxPower int64  `json:"tx_power"`
+	CuTotal int64  `json:"cu_total"`
+	NumSta  int64  `json:"num_sta"`
+}
+
+// jsonFloat is a number the controller sends either as a JSON number or as a
+// string, such as a port's `poe_power`. An empty string decodes as zero.
+type jsonFloat float64
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s: %w", b, err)
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+func (n *lldpNeighbor) displayName() string {
+	if n.PortID != "" {
+		return n.ChassisID + " port " + n.PortID
+	}
+	return n.ChassisID
+}
+
+func (d *deviceStatus) displayName() string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return d.MAC
+}
+
+// errDeviceNotFound is returned by getDeviceStatus for a device the
+// controller does not know.
+var errDeviceNotFound = errors.New("device not found")
+
+// getDeviceStatus reads the live status of a device, bypassing the read
+// cache.
+func (c *Client) getDeviceStatus(ctx context.Context, site, mac string) (*deviceStatus, error) {
+	var devices []deviceStatus
+	err := c.controllerRequest(
+		withFreshReads(ctx),
+		http.MethodGet,
+		"/api/s/"+url.PathEscape(site)+"/stat/device/"+url.PathEscape(mac),
+		nil,
+		&devices,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("%w: %s", errDeviceNotFound, mac)
+	}
+	return &devices[0], nil
+}
+
+// getDeviceSummary reads the device with mac through go-unifi's
+// GetDeviceByMAC, which works with every connection type. The device is
+// converted through its JSON form, which uses the controller's field names.
+// The live tables are left out.
+func (c *Client) getDeviceSummary(ctx context.Context, site, mac string) (*deviceStatus, error) {
+	device, err := c.GetDeviceByMAC(ctx, site, mac)
+	if err != nil {
+		var notFound *ui.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s", errDeviceNotFound, mac)
+		}
+		return nil, err
+	}
+	b, err := json.Marshal(device)
+	if err != nil {
+		return nil, err
+	}
+	var status deviceStatus
+	if err := json.Unmarshal(b, &status); err != nil {
+		return nil, err
+	}
+	status.Uplink, status.PortTable, status.LLDPTable, status.RadioTableStats = nil, nil, nil, nil
+	return &status, nil
+}
+
+// deviceStateWaiting is the pending state waitForDeviceState reports while
+// ready holds a device back.
+const deviceStateWaiting = "waiting"
+
+// waitForDeviceState polls read until the device reaches targetState, or
+// timeout passes. pendingStates, and the unknown state, are expected on the
+// way; any other state ends the wait with an error. read reports ok = false
+// while the controller does not know the device, which is tolerated for a
+// while: a forgotten device disappears for a few seconds before it reappears.
+//
+// If ready is not nil, it is called with every device read. A device it
+// returns false for is still waited for, whatever its state, and an error it
+// returns ends the wait.
+func waitForDeviceState[D any](
+	ctx context.Context,
+	read func(context.Context) (device D, state ui.DeviceState, ok bool, err error),
+	targetState ui.DeviceState,
+	pendingStates []ui.DeviceState,
+	timeout time.Duration,
+	ready func(D) (bool, error),
+) (D, error) {
+	// Always consider unknown to be a pending state.
+	pending := []string{deviceStateWaiting, ui.DeviceStateUnknown.String()}
+	for _, state := range pendingStates {
+		pending = append(pending, state.String())
+	}
+
+	var (
+		// lastState is read after a timeout, while a refresh may still be
+		// running.
+		mu        sync.Mutex
+		lastState *ui.DeviceState
+	)
+
+	wait := retry.StateChangeConf{
+		Pending: pending,
+		Target:  []string{targetState.String()},
+		Refresh: func() (any, string, error) {
+			device, state, ok, err := read(ctx)
+			if err != nil || !ok {
+				return nil, "", err
+			}
+			mu.Lock()
+			lastState = &state
+			mu.Unlock()
+			if ready != nil {
+				done, err := ready(device)
+				if err != nil {
+					return device, "", err
+				}
+				if !done {
+					return device, deviceStateWaiting, nil
+				}
+			}
+			return device, state.String(), nil
+		},
+		Timeout:        timeout,
+		PollInterval:   devicePollInterval,
+		NotFoundChecks: 30,
+	}
+
+	out, err := wait.WaitForStateContext(ctx)
+	var timeoutErr *retry.TimeoutError
+	if errors.As(err, &timeoutErr) || errors.Is(err, context.DeadlineExceeded) {
+		mu.Lock()
+		if lastState != nil {
+			err = fmt.Errorf("%w; the device last reported state %s", err, *lastState)
+		}
+		mu.Unlock()
+	}
+	device, _ := out.(D)
+	return device, err
+}
+
+// readDeviceStatus reads a device's live status for waitForDeviceState.
+func (c *Client) readDeviceStatus(
+	site, mac string,
+) func(context.Context) (*deviceStatus, ui.DeviceState, bool, error) {
+	return func(ctx context.Context) (*deviceStatus, ui.DeviceState, bool, error) {
+		d, err := c.getDeviceStatus(ctx, site, mac)
+		if errors.Is(err, errDeviceNotFound) {
+			return nil, 0, false, nil
+		}
+		if err != nil {
+			return nil, 0, false, err
+		}
+		return d, d.State, true, nil
+	}
+}
+
+// waitForDeviceRestart waits until a device restarted at sentAt is connected
+// again, or ctx is done. A device counts as restarted once it has been seen in
+// any other state, such as upgrading or provisioning, or reports an uptime
+// shorter than the time since sentAt; the controller can take longer to notice
+// a short outage than the device takes to come back.
+//
+// If accept is not nil, it is called with the reconnected device, and an error
+// it returns ends the wait.
+func (c *Client) waitForDeviceRestart(
+	ctx context.Context,
+	site, mac string,
+	sentAt time.Time,
+	accept func(*deviceStatus) error,
+) (*deviceStatus, error) {
+	var sawRestart bool
+	return waitForDeviceState(
+		ctx,
+		c.readDeviceStatus(site, mac),
+		ui.DeviceStateConnected,
+		nil,
+		timeUntilDeadline(ctx),
+		func(d *deviceStatus) (bool, error) {
+			if d.State != ui.DeviceStateConnected {
+				sawRestart = true
+				return false, nil
+			}
+			uptime := time.Duration(d.Uptime) * time.Second
+			if !sawRestart && (d.Uptime == 0 || uptime >= time.Since(sentAt)) {
+				return false, nil
+			}
+			if accept != nil {
+				if err := accept(d); err != nil {
+					return false, err
+				}
+			}
+			return true, nil
+		},
+	)
+}
+
+// timeUntilDeadline returns the time left before ctx's deadline, or a day if
+// it has none.
+func timeUntilDeadline(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,229 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ui "github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+// deviceStatusSequence serves `stat/device/{mac}` from a list of statuses,
+// repeating the last one.
+func deviceStatusSequence(t *testing.T, statuses ...deviceStatus) *Client {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/s/default/stat/device/aa:bb:cc:dd:ee:ff" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		d := statuses[min(polls, len(statuses)-1)]
+		polls++
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"meta": map[string]any{"rc": "ok"},
+			"data": []deviceStatus{d},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+}
+
+func Test_waitForDeviceState(t *testing.T) {
+	interval := devicePollInterval
+	devicePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { devicePollInterval = interval })
+
+	// sequence reads the given states in turn, repeating the last one. A
+	// negative state reads as a device the controller does not know.
+	sequence := func(states ...int) func(context.Context) (int, ui.DeviceState, bool, error) {
+		var polls int
+		return func(context.Context) (int, ui.DeviceState, bool, error) {
+			state := states[min(polls, len(states)-1)]
+			polls++
+			if state < 0 {
+				return 0, 0, false, nil
+			}
+			return polls, ui.DeviceState(state), true, nil
+		}
+	}
+	pending := []ui.DeviceState{ui.DeviceStateAdopting, ui.DeviceStateProvisioning}
+	connected := int(ui.DeviceStateConnected)
+
+	t.Run("pending states and absence are waited out", func(t *testing.T) {
+		read := sequence(-1, int(ui.DeviceStateAdopting), int(ui.DeviceStateProvisioning), connected)
+		polls, err := waitForDeviceState(
+			context.Background(),
+			read,
+			ui.DeviceStateConnected,
+			pending,
+			time.Second,
+			nil,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if polls != 4 {
+			t.Errorf("reached the target after %d polls, want 4", polls)
+		}
+	})
+
+	t.Run("unexpected state", func(t *testing.T) {
+		read := sequence(int(ui.DeviceStateDeleting))
+		_, err := waitForDeviceState(context.Background(), read, ui.DeviceStateConnected, pending, time.Second, nil)
+		if err == nil {
+			t.Error("expected an error for a state that is neither pending nor the target")
+		}
+	})
+
+	t.Run("ready holds the device back", func(t *testing.T) {
+		read := sequence(connected)
+		polls, err := waitForDeviceState(
+			context.Background(),
+			read,
+			ui.DeviceStateConnected,
+			nil,
+			time.Second,
+			func(polls int) (bool, error) { return polls >= 3, nil },
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if polls != 3 {
+			t.Errorf("reached the target after %d polls, want 3", polls)
+		}
+	})
+
+	t.Run("timeout names the last state", func(t *testing.T) {
+		read := sequence(int(ui.DeviceStateProvisioning))
+		_, err := waitForDeviceState(
+			context.Background(),
+			read,
+			ui.DeviceStateConnected,
+			pending,
+			100*time.Millisecond,
+			nil,
+		)
+		if err == nil || !strings.Contains(err.Error(), ui.DeviceStateProvisioning.String()) {
+			t.Errorf("error = %v, want the last state", err)
+		}
+	})
+}
+
+func TestClient_waitForDeviceRestart(t *testing.T) {
+	interval := devicePollInterval
+	devicePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { devicePollInterval = interval })
+
+	const mac = "aa:bb:cc:dd:ee:ff"
+	online := deviceStatus{MAC: mac, State: ui.DeviceStateConnected, Uptime: 86400}
+	offline := deviceStatus{MAC: mac, State: 0}
+	rebooted := deviceStatus{MAC: mac, State: ui.DeviceStateConnected, Uptime: 30}
+
+	t.Run("seen disconnected", func(t *testing.T) {
+		c := deviceStatusSequence(t, online, online, offline, offline, online)
+		d, err := c.waitForDeviceRestart(context.Background(), "default", mac, time.Now(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.State != ui.DeviceStateConnected {
+			t.Errorf("state = %d, want connected", d.State)
+		}
+	})
+
+	t.Run("uptime reset", func(t *testing.T) {
+		c := deviceStatusSequence(t, online, rebooted)
+		if _, err := c.waitForDeviceRestart(context.Background(), "default", mac, time.Now().Add(-time.Minute), nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		c := deviceStatusSequence(t, online, offline, online)
+		_, err := c.waitForDeviceRestart(context.Background(), "default", mac, time.Now(), func(d *deviceStatus) error {
+			return errors.New("wrong version")
+		})
+		if err == nil || !strings.Contains(err.Error(), "wrong version") {
+			t.Errorf("error = %v, want the accept error", err)
+		}
+	})
+
+	t.Run("never restarts", func(t *testing.T) {
+		c := deviceStatusSequence(t, online)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		if _, err := c.waitForDeviceRestart(ctx, "default", mac, time.Now(), nil); err == nil {
+			t.Error("expected a timeout")
+		}
+	})
+}
+
+func TestClient_getDeviceStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := []map[string]any{}
+		if r.URL.Path == "/api/s/default/stat/device/aa:bb:cc:dd:ee:ff" {
+			data = append(data, map[string]any{
+				"_id":     "d1",
+				"mac":     "aa:bb:cc:dd:ee:ff",
+				"type":    "usw",
+				"model":   "US8P60",
+				"adopted": true,
+				"state":   1,
+				"version": "7.1.26",
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"meta": map[string]any{"rc": "ok"},
+			"data": data,
+		})
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+
+	d, err := c.getDeviceStatus(context.Background(), "default", "aa:bb:cc:dd:ee:ff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != "d1" || d.Type != "usw" || !d.Adopted || d.State != ui.DeviceStateConnected || d.Version != "7.1.26" {
+		t.Errorf("getDeviceStatus() = %+v", d)
+	}
+
+	if _, err := c.getDeviceStatus(context.Background(), "default", "aa:bb:cc:dd:ee:00"); !errors.Is(err, errDeviceNotFound) {
+		t.Errorf("error = %v, want %v", err, errDeviceNotFound)
+	}
+
+	cloud := &Client{controller: controllerInfo{Console: consoleTypeCloudConnector}}
+	if _, err := cloud.getDeviceStatus(context.Background(), "default", "aa:bb:cc:dd:ee:ff"); !errors.Is(err, errNoControllerURL) {
+		t.Errorf("error with cloud_connector = %v, want %v", err, errNoControllerURL)
+	}
+}
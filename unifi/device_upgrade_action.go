@@ -0,0 +1,320 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action              = &deviceUpgradeAction{}
+	_ action.ActionWithConfigure = &deviceUpgradeAction{}
+)
+
+const defaultUpgradeBatchTimeout = 30 * time.Minute
+
+// NewDeviceUpgradeAction returns a new instance of the device upgrade action.
+func NewDeviceUpgradeAction() action.Action {
+	return &deviceUpgradeAction{}
+}
+
+type deviceUpgradeAction struct {
+	client *Client
+}
+
+// deviceUpgradeActionModel describes the action request data model.
+type deviceUpgradeActionModel struct {
+	DeviceMACs      types.List           `tfsdk:"device_macs"`
+	FirmwareURL     types.String         `tfsdk:"firmware_url"`
+	FirmwareVersion types.String         `tfsdk:"firmware_version"`
+	BatchSize       types.Int64          `tfsdk:"batch_size"`
+	BatchTimeout    timetypes.GoDuration `tfsdk:"batch_timeout"`
+	Timeouts        timeouts.Value       `tfsdk:"timeouts"`
+}
+
+func (a *deviceUpgradeAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_device_upgrade"
+}
+
+func (a *deviceUpgradeAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Upgrades the firmware of UniFi devices in rolling batches. Each batch is " +
+			"upgraded together, and the next batch only starts once every device in it has " +
+			"reconnected on the expected firmware. The rollout stops at the first batch with a " +
+			"device that cannot be started or does not come back; the devices of that batch that " +
+			"were already started are still waited for and reported. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"device_macs": schema.ListAttribute{
+				MarkdownDescription: "MAC addresses of the devices to upgrade, in rollout order.",
+				ElementType:         hwtypes.MACAddressType{},
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+				},
+			},
+			"firmware_url": schema.StringAttribute{
+				MarkdownDescription: "URL of a firmware image to install. When unset, each device is upgraded " +
+					"to the latest firmware the controller offers for it, and devices that are already " +
+					"up to date are skipped.",
+				Optional: true,
+			},
+			"firmware_version": schema.StringAttribute{
+				MarkdownDescription: "Version the devices must report after installing `firmware_url`. A " +
+					"version without the build number, such as `6.6.55`, matches any build of it, such as " +
+					"`6.6.55.15189`. When unset, any version is accepted once the device has restarted. " +
+					"Ignored without `firmware_url`, where the version the controller offers is expected.",
+				Optional: true,
+			},
+			"batch_size": schema.Int64Attribute{
+				MarkdownDescription: "Number of devices upgraded at the same time. Defaults to `1`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"batch_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for the devices in a batch to reconnect on the new " +
+					"firmware before stopping the rollout, as a Go duration string (e.g. `20m`). " +
+					"Defaults to `30m`.",
+				CustomType: timetypes.GoDurationType{},
+				Optional:   true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *deviceUpgradeAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *deviceUpgradeAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_device_upgrade")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config deviceUpgradeActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 2*time.Hour)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	var rawMACs []string
+	resp.Diagnostics.Append(config.DeviceMACs.ElementsAs(ctx, &rawMACs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	macs := make([]string, 0, len(rawMACs))
+	for _, raw := range rawMACs {
+		mac, err := normalizeMAC(raw)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Device MAC Address", err.Error())
+			return
+		}
+		macs = append(macs, mac)
+	}
+
+	batchSize := 1
+	if !config.BatchSize.IsNull() {
+		batchSize = int(config.BatchSize.ValueInt64())
+	}
+	batchTimeout := defaultUpgradeBatchTimeout
+	if !config.BatchTimeout.IsNull() {
+		d, diags := config.BatchTimeout.ValueGoDuration()
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		batchTimeout = d
+	}
+
+	rollout := deviceUpgradeRollout{
+		client:          a.client,
+		site:            a.client.Site,
+		firmwareURL:     config.FirmwareURL.ValueString(),
+		firmwareVersion: config.FirmwareVersion.ValueString(),
+		batchTimeout:    batchTimeout,
+		progress: func(msg string) {
+			resp.SendProgress(action.InvokeProgressEvent{Message: msg})
+		},
+	}
+	upgraded, err := rollout.run(ctx, macs, batchSize)
+	if err != nil {
+		detail := err.Error()
+		if len(upgraded) > 0 {
+			detail += fmt.Sprintf("\n\nDevices upgraded before the rollout stopped: %s.", strings.Join(upgraded, ", "))
+		}
+		resp.Diagnostics.AddError("Firmware Upgrade Failed", detail)
+		return
+	}
+
+	tflog.Debug(ctx, "Firmware rollout finished", map[string]any{
+		"upgraded": upgraded,
+	})
+}
+
+// deviceUpgradeRollout upgrades devices in batches.
+type deviceUpgradeRollout struct {
+	client          *Client
+	site            string
+	firmwareURL     string
+	firmwareVersion string
+	batchTimeout    time.Duration
+	progress        func(string)
+}
+
+// run upgrades macs in batches of batchSize and returns the devices it
+// upgraded. It stops at the first batch in which a device fails.
+func (r *deviceUpgradeRollout) run(ctx context.Context, macs []string, batchSize int) ([]string, error) {
+	var upgraded []string
+	batches := (len(macs) + batchSize - 1) / batchSize
+
+	for i := 0; i < len(macs); i += batchSize {
+		batch := macs[i:min(i+batchSize, len(macs))]
+		r.progress(fmt.Sprintf("Upgrading batch %d of %d: %s", i/batchSize+1, batches, strings.Join(batch, ", ")))
+
+		done, notSent, err := r.upgradeBatch(ctx, batch)
+		upgraded = append(upgraded, done...)
+		if err != nil {
+			rest := append(append([]string(nil), notSent...), macs[i+len(batch):]...)
+			if len(rest) > 0 {
+				err = fmt.Errorf("%w\n\nNot attempted: %s.", err, strings.Join(rest, ", "))
+			}
+			return upgraded, err
+		}
+	}
+	return upgraded, nil
+}
+
+// upgradeBatch starts the upgrade of every device in batch, then waits for
+// each of them, and returns the devices it upgraded and those it never sent
+// the upgrade to. When a device cannot be started the rest of the batch is not
+// sent, but the devices already started are still waited for, since they may
+// be rebooting.
+func (r *deviceUpgradeRollout) upgradeBatch(
+	ctx context.Context,
+	batch []string,
+) ([]string, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.batchTimeout)
+	defer cancel()
+
+	type started struct {
+		device *deviceStatus
+		target string
+		sentAt time.Time
+	}
+	var (
+		waiting  []started
+		failures []string
+		notSent  []string
+		upgraded []string
+	)
+
+	for i, mac := range batch {
+		d, err := r.client.getDeviceStatus(ctx, r.site, mac)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: could not read the device: %s", mac, err))
+			notSent = batch[i+1:]
+			break
+		}
+
+		cmd := map[string]any{"mac": mac}
+		target := r.firmwareVersion
+		if r.firmwareURL != "" {
+			cmd["cmd"] = "upgrade-external"
+			cmd["url"] = r.firmwareURL
+		} else {
+			if !d.Upgradable || d.UpgradeToFirmware == "" {
+				r.progress(fmt.Sprintf("%s is up to date on %s", d.displayName(), d.Version))
+				continue
+			}
+			cmd["cmd"] = "upgrade"
+			target = d.UpgradeToFirmware
+		}
+
+		sentAt := time.Now()
+		if err := r.client.executeCommand(ctx, r.site, "devmgr", cmd, nil); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: could not start the upgrade: %s", d.displayName(), err))
+			notSent = batch[i+1:]
+			break
+		}
+		waiting = append(waiting, started{device: d, target: target, sentAt: sentAt})
+	}
+
+	for _, s := range waiting {
+		d, err := r.client.waitForDeviceRestart(ctx, r.site, s.device.MAC, s.sentAt, func(d *deviceStatus) error {
+			if s.target != "" && !versionMatches(d.Version, s.target) {
+				return fmt.Errorf("reconnected on firmware %s instead of %s", d.Version, s.target)
+			}
+			return nil
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", s.device.displayName(), err))
+			continue
+		}
+		r.progress(fmt.Sprintf("%s upgraded from %s to %s", d.displayName(), s.device.Version, d.Version))
+		upgraded = append(upgraded, s.device.MAC)
+	}
+
+	if len(failures) > 0 {
+		return upgraded, notSent, fmt.Errorf("the upgrade failed for:\n- %s", strings.Join(failures, "\n- "))
+	}
+	return upgraded, nil, nil
+}
@@ -0,0 +1,270 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+	ui "github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+func TestNewDeviceUpgradeAction(t *testing.T) {
+	got := NewDeviceUpgradeAction()
+	if got == nil {
+		t.Fatal("NewDeviceUpgradeAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_deviceUpgradeAction_Metadata(t *testing.T) {
+	a := &deviceUpgradeAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_device_upgrade" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_device_upgrade")
+	}
+}
+
+func Test_deviceUpgradeAction_Schema(t *testing.T) {
+	a := &deviceUpgradeAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"device_macs", "firmware_url", "firmware_version", "batch_size", "batch_timeout", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_deviceUpgradeAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &deviceUpgradeAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+// upgradeController fakes the device upgrade commands. An upgraded device
+// reports the upgrading state for two polls, then reconnects on its new
+// firmware, unless it is listed in stuck. Upgrades of devices listed in
+// rejected fail.
+type upgradeController struct {
+	mu       sync.Mutex
+	devices  map[string]*deviceStatus
+	polls    map[string]int
+	stuck    map[string]bool
+	rejected map[string]bool
+	commands []map[string]any
+}
+
+func (u *upgradeController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/s/default/cmd/devmgr":
+		var cmd map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&cmd)
+		u.commands = append(u.commands, cmd)
+		mac, _ := cmd["mac"].(string)
+		if u.rejected[mac] {
+			_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"rc": "error", "msg": "api.err.Busy"}})
+			return
+		}
+		d := u.devices[mac]
+		d.State = ui.DeviceStateUpgrading
+		if url, ok := cmd["url"].(string); ok {
+			d.UpgradeToFirmware = strings.TrimSuffix(url[strings.LastIndex(url, "/")+1:], ".bin")
+		}
+		u.polls[mac] = 0
+	case strings.HasPrefix(r.URL.Path, "/api/s/default/stat/device/"):
+		mac := strings.TrimPrefix(r.URL.Path, "/api/s/default/stat/device/")
+		d := u.devices[mac]
+		if d.State == ui.DeviceStateUpgrading && !u.stuck[mac] {
+			if u.polls[mac]++; u.polls[mac] > 2 {
+				*d = deviceStatus{
+					MAC:     d.MAC,
+					Name:    d.Name,
+					State:   ui.DeviceStateConnected,
+					Uptime:  1,
+					Version: d.UpgradeToFirmware,
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"rc": "ok"}, "data": []deviceStatus{*d}})
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"rc": "ok"}, "data": []any{}})
+}
+
+func newUpgradeRollout(t *testing.T, u *upgradeController) (*deviceUpgradeRollout, *[]string) {
+	t.Helper()
+	interval := devicePollInterval
+	devicePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { devicePollInterval = interval })
+
+	srv := httptest.NewServer(u)
+	t.Cleanup(srv.Close)
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	return &deviceUpgradeRollout{
+		client: &Client{
+			Site:       "default",
+			controller: controllerInfo{APIBaseURL: srv.URL},
+			httpClient: httpClient,
+		},
+		site:         "default",
+		batchTimeout: 2 * time.Second,
+		progress:     func(msg string) { messages = append(messages, msg) },
+	}, &messages
+}
+
+func newUpgradeController(macs ...string) *upgradeController {
+	u := &upgradeController{
+		devices:  map[string]*deviceStatus{},
+		polls:    map[string]int{},
+		stuck:    map[string]bool{},
+		rejected: map[string]bool{},
+	}
+	for _, mac := range macs {
+		u.devices[mac] = &deviceStatus{
+			MAC:               mac,
+			State:             ui.DeviceStateConnected,
+			Uptime:            86400,
+			Version:           "6.6.55",
+			Upgradable:        true,
+			UpgradeToFirmware: "6.6.77",
+		}
+	}
+	return u
+}
+
+func Test_deviceUpgradeRollout(t *testing.T) {
+	const (
+		ap1 = "aa:00:00:00:00:01"
+		ap2 = "aa:00:00:00:00:02"
+		ap3 = "aa:00:00:00:00:03"
+		ap4 = "aa:00:00:00:00:04"
+	)
+
+	t.Run("latest firmware in batches", func(t *testing.T) {
+		u := newUpgradeController(ap1, ap2, ap3)
+		u.devices[ap2].Upgradable = false
+		u.devices[ap2].UpgradeToFirmware = ""
+		r, messages := newUpgradeRollout(t, u)
+
+		upgraded, err := r.run(context.Background(), []string{ap1, ap2, ap3}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(upgraded, ",") != ap1+","+ap3 {
+			t.Errorf("upgraded = %v, want %s and %s", upgraded, ap1, ap3)
+		}
+		for _, mac := range []string{ap1, ap3} {
+			if v := u.devices[mac].Version; v != "6.6.77" {
+				t.Errorf("%s version = %s, want 6.6.77", mac, v)
+			}
+		}
+		if len(u.commands) != 2 || u.commands[0]["cmd"] != "upgrade" {
+			t.Errorf("commands = %v, want two upgrades", u.commands)
+		}
+		if !strings.Contains(strings.Join(*messages, "\n"), "Upgrading batch 2 of 2") {
+			t.Errorf("progress = %v, want a message per batch", *messages)
+		}
+	})
+
+	t.Run("custom firmware", func(t *testing.T) {
+		u := newUpgradeController(ap1)
+		r, _ := newUpgradeRollout(t, u)
+		r.firmwareURL = "https://fw.example.com/6.7.1.bin"
+		r.firmwareVersion = "6.7.1"
+
+		if _, err := r.run(context.Background(), []string{ap1}, 1); err != nil {
+			t.Fatal(err)
+		}
+		if u.commands[0]["cmd"] != "upgrade-external" || u.commands[0]["url"] != r.firmwareURL {
+			t.Errorf("command = %v, want upgrade-external with the URL", u.commands[0])
+		}
+	})
+
+	t.Run("wrong version", func(t *testing.T) {
+		u := newUpgradeController(ap1)
+		r, _ := newUpgradeRollout(t, u)
+		r.firmwareURL = "https://fw.example.com/6.7.0.bin"
+		r.firmwareVersion = "6.7.1"
+
+		_, err := r.run(context.Background(), []string{ap1}, 1)
+		if err == nil || !strings.Contains(err.Error(), "instead of 6.7.1") {
+			t.Errorf("error = %v, want a version mismatch", err)
+		}
+	})
+
+	t.Run("device does not return", func(t *testing.T) {
+		u := newUpgradeController(ap1, ap2, ap3, ap4)
+		u.stuck[ap3] = true
+		r, _ := newUpgradeRollout(t, u)
+		r.batchTimeout = 300 * time.Millisecond
+
+		upgraded, err := r.run(context.Background(), []string{ap1, ap2, ap3, ap4}, 1)
+		if err == nil {
+			t.Fatal("expected the rollout to stop")
+		}
+		if strings.Join(upgraded, ",") != ap1+","+ap2 {
+			t.Errorf("upgraded = %v, want the devices before the stuck one", upgraded)
+		}
+		if !strings.Contains(err.Error(), ap3) || !strings.Contains(err.Error(), "Not attempted: "+ap4) {
+			t.Errorf("error = %v, want it to name the stuck and skipped devices", err)
+		}
+		if got := u.devices[ap4].Version; got != "6.6.55" {
+			t.Errorf("%s was upgraded after the rollout stopped", ap4)
+		}
+	})
+	t.Run("upgrade rejected mid-batch", func(t *testing.T) {
+		u := newUpgradeController(ap1, ap2, ap3, ap4)
+		u.rejected[ap2] = true
+		r, _ := newUpgradeRollout(t, u)
+
+		upgraded, err := r.run(context.Background(), []string{ap1, ap2, ap3, ap4}, 3)
+		if err == nil {
+			t.Fatal("expected the rollout to stop")
+		}
+		if strings.Join(upgraded, ",") != ap1 {
+			t.Errorf("upgraded = %v, want %s, which was started before the rejection", upgraded, ap1)
+		}
+		if !strings.Contains(err.Error(), "api.err.Busy") || !strings.Contains(err.Error(), "Not attempted: "+ap3+", "+ap4) {
+			t.Errorf("error = %v, want the rejection and both unsent devices", err)
+		}
+		if len(u.commands) != 2 {
+			t.Errorf("commands = %v, want no upgrade sent after the rejection", u.commands)
+		}
+	})
+}
@@ -0,0 +1,428 @@
+package fakecontroller
+
+import (
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// objectIDPattern matches the 24 character hex IDs the controller assigns.
+var objectIDPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// statCollections maps the read-only `stat/{name}` endpoints onto the store
+// collection that backs them.
+var statCollections = map[string]string{
+	"device":  "device",
+	"sta":     "user",
+	"alluser": "user",
+}
+
+// handleV1 serves `/api/s/{site}/{kind}/...`, where parts is the path split
+// after `/api/s/`.
+func (s *Server) handleV1(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) < 2 {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+
+	site, kind, rest := parts[0], parts[1], parts[2:]
+	if !s.store.hasSite(site) {
+		writeError(w, http.StatusBadRequest, "api.err.NoSiteContext")
+		return
+	}
+
+	switch kind {
+	case "rest", "upd":
+		s.handleRest(w, r, site, rest)
+	case "stat", "list":
+		if len(rest) == 0 {
+			writeError(w, http.StatusNotFound, "api.err.NotFound")
+			return
+		}
+		coll := rest[0]
+		switch coll {
+		case "health":
+			writeData(w, s.siteHealth(site))
+			return
+		case "sysinfo":
+			writeData(w, []any{object{
+				"version": s.version,
+				"uptime":  int64(time.Since(s.started).Seconds()),
+			}})
+			return
+		}
+		if mapped, ok := statCollections[coll]; ok {
+			coll = mapped
+		}
+		if len(rest) > 1 {
+			// stat/device/{mac} looks a single device up by MAC.
+			if obj, ok := s.store.find(site, coll, "mac", strings.ToLower(rest[1])); ok {
+				writeData(w, []any{obj})
+				return
+			}
+			writeData(w, []any{})
+			return
+		}
+		writeData(w, s.store.list(site, coll))
+	case "get":
+		s.handleGetSetting(w, site, rest)
+	case "set":
+		s.handleSetSetting(w, r, site, rest)
+	case "cmd":
+		s.handleCmd(w, r, site, rest)
+	default:
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+	}
+}
+
+// siteHealth builds `stat/health` from the devices of site. Switches count
+// towards `lan`, access points towards `wlan` and gateways towards `wan`; a
+// subsystem without devices is `unknown`, as on a real controller.
+func (s *Server) siteHealth(site string) []any {
+	type counts struct{ adopted, disconnected, pending int }
+	bySubsystem := map[string]*counts{"wan": {}, "lan": {}, "wlan": {}}
+	for _, d := range s.store.list(site, "device") {
+		var c *counts
+		switch d["type"] {
+		case "usw":
+			c = bySubsystem["lan"]
+		case "uap":
+			c = bySubsystem["wlan"]
+		case "ugw", "udm", "uxg":
+			c = bySubsystem["wan"]
+		default:
+			continue
+		}
+		switch {
+		case d["adopted"] != true:
+			c.pending++
+		case fmt.Sprint(d["state"]) != "1":
+			c.adopted++
+			c.disconnected++
+		default:
+			c.adopted++
+		}
+	}
+
+	status := func(c *counts) string {
+		switch {
+		case c.adopted == 0:
+			return "unknown"
+		case c.disconnected > 0:
+			return "warning"
+		default:
+			return "ok"
+		}
+	}
+	subsystem := func(name string, extra object) object {
+		c := bySubsystem[name]
+		obj := object{
+			"subsystem":        name,
+			"status":           status(c),
+			"num_adopted":      c.adopted,
+			"num_disconnected": c.disconnected,
+			"num_pending":      c.pending,
+		}
+		for k, v := range extra {
+			obj[k] = v
+		}
+		return obj
+	}
+
+	return []any{
+		subsystem("wlan", object{"num_user": 0, "num_guest": 0, "num_ap": bySubsystem["wlan"].adopted}),
+		subsystem("wan", object{"num_gw": bySubsystem["wan"].adopted}),
+		object{"subsystem": "www", "status": status(bySubsystem["wan"])},
+		subsystem("lan", object{"num_user": 0, "num_guest": 0, "num_sw": bySubsystem["lan"].adopted}),
+		object{
+			"subsystem":                "vpn",
+			"status":                   "unknown",
+			"remote_user_num_active":   0,
+			"remote_user_num_inactive": 0,
+		},
+	}
+}
+
+// handleRest implements the generic `rest/{collection}[/{id}]` CRUD endpoints.
+func (s *Server) handleRest(w http.ResponseWriter, r *http.Request, site string, rest []string) {
+	if len(rest) == 0 || rest[0] == "" {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+
+	coll := rest[0]
+	id := ""
+	if len(rest) > 1 {
+		id = rest[1]
+	}
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		writeData(w, s.store.list(site, coll))
+	case r.Method == http.MethodGet:
+		obj, ok := s.store.get(site, coll, id)
+		if !ok {
+			// go-unifi maps an empty data array to a NotFoundError.
+			writeData(w, []any{})
+			return
+		}
+		writeData(w, []any{obj})
+	case r.Method == http.MethodPost && id == "":
+		obj := object{}
+		if err := readBody(r, &obj); err != nil {
+			writeError(w, http.StatusBadRequest, "api.err.InvalidPayload")
+			return
+		}
+		writeData(w, []any{s.store.create(site, coll, obj)})
+	case r.Method == http.MethodPut || r.Method == http.MethodPost:
+		obj := object{}
+		if err := readBody(r, &obj); err != nil {
+			writeError(w, http.StatusBadRequest, "api.err.InvalidPayload")
+			return
+		}
+		updated, ok := s.store.update(site, coll, id, obj)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "api.err.IdInvalid")
+			return
+		}
+		writeData(w, []any{updated})
+	case r.Method == http.MethodDelete:
+		s.store.delete(site, coll, id)
+		writeData(w, []any{})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "api.err.MethodNotAllowed")
+	}
+}
+
+// handleGetSetting serves `get/setting[/{key}]`.
+func (s *Server) handleGetSetting(w http.ResponseWriter, site string, rest []string) {
+	if len(rest) == 0 || rest[0] != "setting" {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+
+	settings := s.store.list(site, "setting")
+	if len(rest) < 2 {
+		writeData(w, settings)
+		return
+	}
+
+	for _, setting := range settings {
+		if setting["key"] == rest[1] {
+			writeData(w, []any{setting})
+			return
+		}
+	}
+	writeData(w, []any{})
+}
+
+// handleSetSetting serves `set/setting/{key}[/{id}]`, creating the setting on
+// first write the way the controller lazily materializes defaults.
+func (s *Server) handleSetSetting(w http.ResponseWriter, r *http.Request, site string, rest []string) {
+	if len(rest) < 2 || rest[0] != "setting" {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+
+	key := rest[1]
+	obj := object{}
+	if err := readBody(r, &obj); err != nil {
+		writeError(w, http.StatusBadRequest, "api.err.InvalidPayload")
+		return
+	}
+	obj["key"] = key
+
+	if existing, ok := s.store.find(site, "setting", "key", key); ok {
+		updated, _ := s.store.update(site, "setting", existing["_id"].(string), obj)
+		writeData(w, []any{updated})
+		return
+	}
+	writeData(w, []any{s.store.create(site, "setting", obj)})
+}
+
+// handleCmd serves `cmd/{manager}`. Every command is recorded; the handful the
+// provider depends on for its own bookkeeping also update the store.
+func (s *Server) handleCmd(w http.ResponseWriter, r *http.Request, site string, rest []string) {
+	if len(rest) == 0 {
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+		return
+	}
+
+	manager := rest[0]
+	body := map[string]any{}
+	if err := readBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "api.err.InvalidPayload")
+		return
+	}
+	s.recordCommand(site, manager, body)
+
+	cmd, _ := body["cmd"].(string)
+	mac, _ := body["mac"].(string)
+	mac = strings.ToLower(mac)
+
+	switch manager + "/" + cmd {
+	case "sitemgr/add-site":
+		name, _ := body["name"].(string)
+		if name == "" {
+			name = randomToken()[:8]
+		}
+		site := s.store.create(globalSite, "site", object{
+			"name": name,
+			"desc": body["desc"],
+			"role": "admin",
+		})
+		s.store.addSite(name)
+		s.seedGroups(name)
+		writeData(w, []any{site})
+		return
+	case "sitemgr/delete-site":
+		id, _ := body["site"].(string)
+		if obj, ok := s.store.get(globalSite, "site", id); ok {
+			s.store.delete(globalSite, "site", id)
+			s.store.removeSite(obj["name"].(string))
+		}
+	case "sitemgr/update-site":
+		if obj, ok := s.store.find(globalSite, "site", "name", site); ok {
+			s.store.update(globalSite, "site", obj["_id"].(string), object{"desc": body["desc"]})
+		}
+	case "devmgr/adopt":
+		if obj, ok := s.store.find(site, "device", "mac", mac); ok {
+			s.store.update(site, "device", obj["_id"].(string), object{"adopted": true, "state": 1})
+		}
+	case "devmgr/delete-device", "sitemgr/delete-device":
+		if obj, ok := s.store.find(site, "device", "mac", mac); ok {
+			s.store.delete(site, "device", obj["_id"].(string))
+		}
+	case "hotspot/create-voucher":
+		writeData(w, []any{s.createVouchers(site, body)})
+		return
+	case "hotspot/delete-voucher":
+		id, _ := body["_id"].(string)
+		s.store.delete(site, "voucher", id)
+	case "stamgr/forget-sta":
+		macs, _ := body["macs"].([]any)
+		for _, m := range macs {
+			if m, ok := m.(string); ok {
+				if obj, ok := s.store.find(site, "user", "mac", strings.ToLower(m)); ok {
+					s.store.delete(site, "user", obj["_id"].(string))
+				}
+			}
+		}
+	}
+
+	writeData(w, []any{})
+}
+
+// handleV2 serves `/v2/api/site/{site}/...`. The v2 API returns bare JSON
+// rather than the `meta`/`data` envelope and signals a missing object with a
+// 404, so collections and items are told apart by whether the last path
+// segment is an object ID.
+func (s *Server) handleV2(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) < 2 {
+		writeJSON(w, http.StatusNotFound, map[string]any{"errorCode": 404})
+		return
+	}
+
+	site, segments := parts[0], parts[1:]
+	if !s.store.hasSite(site) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": "api.err.NoSiteContext"})
+		return
+	}
+
+	last := segments[len(segments)-1]
+	if last == "batch-delete" && r.Method == http.MethodPost {
+		coll := "v2/" + strings.Join(segments[:len(segments)-1], "/")
+		var ids []string
+		if err := readBody(r, &ids); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": "api.err.InvalidPayload"})
+			return
+		}
+		for _, id := range ids {
+			s.store.delete(site, coll, id)
+		}
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+
+	coll := "v2/" + strings.Join(segments, "/")
+	id := ""
+	if len(segments) > 1 && objectIDPattern.MatchString(last) {
+		coll = "v2/" + strings.Join(segments[:len(segments)-1], "/")
+		id = last
+	}
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		writeJSON(w, http.StatusOK, s.store.list(site, coll))
+	case r.Method == http.MethodGet:
+		obj, ok := s.store.get(site, coll, id)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]any{"errorCode": 404})
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+	case r.Method == http.MethodPost && id == "":
+		obj := object{}
+		if err := readBody(r, &obj); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": "api.err.InvalidPayload"})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.store.create(site, coll, obj))
+	case r.Method == http.MethodPut:
+		obj := object{}
+		if err := readBody(r, &obj); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": "api.err.InvalidPayload"})
+			return
+		}
+		updated, ok := s.store.update(site, coll, id, obj)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]any{"errorCode": 404})
+			return
+		}
+		writeJSON(w, http.StatusOK, updated)
+	case r.Method == http.MethodDelete:
+		if !s.store.delete(site, coll, id) {
+			writeJSON(w, http.StatusNotFound, map[string]any{"errorCode": 404})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"errorCode": 405})
+	}
+}
+
+// createVouchers stores the batch of vouchers a `create-voucher` command asks
+// for and returns the batch's create time, as the controller does. Like the
+// controller, batches created within the same second share a create time.
+func (s *Server) createVouchers(site string, body map[string]any) map[string]any {
+	createTime := time.Now().Unix()
+
+	n, _ := body["n"].(float64)
+	for range int(n) {
+		obj := object{
+			"code":        fmt.Sprintf("%010d", mathrand.Int64N(1e10)),
+			"create_time": createTime,
+			"quota":       body["quota"],
+			"duration":    body["expire"],
+			"used":        0,
+			"note":        body["note"],
+		}
+		if up, ok := body["up"]; ok {
+			obj["qos_overwrite"] = true
+			obj["qos_rate_max_up"] = up
+		}
+		if down, ok := body["down"]; ok {
+			obj["qos_overwrite"] = true
+			obj["qos_rate_max_down"] = down
+		}
+		if bytes, ok := body["bytes"]; ok {
+			obj["qos_overwrite"] = true
+			obj["qos_usage_quota"] = bytes
+		}
+		s.store.create(site, "voucher", obj)
+	}
+	return map[string]any{"create_time": createTime}
+}
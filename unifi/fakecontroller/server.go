@@ -0,0 +1,344 @@
+// Package fakecontroller implements an in-process stand-in for the UniFi
+// Network Application. It serves the login, `/api/s/{site}/...` and
+// `/v2/api/site/{site}/...` endpoints go-unifi talks to, backed by an in-memory
+// store per site, so acceptance tests can run without the controller container.
+//
+// The fake is deliberately generic: any `rest/{collection}` or v2 collection is
+// accepted and stored as raw JSON, so new resources work against it without
+// teaching it their schema. It does not validate payloads the way the real
+// controller does.
+package fakecontroller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultUsername and DefaultPassword are the credentials the fake accepts
+	// unless overridden with WithCredentials.
+	DefaultUsername = "admin"
+	DefaultPassword = "admin"
+
+	// DefaultVersion is the Network Application version the fake reports.
+	DefaultVersion = "9.4.19"
+
+	sessionCookie = "unifises"
+
+	// globalSite is the pseudo-site the site list itself is stored under.
+	globalSite = ""
+)
+
+// Server is a fake UniFi controller listening on a local TLS port.
+type Server struct {
+	*httptest.Server
+
+	username string
+	password string
+	apiKey   string
+	version  string
+	started  time.Time
+
+	store *store
+
+	mu       sync.Mutex
+	sessions map[string]struct{}
+	commands []Command
+}
+
+// Command records a call to one of the controller's `cmd/{manager}` endpoints.
+type Command struct {
+	Site    string
+	Manager string
+	Body    map[string]any
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithCredentials sets the username and password the fake accepts at login.
+func WithCredentials(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithAPIKey makes the fake accept the given key in the `X-API-KEY` header.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithVersion sets the Network Application version the fake reports.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// New starts a fake controller seeded with a `default` site that has a LAN and
+// a WAN network and the default groups, matching a freshly set up controller.
+// Call Close when done.
+func New(opts ...Option) *Server {
+	s := &Server{
+		username: DefaultUsername,
+		password: DefaultPassword,
+		version:  DefaultVersion,
+		started:  time.Now(),
+		store:    newStore(),
+		sessions: map[string]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.seed()
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+func (s *Server) seed() {
+	s.store.create(globalSite, "site", object{
+		"name":           "default",
+		"desc":           "Default",
+		"attr_hidden_id": "default",
+		"attr_no_delete": true,
+		"role":           "admin",
+	})
+	s.store.addSite("default")
+
+	s.store.create("default", "networkconf", object{
+		"name":           "Default",
+		"purpose":        "corporate",
+		"attr_hidden_id": "LAN",
+		"attr_no_delete": true,
+		"ip_subnet":      "192.168.1.1/24",
+		"networkgroup":   "LAN",
+		"dhcpd_enabled":  true,
+		"dhcpd_start":    "192.168.1.6",
+		"dhcpd_stop":     "192.168.1.254",
+		"enabled":        true,
+	})
+	s.store.create("default", "networkconf", object{
+		"name":             "Internet 1",
+		"purpose":          "wan",
+		"attr_hidden_id":   "WAN",
+		"attr_no_delete":   true,
+		"wan_networkgroup": "WAN",
+		"wan_type":         "dhcp",
+		"enabled":          true,
+	})
+	s.seedGroups("default")
+}
+
+// seedGroups creates the default user, WLAN, AP and RADIUS groups the
+// controller gives every site. WLANs fall back to the default WLAN and AP
+// groups when none is set.
+func (s *Server) seedGroups(site string) {
+	s.store.create(site, "usergroup", object{
+		"name":              "Default",
+		"attr_hidden_id":    "Default",
+		"attr_no_delete":    true,
+		"qos_rate_max_down": -1,
+		"qos_rate_max_up":   -1,
+	})
+	s.store.create(site, "wlangroup", object{
+		"name":           "Default",
+		"attr_hidden_id": "Default",
+		"attr_no_delete": true,
+	})
+	s.store.create(site, "v2/apgroups", object{
+		"name":           "All APs",
+		"attr_hidden_id": "default",
+		"attr_no_delete": true,
+		"device_macs":    []any{},
+	})
+	s.store.create(site, "radiusprofile", object{
+		"name":           "Default",
+		"attr_hidden_id": "Default",
+		"attr_no_delete": true,
+	})
+}
+
+// Commands returns every `cmd/{manager}` call the fake has received.
+func (s *Server) Commands() []Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Command(nil), s.commands...)
+}
+
+// ExpireSessions drops every login session, as the controller does when a
+// session times out, so the next cookie-authenticated call gets a 401.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = map[string]struct{}{}
+}
+
+// Create stores obj in a site collection directly, bypassing the HTTP API. It
+// is meant for seeding objects the provider can only read, such as devices.
+func (s *Server) Create(site, collection string, obj map[string]any) map[string]any {
+	s.store.addSite(site)
+	return s.store.create(site, collection, obj)
+}
+
+// List returns every object in a site collection.
+func (s *Server) List(site, collection string) []map[string]any {
+	return s.store.list(site, collection)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// UniFi OS consoles serve the Network Application under /proxy/network;
+	// accept both layouts so either API style go-unifi detects works.
+	path := strings.TrimPrefix(r.URL.Path, "/proxy/network")
+
+	switch {
+	case path == "/" || path == "":
+		// A classic controller redirects the root to the management UI.
+		http.Redirect(w, r, "/manage", http.StatusFound)
+		return
+	case path == "/status":
+		writeJSON(w, http.StatusOK, map[string]any{
+			"meta": map[string]any{"rc": "ok", "up": true, "server_version": s.version},
+			"data": []any{},
+		})
+		return
+	case path == "/api/login" || path == "/api/auth/login":
+		s.handleLogin(w, r)
+		return
+	case path == "/api/logout" || path == "/api/auth/logout":
+		s.handleLogout(w, r)
+		return
+	}
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "api.err.LoginRequired")
+		return
+	}
+
+	switch {
+	case path == "/api/self":
+		writeData(w, []any{map[string]any{"name": s.username, "is_super": true}})
+	case path == "/api/self/sites" || path == "/api/stat/sites":
+		writeData(w, s.store.list(globalSite, "site"))
+	case strings.HasPrefix(path, "/api/s/"):
+		s.handleV1(w, r, strings.Split(strings.TrimPrefix(path, "/api/s/"), "/"))
+	case strings.HasPrefix(path, "/v2/api/site/"):
+		s.handleV2(w, r, strings.Split(strings.TrimPrefix(path, "/v2/api/site/"), "/"))
+	default:
+		writeError(w, http.StatusNotFound, "api.err.NotFound")
+	}
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var creds struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := readBody(r, &creds); err != nil {
+		writeError(w, http.StatusBadRequest, "api.err.Invalid")
+		return
+	}
+
+	if creds.Username != s.username || creds.Password != s.password {
+		writeError(w, http.StatusBadRequest, "api.err.Invalid")
+		return
+	}
+
+	token := randomToken()
+
+	s.mu.Lock()
+	s.sessions[token] = struct{}{}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: token, Path: "/", HttpOnly: true})
+	w.Header().Set("X-CSRF-Token", token)
+	writeData(w, []any{})
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, c.Value)
+		s.mu.Unlock()
+	}
+	writeData(w, []any{})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.apiKey != "" && r.Header.Get("X-API-KEY") == s.apiKey {
+		return true
+	}
+
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.sessions[c.Value]
+	return ok
+}
+
+func (s *Server) recordCommand(site, manager string, body map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = append(s.commands, Command{Site: site, Manager: manager, Body: body})
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// writeData writes a v1 API envelope with `rc: ok`.
+func writeData(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"meta": map[string]any{"rc": "ok"},
+		"data": data,
+	})
+}
+
+// writeError writes a v1 API envelope with `rc: error`.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{
+		"meta": map[string]any{"rc": "error", "msg": msg},
+		"data": []any{},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// readBody decodes a JSON request body into v, treating an empty body as a
+// no-op so bodiless commands and deletes are accepted.
+func readBody(r *http.Request, v any) error {
+	if r.Body == nil {
+		return nil
+	}
+	err := json.NewDecoder(r.Body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,434 @@
+package fakecontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+)
+
+type envelope struct {
+	Meta struct {
+		RC  string `json:"rc"`
+		Msg string `json:"msg"`
+	} `json:"meta"`
+	Data []map[string]any `json:"data"`
+}
+
+func newTestClient(t *testing.T, s *Server) *http.Client {
+	t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := s.Client()
+	c.Jar = jar
+	c.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return c
+}
+
+func do(t *testing.T, c *http.Client, method, url string, body any, out any) int {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, url, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func login(t *testing.T, s *Server, c *http.Client) {
+	t.Helper()
+
+	status := do(t, c, http.MethodPost, s.URL+"/api/login", map[string]string{
+		"username": DefaultUsername,
+		"password": DefaultPassword,
+	}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("login status = %d, want 200", status)
+	}
+}
+
+func TestServer_rootRedirects(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	if status := do(t, newTestClient(t, s), http.MethodGet, s.URL+"/", nil, nil); status != http.StatusFound {
+		t.Errorf("GET / status = %d, want 302", status)
+	}
+}
+
+func TestServer_requiresLogin(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	var env envelope
+	if status := do(t, c, http.MethodGet, s.URL+"/api/s/default/rest/networkconf", nil, &env); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", status)
+	}
+	if env.Meta.Msg != "api.err.LoginRequired" {
+		t.Errorf("msg = %q, want api.err.LoginRequired", env.Meta.Msg)
+	}
+
+	if status := do(t, c, http.MethodPost, s.URL+"/api/login", map[string]string{
+		"username": "admin",
+		"password": "wrong",
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("bad login status = %d, want 400", status)
+	}
+
+	login(t, s, c)
+	if status := do(t, c, http.MethodGet, s.URL+"/api/s/default/rest/networkconf", nil, &env); status != http.StatusOK {
+		t.Fatalf("status after login = %d, want 200", status)
+	}
+
+	s.ExpireSessions()
+	if status := do(t, c, http.MethodGet, s.URL+"/api/s/default/rest/networkconf", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("status after expiry = %d, want 401", status)
+	}
+}
+
+func TestServer_apiKey(t *testing.T) {
+	s := New(WithAPIKey("secret"))
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/proxy/network/api/s/default/rest/networkconf", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-API-KEY", "secret")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestServer_restCRUD(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	base := s.URL + "/api/s/default/rest/firewallgroup"
+
+	var created envelope
+	do(t, c, http.MethodPost, base, map[string]any{
+		"name":          "web",
+		"group_type":    "port-group",
+		"group_members": []string{"80", "443"},
+	}, &created)
+	if len(created.Data) != 1 {
+		t.Fatalf("create returned %d objects, want 1", len(created.Data))
+	}
+	id, _ := created.Data[0]["_id"].(string)
+	if !objectIDPattern.MatchString(id) {
+		t.Fatalf("created _id %q is not an object ID", id)
+	}
+
+	var updated envelope
+	do(t, c, http.MethodPut, base+"/"+id, map[string]any{"name": "web-ports"}, &updated)
+	if got := updated.Data[0]["name"]; got != "web-ports" {
+		t.Errorf("updated name = %v, want web-ports", got)
+	}
+	if got := updated.Data[0]["group_type"]; got != "port-group" {
+		t.Errorf("update dropped group_type, got %v", got)
+	}
+
+	var listed envelope
+	do(t, c, http.MethodGet, base, nil, &listed)
+	if len(listed.Data) != 1 {
+		t.Errorf("list returned %d objects, want 1", len(listed.Data))
+	}
+
+	do(t, c, http.MethodDelete, base+"/"+id, nil, nil)
+
+	var got envelope
+	do(t, c, http.MethodGet, base+"/"+id, nil, &got)
+	if len(got.Data) != 0 {
+		t.Errorf("get after delete returned %d objects, want 0", len(got.Data))
+	}
+}
+
+func TestServer_sitesAreIsolated(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	var added envelope
+	do(t, c, http.MethodPost, s.URL+"/api/s/default/cmd/sitemgr", map[string]any{
+		"cmd":  "add-site",
+		"desc": "Branch",
+	}, &added)
+	name, _ := added.Data[0]["name"].(string)
+	if name == "" {
+		t.Fatal("add-site returned no site name")
+	}
+
+	var networks envelope
+	do(t, c, http.MethodGet, s.URL+"/api/s/"+name+"/rest/networkconf", nil, &networks)
+	if len(networks.Data) != 0 {
+		t.Errorf("new site has %d networks, want 0", len(networks.Data))
+	}
+
+	do(t, c, http.MethodGet, s.URL+"/api/s/default/rest/networkconf", nil, &networks)
+	if len(networks.Data) != 2 {
+		t.Errorf("default site has %d networks, want the 2 seeded", len(networks.Data))
+	}
+
+	if status := do(t, c, http.MethodGet, s.URL+"/api/s/missing/rest/networkconf", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("unknown site status = %d, want 400", status)
+	}
+
+	if cmds := s.Commands(); len(cmds) != 1 || cmds[0].Manager != "sitemgr" {
+		t.Errorf("Commands() = %+v, want one sitemgr command", cmds)
+	}
+}
+
+func TestServer_settings(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	do(t, c, http.MethodPut, s.URL+"/api/s/default/set/setting/ntp", map[string]any{
+		"ntp_server_1": "pool.ntp.org",
+	}, nil)
+	do(t, c, http.MethodPut, s.URL+"/api/s/default/set/setting/ntp", map[string]any{
+		"ntp_server_2": "time.google.com",
+	}, nil)
+
+	var got envelope
+	do(t, c, http.MethodGet, s.URL+"/api/s/default/get/setting", nil, &got)
+	if len(got.Data) != 1 {
+		t.Fatalf("got %d settings, want 1", len(got.Data))
+	}
+	if got.Data[0]["key"] != "ntp" || got.Data[0]["ntp_server_1"] != "pool.ntp.org" ||
+		got.Data[0]["ntp_server_2"] != "time.google.com" {
+		t.Errorf("setting = %v", got.Data[0])
+	}
+}
+
+func TestServer_v2CRUD(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	base := s.URL + "/v2/api/site/default/static-dns"
+
+	var created map[string]any
+	do(t, c, http.MethodPost, base, map[string]any{"key": "nas.example.com", "value": "10.0.0.2"}, &created)
+	id, _ := created["_id"].(string)
+	if id == "" {
+		t.Fatal("create returned no _id")
+	}
+
+	var updated map[string]any
+	do(t, c, http.MethodPut, base+"/"+id, map[string]any{"value": "10.0.0.3"}, &updated)
+	if updated["value"] != "10.0.0.3" || updated["key"] != "nas.example.com" {
+		t.Errorf("updated = %v", updated)
+	}
+
+	var listed []map[string]any
+	do(t, c, http.MethodGet, base, nil, &listed)
+	if len(listed) != 1 {
+		t.Errorf("list returned %d objects, want 1", len(listed))
+	}
+
+	if status := do(t, c, http.MethodDelete, base+"/"+id, nil, nil); status != http.StatusOK {
+		t.Errorf("delete status = %d, want 200", status)
+	}
+	if status := do(t, c, http.MethodGet, base+"/"+id, nil, nil); status != http.StatusNotFound {
+		t.Errorf("get after delete status = %d, want 404", status)
+	}
+}
+
+func TestServer_v2BatchDelete(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	base := s.URL + "/v2/api/site/default/firewall-policies"
+
+	var a, b map[string]any
+	do(t, c, http.MethodPost, base, map[string]any{"name": "a"}, &a)
+	do(t, c, http.MethodPost, base, map[string]any{"name": "b"}, &b)
+	do(t, c, http.MethodPost, base+"/batch-delete", []string{a["_id"].(string)}, nil)
+
+	var listed []map[string]any
+	do(t, c, http.MethodGet, base, nil, &listed)
+	if len(listed) != 1 || listed[0]["name"] != "b" {
+		t.Errorf("after batch delete = %v, want only b", listed)
+	}
+}
+
+func TestServer_seededDevices(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	s.Create("default", "device", map[string]any{
+		"mac":     "aa:bb:cc:dd:ee:ff",
+		"adopted": false,
+		"state":   0,
+	})
+
+	var got envelope
+	do(t, c, http.MethodGet, s.URL+"/api/s/default/stat/device/AA:BB:CC:DD:EE:FF", nil, &got)
+	if len(got.Data) != 1 {
+		t.Fatalf("stat/device/{mac} returned %d devices, want 1", len(got.Data))
+	}
+
+	do(t, c, http.MethodPost, s.URL+"/api/s/default/cmd/devmgr", map[string]any{
+		"cmd": "adopt",
+		"mac": "aa:bb:cc:dd:ee:ff",
+	}, nil)
+
+	devices := s.List("default", "device")
+	if devices[0]["adopted"] != true {
+		t.Errorf("device not adopted after devmgr adopt: %v", devices[0])
+	}
+}
+
+func TestServer_hotspotVouchers(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	create := func() float64 {
+		var got envelope
+		do(t, c, http.MethodPost, s.URL+"/api/s/default/cmd/hotspot", map[string]any{
+			"cmd":    "create-voucher",
+			"n":      2,
+			"quota":  1,
+			"expire": 60,
+			"down":   1000,
+		}, &got)
+		if len(got.Data) != 1 {
+			t.Fatalf("create-voucher returned %d objects, want 1", len(got.Data))
+		}
+		return got.Data[0]["create_time"].(float64)
+	}
+	if first, second := create(), create(); second < first {
+		t.Errorf("second batch created at %v, before the first at %v", second, first)
+	}
+
+	var got envelope
+	do(t, c, http.MethodGet, s.URL+"/api/s/default/stat/voucher", nil, &got)
+	if len(got.Data) != 4 {
+		t.Fatalf("stat/voucher returned %d vouchers, want 4", len(got.Data))
+	}
+	if code, _ := got.Data[0]["code"].(string); len(code) != 10 {
+		t.Errorf("code = %q, want 10 digits", code)
+	}
+	if got.Data[0]["qos_rate_max_down"] != float64(1000) {
+		t.Errorf("qos_rate_max_down = %v, want 1000", got.Data[0]["qos_rate_max_down"])
+	}
+
+	do(t, c, http.MethodPost, s.URL+"/api/s/default/cmd/hotspot", map[string]any{
+		"cmd": "delete-voucher",
+		"_id": got.Data[0]["_id"],
+	}, nil)
+	if n := len(s.List("default", "voucher")); n != 3 {
+		t.Errorf("%d vouchers left after delete-voucher, want 3", n)
+	}
+}
+
+func TestServer_siteHealth(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	s.Create("default", "device", map[string]any{"mac": "aa:bb:cc:dd:ee:01", "type": "usw", "adopted": true, "state": 1})
+	s.Create("default", "device", map[string]any{"mac": "aa:bb:cc:dd:ee:02", "type": "usw", "adopted": true, "state": 0})
+	s.Create("default", "device", map[string]any{"mac": "aa:bb:cc:dd:ee:03", "type": "uap", "adopted": false, "state": 0})
+
+	var got envelope
+	do(t, c, http.MethodGet, s.URL+"/api/s/default/stat/health", nil, &got)
+	health := map[string]map[string]any{}
+	for _, sub := range got.Data {
+		health[sub["subsystem"].(string)] = sub
+	}
+	for _, name := range []string{"wan", "www", "lan", "wlan", "vpn"} {
+		if _, ok := health[name]; !ok {
+			t.Errorf("stat/health has no %s subsystem", name)
+		}
+	}
+	if lan := health["lan"]; lan["status"] != "warning" || lan["num_adopted"] != float64(2) || lan["num_disconnected"] != float64(1) {
+		t.Errorf("lan = %v, want warning with 2 adopted and 1 disconnected", lan)
+	}
+	if wlan := health["wlan"]; wlan["status"] != "unknown" || wlan["num_pending"] != float64(1) {
+		t.Errorf("wlan = %v, want unknown with 1 pending", wlan)
+	}
+
+	do(t, c, http.MethodGet, s.URL+"/api/s/default/stat/sysinfo", nil, &got)
+	if len(got.Data) != 1 || got.Data[0]["version"] != DefaultVersion {
+		t.Errorf("stat/sysinfo = %v, want version %s", got.Data, DefaultVersion)
+	}
+}
+
+func TestServer_defaultGroups(t *testing.T) {
+	s := New()
+	defer s.Close()
+	c := newTestClient(t, s)
+	login(t, s, c)
+
+	for _, coll := range []string{"usergroup", "wlangroup", "radiusprofile"} {
+		var got envelope
+		do(t, c, http.MethodGet, s.URL+"/api/s/default/rest/"+coll, nil, &got)
+		if len(got.Data) != 1 || got.Data[0]["attr_hidden_id"] != "Default" {
+			t.Errorf("rest/%s = %v, want the Default group", coll, got.Data)
+		}
+	}
+
+	var apGroups []map[string]any
+	do(t, c, http.MethodGet, s.URL+"/v2/api/site/default/apgroups", nil, &apGroups)
+	if len(apGroups) != 1 || apGroups[0]["attr_hidden_id"] != "default" {
+		t.Errorf("apgroups = %v, want the default AP group", apGroups)
+	}
+
+	var added envelope
+	do(t, c, http.MethodPost, s.URL+"/api/s/default/cmd/sitemgr", map[string]any{
+		"cmd":  "add-site",
+		"name": "branch",
+	}, &added)
+	var groups envelope
+	do(t, c, http.MethodGet, s.URL+"/api/s/branch/rest/usergroup", nil, &groups)
+	if len(groups.Data) != 1 {
+		t.Errorf("new site has %d user groups, want the default one", len(groups.Data))
+	}
+}
@@ -0,0 +1,161 @@
+package fakecontroller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// object is a single stored controller object, kept in its JSON map form so
+// the fake never has to know the shape of the resource behind a collection.
+type object = map[string]any
+
+// store holds every collection of every site in memory.
+type store struct {
+	mu     sync.Mutex
+	nextID uint64
+
+	// sites maps a site name (the short name used in API paths) to its
+	// collections, and each collection maps an object ID to the object.
+	sites map[string]map[string]map[string]object
+}
+
+func newStore() *store {
+	return &store{sites: map[string]map[string]map[string]object{}}
+}
+
+// newID returns a 24 character hex ID shaped like a MongoDB ObjectId, which is
+// what the controller hands out for every object.
+func (s *store) newID() string {
+	s.nextID++
+	return fmt.Sprintf("6500000000000000%08x", s.nextID)
+}
+
+func (s *store) collection(site, name string) map[string]object {
+	colls, ok := s.sites[site]
+	if !ok {
+		colls = map[string]map[string]object{}
+		s.sites[site] = colls
+	}
+	coll, ok := colls[name]
+	if !ok {
+		coll = map[string]object{}
+		colls[name] = coll
+	}
+	return coll
+}
+
+func (s *store) hasSite(site string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.sites[site]
+	return ok
+}
+
+func (s *store) addSite(site string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sites[site]; !ok {
+		s.sites[site] = map[string]map[string]object{}
+	}
+}
+
+func (s *store) removeSite(site string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sites, site)
+}
+
+// list returns copies of every object in the collection, ordered by ID so
+// responses are stable across calls.
+func (s *store) list(site, name string) []object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coll := s.collection(site, name)
+	ids := slices.Sorted(maps.Keys(coll))
+
+	out := make([]object, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, maps.Clone(coll[id]))
+	}
+	return out
+}
+
+func (s *store) get(site, name, id string) (object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.collection(site, name)[id]
+	if !ok {
+		return nil, false
+	}
+	return maps.Clone(obj), true
+}
+
+// create stores obj under a fresh ID, stamping the `_id` and `site_id` fields
+// the controller would fill in.
+func (s *store) create(site, name string, obj object) object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj = maps.Clone(obj)
+	if id, _ := obj["_id"].(string); id == "" {
+		obj["_id"] = s.newID()
+	}
+	if _, ok := obj["site_id"]; !ok {
+		obj["site_id"] = site
+	}
+
+	s.collection(site, name)[obj["_id"].(string)] = obj
+	return maps.Clone(obj)
+}
+
+// update merges obj into the stored object, mirroring the controller's PUT
+// semantics where omitted fields keep their current value.
+func (s *store) update(site, name, id string, obj object) (object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coll := s.collection(site, name)
+	existing, ok := coll[id]
+	if !ok {
+		return nil, false
+	}
+
+	merged := maps.Clone(existing)
+	maps.Copy(merged, obj)
+	merged["_id"] = id
+
+	coll[id] = merged
+	return maps.Clone(merged), true
+}
+
+func (s *store) delete(site, name, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coll := s.collection(site, name)
+	if _, ok := coll[id]; !ok {
+		return false
+	}
+	delete(coll, id)
+	return true
+}
+
+// find returns the first object in the collection whose field equals value.
+func (s *store) find(site, name, field string, value any) (object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range s.collection(site, name) {
+		if obj[field] == value {
+			return maps.Clone(obj), true
+		}
+	}
+	return nil, false
+}
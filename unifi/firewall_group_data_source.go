@@ -0,0 +1,198 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &firewallGroupDataSource{}
+
+func NewFirewallGroupDataSource() datasource.DataSource {
+	return &firewallGroupDataSource{}
+}
+
+// firewallGroupDataSource defines the data source implementation.
+type firewallGroupDataSource struct {
+	client *Client
+}
+
+// firewallGroupDataSourceModel describes the data source data model.
+type firewallGroupDataSourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	Site     types.String   `tfsdk:"site"`
+	Name     types.String   `tfsdk:"name"`
+	Type     types.String   `tfsdk:"type"`
+	Members  types.Set      `tfsdk:"members"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (d *firewallGroupDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_group"
+}
+
+func (d *firewallGroupDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`unifi_firewall_group` data source can be used to look up a group of addresses " +
+			"or ports by name, for example one managed in another workspace or in the controller UI.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the firewall group.",
+				Computed:            true,
+			},
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site the firewall group is associated with.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the firewall group.",
+				Required:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the firewall group: `address-group`, `port-group`, or " +
+					"`ipv6-address-group`. Required when groups of different types share the name.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("address-group", "port-group", "ipv6-address-group"),
+				},
+			},
+			"members": schema.SetAttribute{
+				MarkdownDescription: "The members of the firewall group.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *firewallGroupDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *firewallGroupDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data firewallGroupDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := data.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = d.client.Site
+	}
+
+	groups, err := d.client.ListFirewallGroup(ctx, site)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Firewall Groups",
+			"Could not list firewall groups: "+err.Error(),
+		)
+		return
+	}
+
+	group, err := findFirewallGroup(groups, data.Name.ValueString(), data.Type.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Finding Firewall Group", err.Error())
+		return
+	}
+
+	var model firewallGroupResourceModel
+	resp.Diagnostics.Append((&firewallGroupResource{}).firewallGroupToModel(ctx, group, &model, site)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = model.ID
+	data.Site = model.Site
+	data.Name = model.Name
+	data.Type = model.Type
+	data.Members = model.Members
+	if data.Members.IsNull() {
+		data.Members = types.SetValueMust(types.StringType, nil)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findFirewallGroup returns the group called name, of type groupType unless it
+// is empty. More than one match is an error.
+func findFirewallGroup(groups []unifi.FirewallGroup, name, groupType string) (*unifi.FirewallGroup, error) {
+	var found *unifi.FirewallGroup
+	for i := range groups {
+		g := &groups[i]
+		if g.Name != name || (groupType != "" && g.GroupType != groupType) {
+			continue
+		}
+		if found != nil && found.GroupType == g.GroupType {
+			return nil, fmt.Errorf("more than one %s firewall group is named %q", g.GroupType, name)
+		}
+		if found != nil {
+			return nil, fmt.Errorf(
+				"more than one firewall group is named %q (%s and %s); set type to choose one",
+				name, found.GroupType, g.GroupType,
+			)
+		}
+		found = g
+	}
+	if found == nil {
+		if groupType != "" {
+			return nil, fmt.Errorf("no %s firewall group named %q", groupType, name)
+		}
+		return nil, fmt.Errorf("no firewall group named %q", name)
+	}
+	return found, nil
+}
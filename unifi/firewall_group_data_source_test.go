@@ -0,0 +1,145 @@
+package unifi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+func TestAccFirewallGroupDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFirewallGroupDataSourceConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.unifi_firewall_group.test", "id",
+						"unifi_firewall_group.test", "id",
+					),
+					resource.TestCheckResourceAttr("data.unifi_firewall_group.test", "type", "address-group"),
+					resource.TestCheckResourceAttr("data.unifi_firewall_group.test", "members.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccFirewallGroupDataSourceConfig_basic() string {
+	return `
+resource "unifi_firewall_group" "test" {
+	name = "Test Shared Address Group"
+	type = "address-group"
+	members = [
+		"192.168.1.10",
+		"192.168.1.20"
+	]
+}
+
+data "unifi_firewall_group" "test" {
+	name = unifi_firewall_group.test.name
+	type = "address-group"
+}
+`
+}
+
+func TestNewFirewallGroupDataSource(t *testing.T) {
+	d := NewFirewallGroupDataSource()
+	if d == nil {
+		t.Fatal("NewFirewallGroupDataSource() returned nil")
+	}
+	if _, ok := d.(fwdatasource.DataSourceWithConfigure); !ok {
+		t.Error("expected DataSourceWithConfigure interface")
+	}
+}
+
+func Test_firewallGroupDataSource_Metadata(t *testing.T) {
+	d := &firewallGroupDataSource{}
+	resp := &fwdatasource.MetadataResponse{}
+	d.Metadata(context.Background(), fwdatasource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_firewall_group" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_firewall_group")
+	}
+}
+
+func Test_firewallGroupDataSource_Schema(t *testing.T) {
+	d := &firewallGroupDataSource{}
+	resp := &fwdatasource.SchemaResponse{}
+	d.Schema(context.Background(), fwdatasource.SchemaRequest{}, resp)
+	for _, attr := range []string{"id", "site", "name", "type", "members", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	if !resp.Schema.Attributes["name"].IsRequired() {
+		t.Error("name should be required")
+	}
+}
+
+func Test_firewallGroupDataSource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwdatasource.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwdatasource.ConfigureRequest{}, false},
+		{"wrong_type", fwdatasource.ConfigureRequest{ProviderData: "wrong"}, true},
+		{"correct_client", fwdatasource.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &firewallGroupDataSource{}
+			resp := &fwdatasource.ConfigureResponse{}
+			d.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_findFirewallGroup(t *testing.T) {
+	groups := []unifi.FirewallGroup{
+		{ID: "1", Name: "servers", GroupType: "address-group"},
+		{ID: "2", Name: "servers", GroupType: "ipv6-address-group"},
+		{ID: "3", Name: "web", GroupType: "port-group"},
+		{ID: "4", Name: "dup", GroupType: "port-group"},
+		{ID: "5", Name: "dup", GroupType: "port-group"},
+	}
+	tests := []struct {
+		name      string
+		groupName string
+		groupType string
+		wantID    string
+		wantErr   string
+	}{
+		{"by name", "web", "", "3", ""},
+		{"by name and type", "servers", "ipv6-address-group", "2", ""},
+		{"ambiguous without type", "servers", "", "", "set type"},
+		{"duplicate of one type", "dup", "port-group", "", "more than one port-group"},
+		{"wrong type", "web", "address-group", "", "no address-group firewall group"},
+		{"missing", "nope", "", "", "no firewall group"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findFirewallGroup(groups, tt.groupName, tt.groupType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("ID = %q, want %q", got.ID, tt.wantID)
+			}
+		})
+	}
+}
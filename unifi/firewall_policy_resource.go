@@ -38,8 +38,13 @@
 	_ resource.ResourceWithImportState  = &firewallPolicyResource{}
 	_ resource.ResourceWithIdentity     = &firewallPolicyResource{}
 	_ resource.ResourceWithUpgradeState = &firewallPolicyResource{}
+	_ resource.ResourceWithModifyPlan   = &firewallPolicyResource{}
 )
 
+// firewallPolicyMinimumVersion is the oldest Network Application release with
+// zone-based firewall policies.
+const firewallPolicyMinimumVersion = "9.0.0"
+
 // Ensure provider defined types fully satisfy list interfaces.
 var (
 	_ list.ListResource              = &firewallPolicyResource{}
@@ -297,7 +302,7 @@ func (r *firewallPolicyResource) Schema(
 
 	resp.Schema = schema.Schema{
 		Version: 1,
-		MarkdownDescription: "Manages a UniFi zone-based firewall policy (UniFi Network 8.x+). " +
+		MarkdownDescription: "Manages a UniFi zone-based firewall policy (UniFi Network 9.0+). " +
 			"Zone-based firewall policies replace the legacy firewall rules and are displayed " +
 			"under Settings → Security → Firewall Policies in the UniFi UI.",
 
@@ -477,6 +482,32 @@ func (r *firewallPolicyResource) Configure(
 	r.client = client
 }
 
+func (r *firewallPolicyResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if req.Plan.Raw.IsNull() {
+		return // resource is being destroyed
+	}
+
+	resp.Diagnostics.Append(r.client.checkMinimumVersion("unifi_firewall_policy", firewallPolicyMinimumVersion)...)
+
+	if r.client != nil && r.client.lockout != nil {
+		var plan firewallPolicyModel
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		var state *firewallPolicyModel
+		if !req.State.Raw.IsNull() {
+			state = &firewallPolicyModel{}
+			resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(r.client.lockout.checkFirewallPolicy(ctx, state, &plan)...)
+	}
+}
+
 func (r *firewallPolicyResource) Create(
 	ctx context.Context,
 	req resource.CreateRequest,
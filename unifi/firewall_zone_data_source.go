@@ -46,7 +46,7 @@ func (d *firewallZoneDataSource) Schema(
 	resp *datasource.SchemaResponse,
 ) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Data source for UniFi firewall zones (zone-based firewall, UniFi Network 8.x+). " +
+		MarkdownDescription: "Data source for UniFi firewall zones (zone-based firewall, UniFi Network 9.0+). " +
 			"Use this to look up zone IDs by name for use in `unifi_firewall_policy` resources.",
 
 		Attributes: map[string]schema.Attribute{
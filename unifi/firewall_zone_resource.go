@@ -25,8 +25,13 @@
 	_ resource.Resource                = &firewallZoneResource{}
 	_ resource.ResourceWithImportState = &firewallZoneResource{}
 	_ resource.ResourceWithIdentity    = &firewallZoneResource{}
+	_ resource.ResourceWithModifyPlan  = &firewallZoneResource{}
 )
 
+// firewallZoneMinimumVersion is the oldest Network Application release with
+// firewall zones.
+const firewallZoneMinimumVersion = "9.0.0"
+
 // Ensure provider defined types fully satisfy list interfaces.
 var (
 	_ list.ListResource              = &firewallZoneResource{}
@@ -98,7 +103,7 @@ func (r *firewallZoneResource) Schema(
 	resp *resource.SchemaResponse,
 ) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Manages a zone-based firewall zone (UniFi OS 8.x+). Create a zone and " +
+		MarkdownDescription: "Manages a zone-based firewall zone (UniFi Network 9.0+). Create a zone and " +
 			"attach networks to it, then reference its `id` from `unifi_firewall_policy`.",
 
 		Attributes: map[string]schema.Attribute{
@@ -171,6 +176,18 @@ func (r *firewallZoneResource) Configure(
 	r.client = client
 }
 
+func (r *firewallZoneResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if req.Plan.Raw.IsNull() {
+		return // resource is being destroyed
+	}
+
+	resp.Diagnostics.Append(r.client.checkMinimumVersion("unifi_firewall_zone", firewallZoneMinimumVersion)...)
+}
+
 func (r *firewallZoneResource) Create(
 	ctx context.Context,
 	req resource.CreateRequest,
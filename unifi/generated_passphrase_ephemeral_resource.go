@@ -0,0 +1,196 @@
+package unifi
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// WPA-PSK passphrases are 8 to 63 printable ASCII characters; 64 characters
+	// would be read as a raw hex PSK instead.
+	passphraseMinLength     = 8
+	passphraseMaxLength     = 63
+	passphraseDefaultLength = 24
+
+	passphraseLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passphraseDigits  = "0123456789"
+	// passphraseSpecial is printable ASCII punctuation. Space is left out since
+	// leading and trailing spaces are easy to lose when a passphrase is shared.
+	passphraseSpecial = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+	// passphraseAmbiguous are characters that are easily confused when a guest
+	// types the passphrase from a printout.
+	passphraseAmbiguous = "0O1lI|"
+)
+
+var _ ephemeral.EphemeralResource = &generatedPassphraseEphemeralResource{}
+
+// NewGeneratedPassphraseEphemeralResource returns a new instance of the
+// generated passphrase ephemeral resource.
+func NewGeneratedPassphraseEphemeralResource() ephemeral.EphemeralResource {
+	return &generatedPassphraseEphemeralResource{}
+}
+
+// generatedPassphraseEphemeralResource generates a WPA-compatible passphrase
+// locally. It never talks to the controller, so it needs no client.
+type generatedPassphraseEphemeralResource struct{}
+
+type generatedPassphraseEphemeralResourceModel struct {
+	Length           types.Int64  `tfsdk:"length"`
+	Special          types.Bool   `tfsdk:"special"`
+	ExcludeAmbiguous types.Bool   `tfsdk:"exclude_ambiguous"`
+	Result           types.String `tfsdk:"result"`
+}
+
+func (e *generatedPassphraseEphemeralResource) Metadata(
+	ctx context.Context,
+	req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_generated_passphrase"
+}
+
+func (e *generatedPassphraseEphemeralResource) Schema(
+	ctx context.Context,
+	req ephemeral.SchemaRequest,
+	resp *ephemeral.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a random WPA-PSK compatible passphrase locally. The value is " +
+			"never written to state or plan, so it can be passed to write-only attributes such as " +
+			"`unifi_wlan` `passphrase_wo`. A new passphrase is generated on every run and is sent " +
+			"whenever the consuming resource is created or updated.",
+
+		Attributes: map[string]schema.Attribute{
+			"length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf(
+					"Length of the passphrase, between %d and %d characters. Default: `%d`.",
+					passphraseMinLength,
+					passphraseMaxLength,
+					passphraseDefaultLength,
+				),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(passphraseMinLength, passphraseMaxLength),
+				},
+			},
+			"special": schema.BoolAttribute{
+				MarkdownDescription: "Whether to include punctuation characters. Some client devices and " +
+					"QR code generators handle punctuation poorly. Default: `false`.",
+				Optional: true,
+			},
+			"exclude_ambiguous": schema.BoolAttribute{
+				MarkdownDescription: "Whether to leave out characters that are easily confused when typed " +
+					"from a printout (`0`, `O`, `1`, `l`, `I`, `|`). Default: `false`.",
+				Optional: true,
+			},
+			"result": schema.StringAttribute{
+				MarkdownDescription: "The generated passphrase.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (e *generatedPassphraseEphemeralResource) Open(
+	ctx context.Context,
+	req ephemeral.OpenRequest,
+	resp *ephemeral.OpenResponse,
+) {
+	var data generatedPassphraseEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	length := int64(passphraseDefaultLength)
+	if !data.Length.IsNull() {
+		length = data.Length.ValueInt64()
+	}
+
+	passphrase, err := generatePassphrase(
+		int(length),
+		data.Special.ValueBool(),
+		data.ExcludeAmbiguous.ValueBool(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Generating Passphrase",
+			fmt.Sprintf("Could not generate a passphrase: %s", err),
+		)
+		return
+	}
+
+	data.Length = types.Int64Value(length)
+	data.Special = types.BoolValue(data.Special.ValueBool())
+	data.ExcludeAmbiguous = types.BoolValue(data.ExcludeAmbiguous.ValueBool())
+	data.Result = types.StringValue(passphrase)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// generatePassphrase returns a uniformly random WPA-PSK passphrase of the given
+// length. It always contains at least one letter and one digit, and at least
+// one punctuation character when special is set.
+func generatePassphrase(length int, special, excludeAmbiguous bool) (string, error) {
+	if length < passphraseMinLength || length > passphraseMaxLength {
+		return "", fmt.Errorf(
+			"length must be between %d and %d, got %d",
+			passphraseMinLength,
+			passphraseMaxLength,
+			length,
+		)
+	}
+
+	classes := []string{passphraseLetters, passphraseDigits}
+	if special {
+		classes = append(classes, passphraseSpecial)
+	}
+	if excludeAmbiguous {
+		for i, class := range classes {
+			classes[i] = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(passphraseAmbiguous, r) {
+					return -1
+				}
+				return r
+			}, class)
+		}
+	}
+	charset := strings.Join(classes, "")
+
+	for {
+		out := make([]byte, length)
+		for i := range out {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+			if err != nil {
+				return "", err
+			}
+			out[i] = charset[n.Int64()]
+		}
+
+		// Redraw rather than patch in required characters, which would bias
+		// where they appear.
+		if containsEveryClass(string(out), classes) {
+			return string(out), nil
+		}
+	}
+}
+
+func containsEveryClass(s string, classes []string) bool {
+	for _, class := range classes {
+		if !strings.ContainsAny(s, class) {
+			return false
+		}
+	}
+	return true
+}
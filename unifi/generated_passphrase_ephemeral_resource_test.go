@@ -0,0 +1,93 @@
+package unifi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fwephemeral "github.com/hashicorp/terraform-plugin-framework/ephemeral"
+)
+
+func TestNewGeneratedPassphraseEphemeralResource(t *testing.T) {
+	if got := NewGeneratedPassphraseEphemeralResource(); got == nil {
+		t.Fatal("NewGeneratedPassphraseEphemeralResource() returned nil")
+	}
+}
+
+func Test_generatedPassphraseEphemeralResource_Metadata(t *testing.T) {
+	e := &generatedPassphraseEphemeralResource{}
+	resp := &fwephemeral.MetadataResponse{}
+	e.Metadata(
+		context.Background(),
+		fwephemeral.MetadataRequest{ProviderTypeName: "unifi"},
+		resp,
+	)
+	if resp.TypeName != "unifi_generated_passphrase" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_generated_passphrase")
+	}
+}
+
+func Test_generatedPassphraseEphemeralResource_Schema(t *testing.T) {
+	e := &generatedPassphraseEphemeralResource{}
+	resp := &fwephemeral.SchemaResponse{}
+	e.Schema(context.Background(), fwephemeral.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("Schema() produced errors: %v", resp.Diagnostics)
+	}
+	for _, attr := range []string{"length", "special", "exclude_ambiguous", "result"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("missing attribute %q", attr)
+		}
+	}
+	if !resp.Schema.Attributes["result"].IsSensitive() {
+		t.Error("result must be sensitive")
+	}
+}
+
+func Test_generatePassphrase(t *testing.T) {
+	tests := []struct {
+		name             string
+		length           int
+		special          bool
+		excludeAmbiguous bool
+		wantErr          bool
+	}{
+		{"minimum length", passphraseMinLength, false, false, false},
+		{"maximum length", passphraseMaxLength, false, false, false},
+		{"with special", 32, true, false, false},
+		{"exclude ambiguous", 32, true, true, false},
+		{"too short", passphraseMinLength - 1, false, false, true},
+		{"too long", passphraseMaxLength + 1, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := generatePassphrase(tt.length, tt.special, tt.excludeAmbiguous)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generatePassphrase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != tt.length {
+				t.Errorf("len = %d, want %d", len(got), tt.length)
+			}
+			for _, r := range got {
+				if r < '!' || r > '~' {
+					t.Errorf("character %q is not printable non-space ASCII", r)
+				}
+			}
+			if !strings.ContainsAny(got, passphraseLetters) ||
+				!strings.ContainsAny(got, passphraseDigits) {
+				t.Errorf("%q is missing a letter or digit", got)
+			}
+			if tt.special != strings.ContainsAny(got, passphraseSpecial) {
+				t.Errorf("%q: special characters present = %v, want %v",
+					got, !tt.special, tt.special)
+			}
+			if tt.excludeAmbiguous && strings.ContainsAny(got, passphraseAmbiguous) {
+				t.Errorf("%q contains an ambiguous character", got)
+			}
+		})
+	}
+}
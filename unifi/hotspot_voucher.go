@@ -0,0 +1,197 @@
+package unifi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// voucherRequest is the `create-voucher` command of the `hotspot` manager.
+type voucherRequest struct {
+	Count         int64
+	Quota         int64
+	ExpireMinutes int64
+	UploadKbps    *int64
+	DownloadKbps  *int64
+	DataLimitMB   *int64
+	Note          string
+}
+
+func (r *voucherRequest) command() map[string]any {
+	cmd := map[string]any{
+		"cmd":    "create-voucher",
+		"n":      r.Count,
+		"quota":  r.Quota,
+		"expire": r.ExpireMinutes,
+	}
+	if r.UploadKbps != nil {
+		cmd["up"] = *r.UploadKbps
+	}
+	if r.DownloadKbps != nil {
+		cmd["down"] = *r.DownloadKbps
+	}
+	if r.DataLimitMB != nil {
+		cmd["bytes"] = *r.DataLimitMB
+	}
+	if r.Note != "" {
+		cmd["note"] = r.Note
+	}
+	return cmd
+}
+
+// hotspotVoucher is an entry of `stat/voucher`. Vouchers created together
+// share a CreateTime, but so do batches created within the same second, so
+// a batch is tracked by the IDs of its vouchers.
+type hotspotVoucher struct {
+	ID             string `json:"_id"`
+	Code           string `json:"code"`
+	CreateTime     int64  `json:"create_time"`
+	Duration       int64  `json:"duration"`
+	Quota          int64  `json:"quota"`
+	Used           int64  `json:"used"`
+	Note           string `json:"note"`
+	QosOverwrite   bool   `json:"qos_overwrite"`
+	QosRateMaxUp   int64  `json:"qos_rate_max_up"`
+	QosRateMaxDown int64  `json:"qos_rate_max_down"`
+	QosUsageQuota  int64  `json:"qos_usage_quota"`
+}
+
+// formattedCode returns the code as the controller prints it, e.g.
+// `12345-67890`.
+func (v *hotspotVoucher) formattedCode() string {
+	if len(v.Code) != 10 {
+		return v.Code
+	}
+	return v.Code[:5] + "-" + v.Code[5:]
+}
+
+// createVouchers creates a batch of vouchers and returns its create time.
+func (c *Client) createVouchers(ctx context.Context, site string, req *voucherRequest) (int64, error) {
+	var out []struct {
+		CreateTime int64 `json:"create_time"`
+	}
+	if err := c.executeCommand(ctx, site, "hotspot", req.command(), &out); err != nil {
+		return 0, err
+	}
+	if len(out) == 0 || out[0].CreateTime == 0 {
+		return 0, errors.New("the controller did not return the create time of the vouchers")
+	}
+	return out[0].CreateTime, nil
+}
+
+// sameBatch reports whether v and o could have been created by the same
+// `create-voucher` command.
+func (v *hotspotVoucher) sameBatch(o *hotspotVoucher) bool {
+	return v.CreateTime == o.CreateTime &&
+		v.Note == o.Note &&
+		v.Quota == o.Quota &&
+		v.Duration == o.Duration &&
+		v.QosOverwrite == o.QosOverwrite &&
+		v.QosRateMaxUp == o.QosRateMaxUp &&
+		v.QosRateMaxDown == o.QosRateMaxDown &&
+		v.QosUsageQuota == o.QosUsageQuota
+}
+
+// createVoucherBatch creates a batch of vouchers and returns its create time
+// and vouchers. The controller only returns the create time, which batches
+// created in the same second share, so the batch is made of the vouchers
+// with the requested settings listed after the command that were not listed
+// before it. If there are more of those than requested, another batch with
+// the same settings was created at the same time and the two cannot be told
+// apart.
+func (c *Client) createVoucherBatch(ctx context.Context, site string, req *voucherRequest) (int64, []hotspotVoucher, error) {
+	before, err := c.listVouchers(ctx, site)
+	if err != nil {
+		return 0, nil, err
+	}
+	known := make(map[string]bool, len(before))
+	for _, v := range before {
+		known[v.ID] = true
+	}
+
+	createTime, err := c.createVouchers(ctx, site, req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	after, err := c.listVouchers(ctx, site)
+	if err != nil {
+		return createTime, nil, fmt.Errorf("the vouchers were created, but could not be read back: %w", err)
+	}
+	batch := slices.DeleteFunc(after, func(v hotspotVoucher) bool {
+		return known[v.ID] || v.CreateTime != createTime ||
+			v.Note != req.Note || v.Quota != req.Quota || v.Duration != req.ExpireMinutes
+	})
+	if int64(len(batch)) != req.Count || slices.ContainsFunc(batch, func(v hotspotVoucher) bool {
+		return !v.sameBatch(&batch[0])
+	}) {
+		return createTime, nil, fmt.Errorf(
+			"the vouchers were created, but %d new vouchers were found at create time %d instead of %d; "+
+				"another batch was probably created at the same time, so the new vouchers cannot be identified",
+			len(batch), createTime, req.Count,
+		)
+	}
+	return createTime, batch, nil
+}
+
+// listVouchers returns the site's vouchers that can still be redeemed, oldest
+// batch first and ordered by code within a batch. Used up and expired
+// vouchers are no longer listed by the controller.
+func (c *Client) listVouchers(ctx context.Context, site string) ([]hotspotVoucher, error) {
+	var vouchers []hotspotVoucher
+	err := c.controllerRequest(
+		ctx,
+		http.MethodGet,
+		"/api/s/"+url.PathEscape(site)+"/stat/voucher",
+		nil,
+		&vouchers,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(vouchers, func(a, b hotspotVoucher) int {
+		return cmp.Or(
+			cmp.Compare(a.CreateTime, b.CreateTime),
+			cmp.Compare(a.Note, b.Note),
+			cmp.Compare(a.Quota, b.Quota),
+			cmp.Compare(a.Duration, b.Duration),
+			cmp.Compare(a.Code, b.Code),
+		)
+	})
+	return vouchers, nil
+}
+
+// listVoucherBatch returns the vouchers of the batch created at createTime,
+// for imports. It fails if the vouchers created at that time do not all look
+// like one batch, as two batches created in the same second would be mixed.
+func (c *Client) listVoucherBatch(ctx context.Context, site string, createTime int64) ([]hotspotVoucher, error) {
+	vouchers, err := c.listVouchers(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+	batch := slices.DeleteFunc(vouchers, func(v hotspotVoucher) bool { return v.CreateTime != createTime })
+	if slices.ContainsFunc(batch, func(v hotspotVoucher) bool { return !v.sameBatch(&batch[0]) }) {
+		return nil, fmt.Errorf("more than one batch of vouchers was created at %d", createTime)
+	}
+	return batch, nil
+}
+
+// listVouchersByID returns the vouchers with the given IDs that can still be
+// redeemed.
+func (c *Client) listVouchersByID(ctx context.Context, site string, ids []string) ([]hotspotVoucher, error) {
+	vouchers, err := c.listVouchers(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(vouchers, func(v hotspotVoucher) bool { return !slices.Contains(ids, v.ID) }), nil
+}
+
+// revokeVoucher deletes a voucher so it can no longer be redeemed. Guests
+// already authorized with it keep their access until it expires.
+func (c *Client) revokeVoucher(ctx context.Context, site, id string) error {
+	return c.executeCommand(ctx, site, "hotspot", map[string]any{"cmd": "delete-voucher", "_id": id}, nil)
+}
@@ -0,0 +1,246 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action              = &hotspotVouchersGenerateAction{}
+	_ action.ActionWithConfigure = &hotspotVouchersGenerateAction{}
+)
+
+// NewHotspotVouchersGenerateAction returns a new instance of the hotspot
+// voucher generation action.
+func NewHotspotVouchersGenerateAction() action.Action {
+	return &hotspotVouchersGenerateAction{}
+}
+
+type hotspotVouchersGenerateAction struct {
+	client *Client
+}
+
+// hotspotVouchersGenerateActionModel describes the action request data model.
+type hotspotVouchersGenerateActionModel struct {
+	Site          types.String   `tfsdk:"site"`
+	Quantity      types.Int64    `tfsdk:"quantity"`
+	Quota         types.Int64    `tfsdk:"quota"`
+	ExpireMinutes types.Int64    `tfsdk:"expire_minutes"`
+	UploadKbps    types.Int64    `tfsdk:"upload_kbps"`
+	DownloadKbps  types.Int64    `tfsdk:"download_kbps"`
+	DataLimitMB   types.Int64    `tfsdk:"data_limit_mb"`
+	Note          types.String   `tfsdk:"note"`
+	Path          types.String   `tfsdk:"path"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (a *hotspotVouchersGenerateAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_hotspot_vouchers_generate"
+}
+
+func (a *hotspotVouchersGenerateAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a batch of hotspot vouchers for the guest portal that Terraform does not " +
+			"manage, for example a fresh stack to print each week. The codes are reported in the action's " +
+			"output, which Terraform shows while the action runs, and can also be written to a local file. " +
+			"Use the `unifi_hotspot_vouchers` resource to keep the codes in state and revoke them on " +
+			"destroy. " + directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site to create the vouchers on.",
+				Optional:            true,
+			},
+			"quantity": schema.Int64Attribute{
+				MarkdownDescription: "The number of vouchers to create.",
+				Required:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 10000),
+				},
+			},
+			"quota": schema.Int64Attribute{
+				MarkdownDescription: "How many times each voucher can be redeemed. `0` allows unlimited use. " +
+					"Defaults to `1`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"expire_minutes": schema.Int64Attribute{
+				MarkdownDescription: "How long a guest stays authorized after redeeming a voucher, in minutes. " +
+					"Defaults to `1440` (one day).",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"upload_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Upload bandwidth limit for guests, in kbps.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"download_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Download bandwidth limit for guests, in kbps.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"data_limit_mb": schema.Int64Attribute{
+				MarkdownDescription: "Total data a guest may transfer, in megabytes.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"note": schema.StringAttribute{
+				MarkdownDescription: "A note shown with the vouchers in the controller and on printouts.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Local path to write the codes to, one per line. The file is replaced " +
+					"if it exists and is written with mode `0600`. Parent directories are created as needed.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *hotspotVouchersGenerateAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *hotspotVouchersGenerateAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_hotspot_vouchers_generate")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config hotspotVouchersGenerateActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 2*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	site := config.Site.ValueString()
+	if site == "" {
+		site = a.client.Site
+	}
+
+	request := &voucherRequest{
+		Count:         config.Quantity.ValueInt64(),
+		Quota:         1,
+		ExpireMinutes: 1440,
+		UploadKbps:    config.UploadKbps.ValueInt64Pointer(),
+		DownloadKbps:  config.DownloadKbps.ValueInt64Pointer(),
+		DataLimitMB:   config.DataLimitMB.ValueInt64Pointer(),
+		Note:          config.Note.ValueString(),
+	}
+	if !config.Quota.IsNull() {
+		request.Quota = config.Quota.ValueInt64()
+	}
+	if !config.ExpireMinutes.IsNull() {
+		request.ExpireMinutes = config.ExpireMinutes.ValueInt64()
+	}
+
+	createTime, vouchers, err := a.client.createVoucherBatch(ctx, site, request)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Hotspot Vouchers", err.Error())
+		return
+	}
+
+	codes := make([]string, len(vouchers))
+	for i, v := range vouchers {
+		codes[i] = v.formattedCode()
+	}
+
+	if !config.Path.IsNull() {
+		dest := config.Path.ValueString()
+		if err := writeVoucherCodes(dest, codes); err != nil {
+			resp.Diagnostics.AddError(
+				"Error Writing Voucher Codes",
+				fmt.Sprintf("The vouchers were created, but could not be written to %s: %s", dest, err),
+			)
+			return
+		}
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Wrote %d voucher codes to %s", len(codes), dest),
+		})
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Created %d vouchers: %s", len(codes), strings.Join(codes, ", ")),
+	})
+	tflog.Debug(ctx, "Hotspot vouchers created", map[string]any{
+		"site":        site,
+		"create_time": createTime,
+		"count":       len(codes),
+	})
+}
+
+// writeVoucherCodes writes codes to dest, one per line, readable only by the
+// current user.
+func writeVoucherCodes(dest string, codes []string) error {
+	return writePrivateFile(dest, []byte(strings.Join(codes, "\n")+"\n"))
+}
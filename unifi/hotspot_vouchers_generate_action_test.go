@@ -0,0 +1,94 @@
+package unifi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+func TestNewHotspotVouchersGenerateAction(t *testing.T) {
+	got := NewHotspotVouchersGenerateAction()
+	if got == nil {
+		t.Fatal("NewHotspotVouchersGenerateAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_hotspotVouchersGenerateAction_Metadata(t *testing.T) {
+	a := &hotspotVouchersGenerateAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_hotspot_vouchers_generate" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_hotspot_vouchers_generate")
+	}
+}
+
+func Test_hotspotVouchersGenerateAction_Schema(t *testing.T) {
+	a := &hotspotVouchersGenerateAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"site", "quantity", "quota", "expire_minutes", "upload_kbps", "download_kbps", "data_limit_mb", "note", "path", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	if !resp.Schema.Attributes["quantity"].IsRequired() {
+		t.Error("quantity should be required")
+	}
+}
+
+func Test_hotspotVouchersGenerateAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &hotspotVouchersGenerateAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_writeVoucherCodes(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "vouchers", "lobby.txt")
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dest, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeVoucherCodes(dest, []string{"12345-67890", "23456-78901"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "12345-67890\n23456-78901\n"; string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %v, want 0600", perm)
+	}
+}
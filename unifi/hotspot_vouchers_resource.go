@@ -0,0 +1,687 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                = &hotspotVouchersResource{}
+	_ resource.ResourceWithImportState = &hotspotVouchersResource{}
+	_ resource.ResourceWithIdentity    = &hotspotVouchersResource{}
+)
+
+// Ensure provider defined types fully satisfy list interfaces.
+var (
+	_ list.ListResource              = &hotspotVouchersResource{}
+	_ list.ListResourceWithConfigure = &hotspotVouchersResource{}
+)
+
+func NewHotspotVouchersResource() resource.Resource {
+	return &hotspotVouchersResource{}
+}
+
+func NewHotspotVouchersListResource() list.ListResource {
+	return &hotspotVouchersResource{}
+}
+
+// hotspotVouchersResource manages a batch of hotspot vouchers. The controller
+// cannot change vouchers, so every argument forces a new batch.
+type hotspotVouchersResource struct {
+	client *Client
+}
+
+// hotspotVouchersListConfigModel describes the list configuration model.
+type hotspotVouchersListConfigModel struct {
+	Site   types.String `tfsdk:"site"`
+	Filter types.List   `tfsdk:"filter"`
+}
+
+// hotspotVouchersListFilterModel represents a single name/value filter entry.
+type hotspotVouchersListFilterModel struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
+// hotspotVouchersResourceModel describes the resource data model.
+type hotspotVouchersResourceModel struct {
+	ID            types.String      `tfsdk:"id"`
+	Site          types.String      `tfsdk:"site"`
+	Quantity      types.Int64       `tfsdk:"quantity"`
+	Quota         types.Int64       `tfsdk:"quota"`
+	ExpireMinutes types.Int64       `tfsdk:"expire_minutes"`
+	UploadKbps    types.Int64       `tfsdk:"upload_kbps"`
+	DownloadKbps  types.Int64       `tfsdk:"download_kbps"`
+	DataLimitMB   types.Int64       `tfsdk:"data_limit_mb"`
+	Note          types.String      `tfsdk:"note"`
+	Codes         types.List        `tfsdk:"codes"`
+	VoucherIDs    types.List        `tfsdk:"voucher_ids"`
+	CreatedAt     timetypes.RFC3339 `tfsdk:"created_at"`
+	Remaining     types.Int64       `tfsdk:"remaining"`
+	Timeouts      timeouts.Value    `tfsdk:"timeouts"`
+}
+
+func (r *hotspotVouchersResource) Metadata(
+	ctx context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_hotspot_vouchers"
+}
+
+// IdentitySchema implements [resource.ResourceWithIdentity].
+func (r *hotspotVouchersResource) IdentitySchema(
+	_ context.Context,
+	_ resource.IdentitySchemaRequest,
+	resp *resource.IdentitySchemaResponse,
+) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+func (r *hotspotVouchersResource) Schema(
+	ctx context.Context,
+	req resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a batch of hotspot vouchers for the guest portal. Vouchers cannot be " +
+			"changed, so changing any argument creates a new batch. Destroying the resource revokes the " +
+			"batch's vouchers that can still be redeemed; guests already authorized keep their access " +
+			"until it expires. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the batch: the time the controller created it, in Unix seconds. " +
+					"Batches created in the same second share it, so the batch is tracked by `voucher_ids`.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site to create the vouchers on.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"quantity": schema.Int64Attribute{
+				MarkdownDescription: "The number of vouchers to create.",
+				Required:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 10000),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"quota": schema.Int64Attribute{
+				MarkdownDescription: "How many times each voucher can be redeemed. `0` allows unlimited use. " +
+					"Defaults to `1`.",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(1),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"expire_minutes": schema.Int64Attribute{
+				MarkdownDescription: "How long a guest stays authorized after redeeming a voucher, in minutes. " +
+					"Defaults to `1440` (one day).",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(1440),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"upload_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Upload bandwidth limit for guests, in kbps.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"download_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Download bandwidth limit for guests, in kbps.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"data_limit_mb": schema.Int64Attribute{
+				MarkdownDescription: "Total data a guest may transfer, in megabytes.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"note": schema.StringAttribute{
+				MarkdownDescription: "A note shown with the vouchers in the controller and on printouts.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"codes": schema.ListAttribute{
+				MarkdownDescription: "The voucher codes, formatted as the controller prints them (`12345-67890`).",
+				ElementType:         types.StringType,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"voucher_ids": schema.ListAttribute{
+				MarkdownDescription: "The controller IDs of the batch's vouchers. Only these vouchers are read and revoked.",
+				ElementType:         types.StringType,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "When the vouchers were created, in RFC 3339 format.",
+				CustomType:          timetypes.RFC3339Type{},
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"remaining": schema.Int64Attribute{
+				MarkdownDescription: "The number of vouchers in the batch that can still be redeemed.",
+				Computed:            true,
+			},
+			"timeouts": timeouts.Attributes(
+				ctx,
+				timeouts.Opts{Create: true, Read: true, Update: true, Delete: true},
+			),
+		},
+	}
+}
+
+func (r *hotspotVouchersResource) Configure(
+	ctx context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *hotspotVouchersResource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var data hotspotVouchersResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, timeoutDiags := data.Timeouts.Create(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = r.client.Site
+	}
+
+	createTime, vouchers, err := r.client.createVoucherBatch(ctx, site, &voucherRequest{
+		Count:         data.Quantity.ValueInt64(),
+		Quota:         data.Quota.ValueInt64(),
+		ExpireMinutes: data.ExpireMinutes.ValueInt64(),
+		UploadKbps:    data.UploadKbps.ValueInt64Pointer(),
+		DownloadKbps:  data.DownloadKbps.ValueInt64Pointer(),
+		DataLimitMB:   data.DataLimitMB.ValueInt64Pointer(),
+		Note:          data.Note.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Hotspot Vouchers", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(strconv.FormatInt(createTime, 10))
+	data.Site = types.StringValue(site)
+	data.CreatedAt = timetypes.NewRFC3339TimeValue(time.Unix(createTime, 0).UTC())
+	data.Remaining = types.Int64Value(int64(len(vouchers)))
+	resp.Diagnostics.Append(r.setCodes(ctx, &data, vouchers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.SetAttribute(ctx, path.Root("id"), data.ID)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *hotspotVouchersResource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var data hotspotVouchersResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := data.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = r.client.Site
+	}
+
+	// An import only knows the create time; every other read follows the
+	// vouchers the batch was created with.
+	var vouchers []hotspotVoucher
+	if data.VoucherIDs.IsNull() {
+		createTime, err := strconv.ParseInt(data.ID.ValueString(), 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Hotspot Vouchers ID",
+				fmt.Sprintf("%q is not a batch create time in Unix seconds.", data.ID.ValueString()),
+			)
+			return
+		}
+		vouchers, err = r.client.listVoucherBatch(ctx, site, createTime)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Hotspot Vouchers",
+				"Could not read the vouchers created at "+data.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	} else {
+		ids, diags := r.voucherIDs(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		var err error
+		vouchers, err = r.client.listVouchersByID(ctx, site, ids)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Reading Hotspot Vouchers",
+				"Could not read the vouchers created at "+data.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Used up and expired vouchers drop out of the controller's list, so an
+	// empty batch is expected over time and must not make Terraform create a
+	// new one. Only an import, which has no codes yet, needs vouchers to exist.
+	if data.Codes.IsNull() {
+		if len(vouchers) == 0 {
+			resp.Diagnostics.AddError(
+				"Hotspot Vouchers Not Found",
+				"No vouchers that can still be redeemed were created at "+data.ID.ValueString()+".",
+			)
+			return
+		}
+		r.vouchersToModel(&data, vouchers, site)
+		resp.Diagnostics.Append(r.setCodes(ctx, &data, vouchers)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	data.Site = types.StringValue(site)
+	data.Remaining = types.Int64Value(int64(len(vouchers)))
+
+	resp.Diagnostics.Append(resp.Identity.SetAttribute(ctx, path.Root("id"), data.ID)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only applies timeouts; every other argument forces replacement.
+func (r *hotspotVouchersResource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var state, plan hotspotVouchersResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Timeouts = plan.Timeouts
+
+	resp.Diagnostics.Append(resp.Identity.SetAttribute(ctx, path.Root("id"), state.ID)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *hotspotVouchersResource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var data hotspotVouchersResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, timeoutDiags := data.Timeouts.Delete(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = r.client.Site
+	}
+
+	ids, diags := r.voucherIDs(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vouchers, err := r.client.listVouchersByID(ctx, site, ids)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Hotspot Vouchers",
+			"Could not read the vouchers created at "+data.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	for _, v := range vouchers {
+		if err := r.client.revokeVoucher(ctx, site, v.ID); err != nil {
+			resp.Diagnostics.AddError(
+				"Error Revoking Hotspot Voucher",
+				fmt.Sprintf("Could not revoke voucher %s: %s", v.formattedCode(), err.Error()),
+			)
+			return
+		}
+	}
+}
+
+func (r *hotspotVouchersResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	// Import format: "site:id" or just "id" for default site
+	idParts := strings.Split(req.ID, ":")
+
+	if len(idParts) == 2 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("site"), idParts[0])...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+		return
+	}
+
+	if len(idParts) == 1 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Invalid Import ID",
+		"Import ID must be in format 'site:id' or 'id'",
+	)
+}
+
+// vouchersToModel sets the arguments of model from a batch of vouchers, for
+// imports and list results. quantity is the number of vouchers still listed.
+func (r *hotspotVouchersResource) vouchersToModel(
+	model *hotspotVouchersResourceModel,
+	vouchers []hotspotVoucher,
+	site string,
+) {
+	first := vouchers[0]
+	model.ID = types.StringValue(strconv.FormatInt(first.CreateTime, 10))
+	model.Site = types.StringValue(site)
+	model.Quantity = types.Int64Value(int64(len(vouchers)))
+	model.Quota = types.Int64Value(first.Quota)
+	model.ExpireMinutes = types.Int64Value(first.Duration)
+	model.CreatedAt = timetypes.NewRFC3339TimeValue(time.Unix(first.CreateTime, 0).UTC())
+	model.Remaining = types.Int64Value(int64(len(vouchers)))
+
+	model.UploadKbps = types.Int64Null()
+	model.DownloadKbps = types.Int64Null()
+	model.DataLimitMB = types.Int64Null()
+	if first.QosOverwrite {
+		if first.QosRateMaxUp > 0 {
+			model.UploadKbps = types.Int64Value(first.QosRateMaxUp)
+		}
+		if first.QosRateMaxDown > 0 {
+			model.DownloadKbps = types.Int64Value(first.QosRateMaxDown)
+		}
+		if first.QosUsageQuota > 0 {
+			model.DataLimitMB = types.Int64Value(first.QosUsageQuota)
+		}
+	}
+
+	model.Note = types.StringNull()
+	if first.Note != "" {
+		model.Note = types.StringValue(first.Note)
+	}
+}
+
+// setCodes sets the codes and voucher IDs of model from a batch of vouchers.
+func (r *hotspotVouchersResource) setCodes(
+	ctx context.Context,
+	model *hotspotVouchersResourceModel,
+	vouchers []hotspotVoucher,
+) diag.Diagnostics {
+	codes := make([]string, len(vouchers))
+	ids := make([]string, len(vouchers))
+	for i, v := range vouchers {
+		codes[i] = v.formattedCode()
+		ids[i] = v.ID
+	}
+	var diags, d diag.Diagnostics
+	model.Codes, diags = types.ListValueFrom(ctx, types.StringType, codes)
+	model.VoucherIDs, d = types.ListValueFrom(ctx, types.StringType, ids)
+	diags.Append(d...)
+	return diags
+}
+
+// voucherIDs returns the IDs of the vouchers in model's batch.
+func (r *hotspotVouchersResource) voucherIDs(
+	ctx context.Context,
+	model *hotspotVouchersResourceModel,
+) ([]string, diag.Diagnostics) {
+	var ids []string
+	diags := model.VoucherIDs.ElementsAs(ctx, &ids, false)
+	return ids, diags
+}
+
+// ListResourceConfigSchema implements [list.ListResource].
+func (r *hotspotVouchersResource) ListResourceConfigSchema(
+	_ context.Context,
+	_ list.ListResourceSchemaRequest,
+	resp *list.ListResourceSchemaResponse,
+) {
+	resp.Schema = listschema.Schema{
+		MarkdownDescription: "List batches of hotspot vouchers in a site that can still be redeemed.",
+		Attributes: map[string]listschema.Attribute{
+			"site": listschema.StringAttribute{
+				MarkdownDescription: "The name of the site to list vouchers from.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]listschema.Block{
+			"filter": listschema.ListNestedBlock{
+				NestedObject: listschema.NestedBlockObject{
+					Attributes: map[string]listschema.Attribute{
+						"name": listschema.StringAttribute{
+							MarkdownDescription: "The name of the filter to apply. Supported values are: `note`.",
+							Required:            true,
+						},
+						"value": listschema.StringAttribute{
+							MarkdownDescription: "The value to filter by.",
+							Required:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// List implements [list.ListResource].
+func (r *hotspotVouchersResource) List(
+	ctx context.Context,
+	req list.ListRequest,
+	stream *list.ListResultsStream,
+) {
+	var config hotspotVouchersListConfigModel
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	site := config.Site.ValueString()
+	if site == "" {
+		site = r.client.Site
+	}
+
+	var filters []hotspotVouchersListFilterModel
+	if !config.Filter.IsNull() && !config.Filter.IsUnknown() {
+		config.Filter.ElementsAs(ctx, &filters, false)
+	}
+
+	postFilters := make(map[string]string)
+	for _, f := range filters {
+		postFilters[f.Name.ValueString()] = f.Value.ValueString()
+	}
+
+	vouchers, err := r.client.listVouchers(ctx, site)
+	if err != nil {
+		var d diag.Diagnostics
+		d.AddError("Error Listing Hotspot Vouchers", "Could not list hotspot vouchers: "+err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(d)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		// listVouchers orders vouchers by batch, so each batch is a run.
+		for start := 0; start < len(vouchers); {
+			end := start + 1
+			for end < len(vouchers) && vouchers[end].sameBatch(&vouchers[start]) {
+				end++
+			}
+			batch := vouchers[start:end]
+			start = end
+
+			if val, ok := postFilters["note"]; ok {
+				if batch[0].Note != val {
+					continue
+				}
+			}
+
+			result := req.NewListResult(ctx)
+
+			created := time.Unix(batch[0].CreateTime, 0).UTC().Format(time.RFC3339)
+			if batch[0].Note != "" {
+				result.DisplayName = fmt.Sprintf("%s (%d vouchers, %s)", batch[0].Note, len(batch), created)
+			} else {
+				result.DisplayName = fmt.Sprintf("%d vouchers, %s", len(batch), created)
+			}
+
+			var model hotspotVouchersResourceModel
+			r.vouchersToModel(&model, batch, site)
+			result.Diagnostics.Append(r.setCodes(ctx, &model, batch)...)
+			model.Timeouts = timeoutsNullValue()
+
+			result.Diagnostics.Append(
+				result.Identity.SetAttribute(ctx, path.Root("id"), model.ID)...,
+			)
+			result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
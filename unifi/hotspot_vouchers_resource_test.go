@@ -0,0 +1,397 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fwlist "github.com/hashicorp/terraform-plugin-framework/list"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccHotspotVouchers_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHotspotVouchersConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.test", "quantity", "3"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.test", "quota", "1"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.test", "expire_minutes", "480"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.test", "codes.#", "3"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.test", "voucher_ids.#", "3"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.test", "remaining", "3"),
+					resource.TestCheckResourceAttrSet("unifi_hotspot_vouchers.test", "created_at"),
+				),
+			},
+			{
+				ResourceName:      "unifi_hotspot_vouchers.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccHotspotVouchersConfig() string {
+	return `
+resource "unifi_hotspot_vouchers" "test" {
+  quantity       = 3
+  expire_minutes = 480
+  download_kbps  = 10000
+  note           = "tfacc-vouchers"
+}
+`
+}
+
+// Terraform creates both batches in parallel, usually within the same second,
+// so they share a create time and must be kept apart by voucher ID.
+func TestAccHotspotVouchers_concurrent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "unifi_hotspot_vouchers" "lobby" {
+  quantity = 2
+  note     = "tfacc-vouchers-lobby"
+}
+
+resource "unifi_hotspot_vouchers" "pool" {
+  quantity = 3
+  note     = "tfacc-vouchers-pool"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.lobby", "voucher_ids.#", "2"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.lobby", "remaining", "2"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.pool", "voucher_ids.#", "3"),
+					resource.TestCheckResourceAttr("unifi_hotspot_vouchers.pool", "remaining", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccHotspotVouchersList_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHotspotVouchersConfig(),
+			},
+			{
+				Query: true,
+				Config: `
+					provider "unifi" {}
+					list "unifi_hotspot_vouchers" "test" {
+						provider = unifi
+						config {
+							filter {
+								name  = "note"
+								value = "tfacc-vouchers"
+						  }
+					  }
+					}
+				`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLengthAtLeast("unifi_hotspot_vouchers.test", 1),
+				},
+			},
+		},
+	})
+}
+
+func TestNewHotspotVouchersResource(t *testing.T) {
+	r := NewHotspotVouchersResource()
+	if r == nil {
+		t.Fatal("returned nil")
+	}
+	if _, ok := r.(fwresource.ResourceWithConfigure); !ok {
+		t.Error("expected ResourceWithConfigure")
+	}
+	if _, ok := r.(fwresource.ResourceWithImportState); !ok {
+		t.Error("expected ResourceWithImportState")
+	}
+	if _, ok := r.(fwresource.ResourceWithIdentity); !ok {
+		t.Error("expected ResourceWithIdentity")
+	}
+}
+
+func TestNewHotspotVouchersListResource(t *testing.T) {
+	r := NewHotspotVouchersListResource()
+	if r == nil {
+		t.Fatal("returned nil")
+	}
+	if _, ok := r.(fwlist.ListResourceWithConfigure); !ok {
+		t.Error("expected ListResourceWithConfigure")
+	}
+}
+
+func Test_hotspotVouchersResource_Metadata(t *testing.T) {
+	r := &hotspotVouchersResource{}
+	resp := &fwresource.MetadataResponse{}
+	r.Metadata(context.Background(), fwresource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_hotspot_vouchers" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_hotspot_vouchers")
+	}
+}
+
+func Test_hotspotVouchersResource_Schema(t *testing.T) {
+	r := &hotspotVouchersResource{}
+	resp := &fwresource.SchemaResponse{}
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, resp)
+	for _, attr := range []string{"id", "site", "quantity", "quota", "expire_minutes", "upload_kbps", "download_kbps", "data_limit_mb", "note", "codes", "voucher_ids", "created_at", "remaining", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	if !resp.Schema.Attributes["codes"].IsSensitive() {
+		t.Error("codes should be sensitive")
+	}
+}
+
+func Test_hotspotVouchersResource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwresource.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwresource.ConfigureRequest{}, false},
+		{"wrong_type", fwresource.ConfigureRequest{ProviderData: "wrong"}, true},
+		{"correct_client", fwresource.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &hotspotVouchersResource{}
+			resp := &fwresource.ConfigureResponse{}
+			r.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_hotspotVouchersResource_vouchersToModel(t *testing.T) {
+	r := &hotspotVouchersResource{}
+	var model hotspotVouchersResourceModel
+	r.vouchersToModel(&model, []hotspotVoucher{
+		{ID: "a", Code: "1234567890", CreateTime: 1760000000, Duration: 480, Quota: 1, Note: "lobby", QosOverwrite: true, QosRateMaxDown: 10000},
+		{ID: "b", Code: "2345678901", CreateTime: 1760000000, Duration: 480, Quota: 1, Note: "lobby", QosOverwrite: true, QosRateMaxDown: 10000},
+	}, "default")
+
+	if model.ID.ValueString() != "1760000000" {
+		t.Errorf("ID = %q, want %q", model.ID.ValueString(), "1760000000")
+	}
+	if model.Quantity.ValueInt64() != 2 {
+		t.Errorf("Quantity = %d, want 2", model.Quantity.ValueInt64())
+	}
+	if model.ExpireMinutes.ValueInt64() != 480 {
+		t.Errorf("ExpireMinutes = %d, want 480", model.ExpireMinutes.ValueInt64())
+	}
+	if model.DownloadKbps.ValueInt64() != 10000 {
+		t.Errorf("DownloadKbps = %d, want 10000", model.DownloadKbps.ValueInt64())
+	}
+	if !model.UploadKbps.IsNull() {
+		t.Errorf("UploadKbps = %s, want null", model.UploadKbps)
+	}
+	if model.Note.ValueString() != "lobby" {
+		t.Errorf("Note = %q, want %q", model.Note.ValueString(), "lobby")
+	}
+	if model.CreatedAt.ValueString() != "2025-10-09T08:53:20Z" {
+		t.Errorf("CreatedAt = %q, want %q", model.CreatedAt.ValueString(), "2025-10-09T08:53:20Z")
+	}
+}
+
+func Test_hotspotVouchersResource_ListResourceConfigSchema(t *testing.T) {
+	r := &hotspotVouchersResource{}
+	resp := &fwlist.ListResourceSchemaResponse{}
+	r.ListResourceConfigSchema(context.Background(), fwlist.ListResourceSchemaRequest{}, resp)
+	if len(resp.Schema.Attributes) == 0 {
+		t.Error("expected non-empty list resource schema")
+	}
+}
+
+func Test_hotspotVoucher_formattedCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"1234567890", "12345-67890"},
+		{"12345", "12345"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		v := hotspotVoucher{Code: tt.code}
+		if got := v.formattedCode(); got != tt.want {
+			t.Errorf("formattedCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestClient_vouchers(t *testing.T) {
+	vouchers := []map[string]any{
+		{"_id": "v3", "code": "5555566666", "create_time": 1760000100, "quota": 1, "duration": 60},
+		{"_id": "v2", "code": "3333344444", "create_time": 1760000000, "quota": 1, "duration": 480},
+		{"_id": "v1", "code": "1111122222", "create_time": 1760000000, "quota": 1, "duration": 480},
+	}
+	// concurrent makes create-voucher also add a voucher of another batch
+	// with the same settings created in the same second.
+	concurrent := false
+	var commands []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data any
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/s/default/stat/voucher":
+			data = vouchers
+		case r.Method == http.MethodPost && r.URL.Path == "/api/s/default/cmd/hotspot":
+			var cmd map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&cmd)
+			commands = append(commands, cmd)
+			if cmd["cmd"] == "create-voucher" {
+				n, _ := cmd["n"].(float64)
+				for i := range int(n) {
+					vouchers = append(vouchers, map[string]any{
+						"_id":         fmt.Sprintf("c%d-%d", len(commands), i),
+						"code":        fmt.Sprintf("%010d", len(commands)*100+i),
+						"create_time": 1760000200,
+						"quota":       cmd["quota"],
+						"duration":    cmd["expire"],
+						"note":        cmd["note"],
+					})
+				}
+				if concurrent {
+					vouchers = append(vouchers, map[string]any{
+						"_id":         "other",
+						"code":        "9999999999",
+						"create_time": 1760000200,
+						"quota":       cmd["quota"],
+						"duration":    cmd["expire"],
+						"note":        cmd["note"],
+					})
+				}
+			}
+			data = []map[string]any{{"create_time": 1760000200}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"meta": map[string]any{"rc": "ok"},
+			"data": data,
+		})
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+	ctx := context.Background()
+
+	down := int64(10000)
+	req := &voucherRequest{
+		Count:         3,
+		Quota:         1,
+		ExpireMinutes: 480,
+		DownloadKbps:  &down,
+		Note:          "lobby",
+	}
+	createTime, created, err := c.createVoucherBatch(ctx, "default", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if createTime != 1760000200 {
+		t.Errorf("createVoucherBatch() create time = %d, want 1760000200", createTime)
+	}
+	if len(created) != 3 {
+		t.Errorf("createVoucherBatch() returned %d vouchers, want 3", len(created))
+	}
+	if len(commands) != 1 {
+		t.Fatalf("sent %d commands, want 1", len(commands))
+	}
+	want := map[string]any{"cmd": "create-voucher", "n": 3.0, "quota": 1.0, "expire": 480.0, "down": 10000.0, "note": "lobby"}
+	for k, v := range want {
+		if commands[0][k] != v {
+			t.Errorf("command[%q] = %v, want %v", k, commands[0][k], v)
+		}
+	}
+	if _, ok := commands[0]["up"]; ok {
+		t.Error("command should not set up")
+	}
+
+	// A second batch in the same second is told apart by its voucher IDs.
+	req.Count = 2
+	_, second, err := c.createVoucherBatch(ctx, "default", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != 2 || second[0].ID != "c2-0" || second[1].ID != "c2-1" {
+		t.Errorf("second createVoucherBatch() = %+v, want c2-0 and c2-1", second)
+	}
+
+	// A batch with other settings created in the same second is ignored.
+	vouchers = append(vouchers, map[string]any{
+		"_id": "unrelated", "code": "8888888888", "create_time": 1760000200, "quota": 0, "duration": 60,
+	})
+	_, third, err := c.createVoucherBatch(ctx, "default", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(third) != 2 || third[0].ID != "c3-0" || third[1].ID != "c3-1" {
+		t.Errorf("third createVoucherBatch() = %+v, want c3-0 and c3-1", third)
+	}
+
+	// Another batch with the same settings created at the same time makes
+	// the new vouchers ambiguous.
+	concurrent = true
+	if _, _, err := c.createVoucherBatch(ctx, "default", req); err == nil {
+		t.Error("createVoucherBatch() with a concurrent batch succeeded, want an error")
+	}
+
+	batch, err := c.listVoucherBatch(ctx, "default", 1760000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 || batch[0].ID != "v1" || batch[1].ID != "v2" {
+		t.Errorf("listVoucherBatch() = %+v, want v1 and v2", batch)
+	}
+	if _, err := c.listVoucherBatch(ctx, "default", 1760000200); err == nil {
+		t.Error("listVoucherBatch() of two batches created together succeeded, want an error")
+	}
+
+	byID, err := c.listVouchersByID(ctx, "default", []string{"v3", "c1-1", "gone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byID) != 2 || byID[0].ID != "v3" || byID[1].ID != "c1-1" {
+		t.Errorf("listVouchersByID() = %+v, want v3 and c1-1", byID)
+	}
+
+	commands = nil
+	if err := c.revokeVoucher(ctx, "default", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := commands[0]; got["cmd"] != "delete-voucher" || got["_id"] != "v1" {
+		t.Errorf("revoke command = %v", got)
+	}
+}
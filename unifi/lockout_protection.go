@@ -0,0 +1,531 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// managementPath is the part of the network that carries the provider's
+// connection to the controller: the networks the provider's host and the
+// controller are on, and the devices and switch ports between them and the
+// gateway. With lockout_protection, plans that would cut it are refused.
+type managementPath struct {
+	Site string
+
+	Provider   pathEndpoint
+	Controller pathEndpoint
+
+	// Networks, Devices and Ports map what is on the path to a description of
+	// why, for diagnostics. Devices and Ports are keyed by normalized MAC.
+	Networks map[string]string
+	Devices  map[string]string
+	Ports    map[string]map[int64]string
+}
+
+// pathEndpoint is one end of the provider's connection.
+type pathEndpoint struct {
+	Name string
+	IP   netip.Addr
+	MAC  string
+	// Port is the TCP port of the controller; zero for the provider's side.
+	Port      int
+	NetworkID string
+	// ZoneIDs are the firewall zones the endpoint is in. Empty if unknown.
+	ZoneIDs []string
+}
+
+// lockoutTopology is what detectManagementPath reads from the controller.
+type lockoutTopology struct {
+	Networks []lockoutNetwork
+	Zones    []lockoutZone
+	Clients  []lockoutClient
+	Devices  []lockoutDevice
+}
+
+type lockoutNetwork struct {
+	ID string
+	// Subnet is the gateway address and prefix length, as in ip_subnet.
+	Subnet netip.Prefix
+}
+
+type lockoutZone struct {
+	ID         string
+	Key        string
+	NetworkIDs []string
+}
+
+type lockoutClient struct {
+	IP         string `json:"ip"`
+	MAC        string `json:"mac"`
+	UplinkMAC  string `json:"last_uplink_mac"`
+	UplinkPort int64  `json:"last_uplink_remote_port"`
+	SwMAC      string `json:"sw_mac"`
+	SwPort     int64  `json:"sw_port"`
+}
+
+type lockoutDevice struct {
+	MAC    string `json:"mac"`
+	Name   string `json:"name"`
+	Uplink struct {
+		UplinkMAC  string `json:"uplink_mac"`
+		RemotePort int64  `json:"uplink_remote_port"`
+		PortIdx    int64  `json:"port_idx"`
+	} `json:"uplink"`
+}
+
+// maxUplinkHops bounds the walk up the uplink chain, in case the controller
+// reports a loop.
+const maxUplinkHops = 16
+
+// detectManagementPath works out the provider's own path to the controller.
+func detectManagementPath(
+	ctx context.Context,
+	c *Client,
+	httpClient *http.Client,
+	apiURL, apiKey string,
+) (*managementPath, error) {
+	if c.controller.APIBaseURL == "" {
+		return nil, fmt.Errorf("the controller could not be detected")
+	}
+
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	port := 443
+	if p := u.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return nil, err
+		}
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return nil, fmt.Errorf("could not resolve %s: %w", u.Hostname(), err)
+	}
+	controllerIP := addrs[0].Unmap()
+
+	// Connecting a UDP socket sends nothing; it only selects the local
+	// address the system routes to the controller from.
+	conn, err := net.DialUDP("udp", nil, net.UDPAddrFromAddrPort(netip.AddrPortFrom(controllerIP, uint16(port))))
+	if err != nil {
+		return nil, fmt.Errorf("could not determine the local address used to reach the controller: %w", err)
+	}
+	local, ok := conn.LocalAddr().(*net.UDPAddr)
+	_ = conn.Close()
+	if !ok {
+		return nil, fmt.Errorf("unexpected local address %s", conn.LocalAddr())
+	}
+	providerIP := local.AddrPort().Addr().Unmap()
+
+	var topo lockoutTopology
+
+	networks, err := c.ListNetwork(ctx, c.Site)
+	if err != nil {
+		return nil, fmt.Errorf("could not list networks: %w", err)
+	}
+	for _, n := range networks {
+		if n.IPSubnet == nil {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(*n.IPSubnet); err == nil {
+			topo.Networks = append(topo.Networks, lockoutNetwork{ID: n.ID, Subnet: prefix})
+		}
+	}
+
+	// Controllers without zone-based firewalls have no zones.
+	if zones, err := c.ListFirewallZone(ctx, c.Site); err == nil {
+		for _, z := range zones {
+			topo.Zones = append(topo.Zones, lockoutZone{ID: z.ID, Key: z.ZoneKey, NetworkIDs: z.NetworkIDs})
+		}
+	}
+
+	site := url.PathEscape(c.Site)
+	var clients struct {
+		Data []lockoutClient `json:"data"`
+	}
+	if err := controllerGetJSON(ctx, httpClient, c.controller.APIBaseURL+"/api/s/"+site+"/stat/sta", apiKey, &clients); err != nil {
+		return nil, fmt.Errorf("could not list clients: %w", err)
+	}
+	topo.Clients = clients.Data
+
+	var devices struct {
+		Data []lockoutDevice `json:"data"`
+	}
+	if err := controllerGetJSON(ctx, httpClient, c.controller.APIBaseURL+"/api/s/"+site+"/stat/device", apiKey, &devices); err != nil {
+		return nil, fmt.Errorf("could not list devices: %w", err)
+	}
+	topo.Devices = devices.Data
+
+	return newManagementPath(c.Site, providerIP, controllerIP, port, topo), nil
+}
+
+// newManagementPath finds the path between the provider and the controller
+// in topo.
+func newManagementPath(
+	site string,
+	providerIP, controllerIP netip.Addr,
+	controllerPort int,
+	topo lockoutTopology,
+) *managementPath {
+	p := &managementPath{
+		Site:       site,
+		Provider:   pathEndpoint{Name: "the host running Terraform", IP: providerIP},
+		Controller: pathEndpoint{Name: "the controller", IP: controllerIP, Port: controllerPort},
+		Networks:   map[string]string{},
+		Devices:    map[string]string{},
+		Ports:      map[string]map[int64]string{},
+	}
+
+	devices := map[string]lockoutDevice{}
+	for _, d := range topo.Devices {
+		devices[cleanMAC(d.MAC)] = d
+	}
+
+	for _, ep := range []*pathEndpoint{&p.Provider, &p.Controller} {
+		var gateway bool
+		for _, n := range topo.Networks {
+			if n.Subnet.Contains(ep.IP) {
+				ep.NetworkID = n.ID
+				gateway = n.Subnet.Addr() == ep.IP
+				p.Networks[n.ID] = ep.Name + " is on it"
+				break
+			}
+		}
+
+		for _, z := range topo.Zones {
+			// The gateway's own addresses are in the gateway zone, not in
+			// the zones of the networks it routes.
+			if (gateway && z.Key == "gateway") || (!gateway && ep.NetworkID != "" && slices.Contains(z.NetworkIDs, ep.NetworkID)) {
+				ep.ZoneIDs = append(ep.ZoneIDs, z.ID)
+			}
+		}
+
+		for _, cl := range topo.Clients {
+			if cl.IP != ep.IP.String() {
+				continue
+			}
+			ep.MAC = cleanMAC(cl.MAC)
+
+			mac, port := cl.UplinkMAC, cl.UplinkPort
+			if cl.SwMAC != "" {
+				mac, port = cl.SwMAC, cl.SwPort
+			}
+			p.walkUplinks(devices, cleanMAC(mac), port, ep.Name)
+			break
+		}
+	}
+
+	return p
+}
+
+// walkUplinks marks port on the device with the given MAC, then the device's
+// uplink port and the port it connects to upstream, up to the gateway.
+func (p *managementPath) walkUplinks(devices map[string]lockoutDevice, mac string, port int64, endpoint string) {
+	for range maxUplinkHops {
+		if mac == "" {
+			return
+		}
+		d, ok := devices[mac]
+		if !ok {
+			return
+		}
+		name := d.Name
+		if name == "" {
+			name = mac
+		}
+		p.Devices[mac] = fmt.Sprintf("%s connects to the controller through %s", endpoint, name)
+		if port > 0 {
+			p.addPort(mac, port, fmt.Sprintf("%s connects to the controller through port %d of %s", endpoint, port, name))
+		}
+		if d.Uplink.PortIdx > 0 {
+			p.addPort(mac, d.Uplink.PortIdx, fmt.Sprintf("port %d is the uplink of %s", d.Uplink.PortIdx, name))
+		}
+		mac, port = cleanMAC(d.Uplink.UplinkMAC), d.Uplink.RemotePort
+	}
+}
+
+func (p *managementPath) addPort(mac string, port int64, why string) {
+	if p.Ports[mac] == nil {
+		p.Ports[mac] = map[int64]string{}
+	}
+	if _, ok := p.Ports[mac][port]; !ok {
+		p.Ports[mac][port] = why
+	}
+}
+
+// empty reports whether nothing on the path could be identified.
+func (p *managementPath) empty() bool {
+	return len(p.Networks) == 0 && len(p.Devices) == 0
+}
+
+// appliesTo reports whether a resource in the given site can affect the path.
+func (p *managementPath) appliesTo(site types.String) bool {
+	if p == nil {
+		return false
+	}
+	return site.IsNull() || site.IsUnknown() || site.ValueString() == "" || site.ValueString() == p.Site
+}
+
+func addLockoutError(diags *diag.Diagnostics, what, why string) {
+	diags.AddError(
+		"Change Would Lock Out the Provider",
+		fmt.Sprintf(
+			"%s, but %s. The provider would lose its connection to the controller. "+
+				"lockout_protection is enabled, so this plan is refused; make the change from "+
+				"a connection that does not depend on it, or disable lockout_protection.",
+			what, why,
+		),
+	)
+}
+
+// checkNetwork refuses destroying a network on the path, or changing its VLAN
+// or subnet, or disabling it. plan is nil when the network is being destroyed.
+func (p *managementPath) checkNetwork(state, plan *networkResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if state == nil || !p.appliesTo(state.Site) {
+		return diags
+	}
+	why, ok := p.Networks[state.ID.ValueString()]
+	if !ok {
+		return diags
+	}
+	name := state.Name.ValueString()
+
+	switch {
+	case plan == nil:
+		addLockoutError(&diags, fmt.Sprintf("This plan destroys network %q", name), why)
+	case !plan.Vlan.IsUnknown() && !plan.Vlan.Equal(state.Vlan):
+		addLockoutError(&diags, fmt.Sprintf("This plan changes the VLAN of network %q", name), why)
+	case !plan.Subnet.IsUnknown() && !plan.Subnet.Equal(state.Subnet):
+		addLockoutError(&diags, fmt.Sprintf("This plan changes the subnet of network %q", name), why)
+	case !plan.Enabled.IsUnknown() && !plan.Enabled.IsNull() && !plan.Enabled.ValueBool() &&
+		!plan.Enabled.Equal(state.Enabled):
+		addLockoutError(&diags, fmt.Sprintf("This plan disables network %q", name), why)
+	}
+	return diags
+}
+
+// checkDevice refuses forgetting or disabling a device on the path, moving its
+// management network, and disabling, re-VLANing or dropping the path's network
+// from a port on the path. plan is nil when the device is being destroyed.
+func (p *managementPath) checkDevice(ctx context.Context, state, plan *deviceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan == nil {
+		// Without forget_on_destroy the device is only removed from state.
+		if state == nil || !state.ForgetOnDestroy.ValueBool() || !p.appliesTo(state.Site) {
+			return diags
+		}
+		why, ok := p.Devices[cleanMAC(state.MAC.ValueString())]
+		if !ok {
+			return diags
+		}
+		name := state.Name.ValueString()
+		if name == "" {
+			name = state.MAC.ValueString()
+		}
+		addLockoutError(&diags, fmt.Sprintf("This plan forgets device %s", name), why)
+		return diags
+	}
+	if !p.appliesTo(plan.Site) || plan.MAC.IsUnknown() {
+		return diags
+	}
+	mac := cleanMAC(plan.MAC.ValueString())
+	name := plan.Name.ValueString()
+	if name == "" {
+		name = plan.MAC.ValueString()
+	}
+
+	if why, ok := p.Devices[mac]; ok {
+		// A device that is already disabled is not on the path.
+		if plan.Disabled.ValueBool() && (state == nil || !state.Disabled.ValueBool()) {
+			addLockoutError(&diags, fmt.Sprintf("This plan disables device %s", name), why)
+		}
+		if state != nil && !plan.MgmtNetworkID.IsUnknown() && !plan.MgmtNetworkID.Equal(state.MgmtNetworkID) {
+			addLockoutError(&diags, fmt.Sprintf("This plan changes the management network of device %s", name), why)
+		}
+	}
+
+	ports := p.Ports[mac]
+	if len(ports) == 0 || plan.PortOverride.IsNull() || plan.PortOverride.IsUnknown() {
+		return diags
+	}
+	var overrides []portOverrideModel
+	diags.Append(plan.PortOverride.ElementsAs(ctx, &overrides, false)...)
+	var stateNative map[int64]types.String
+	if state != nil && !state.PortOverride.IsNull() && !state.PortOverride.IsUnknown() {
+		var prior []portOverrideModel
+		diags.Append(state.PortOverride.ElementsAs(ctx, &prior, false)...)
+		stateNative = map[int64]types.String{}
+		for _, po := range prior {
+			stateNative[po.Index.ValueInt64()] = po.NativeNetworkID
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, po := range overrides {
+		idx := po.Index.ValueInt64()
+		why, ok := ports[idx]
+		if !ok {
+			continue
+		}
+		what := fmt.Sprintf("This plan %%s port %d of device %s", idx, name)
+
+		if po.Forward.ValueString() == "disabled" || po.OpMode.ValueString() == "disabled" {
+			addLockoutError(&diags, fmt.Sprintf(what, "disables"), why)
+		}
+		native := po.NativeNetworkID
+		if !native.IsNull() && !native.IsUnknown() && !native.Equal(stateNative[idx]) {
+			if _, onPath := p.Networks[native.ValueString()]; !onPath && len(p.Networks) > 0 {
+				addLockoutError(&diags, fmt.Sprintf(what, "changes the native network of"), why)
+			}
+		}
+		if !po.ExcludedNetworkIDs.IsNull() && !po.ExcludedNetworkIDs.IsUnknown() {
+			var excluded []string
+			diags.Append(po.ExcludedNetworkIDs.ElementsAs(ctx, &excluded, false)...)
+			for _, id := range excluded {
+				if _, onPath := p.Networks[id]; onPath {
+					addLockoutError(&diags, fmt.Sprintf(what, "removes the management network from"), why)
+					break
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// checkFirewallPolicy refuses creating an enabled BLOCK or REJECT policy that
+// matches traffic from the provider to the controller, or changing a policy
+// into one. Matches that cannot be evaluated offline, such as IP and port
+// groups, are assumed to match. state is nil when the policy is being created;
+// a policy that is already applied and keeps its action, enabled flag, source
+// and destination evidently does not cut the path.
+func (p *managementPath) checkFirewallPolicy(ctx context.Context, state, plan *firewallPolicyModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan == nil || !p.appliesTo(plan.Site) {
+		return diags
+	}
+	if state != nil && plan.Action.Equal(state.Action) && plan.Enabled.Equal(state.Enabled) &&
+		plan.Source.Equal(state.Source) && plan.Destination.Equal(state.Destination) {
+		return diags
+	}
+	if action := plan.Action.ValueString(); action != "BLOCK" && action != "REJECT" {
+		return diags
+	}
+	if !plan.Enabled.IsNull() && !plan.Enabled.IsUnknown() && !plan.Enabled.ValueBool() {
+		return diags
+	}
+	if plan.Source.IsNull() || plan.Source.IsUnknown() || plan.Destination.IsNull() || plan.Destination.IsUnknown() {
+		return diags
+	}
+
+	var src, dst firewallPolicyEndpointModel
+	diags.Append(plan.Source.As(ctx, &src, basetypes.ObjectAsOptions{})...)
+	diags.Append(plan.Destination.As(ctx, &dst, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if p.Provider.matches(ctx, src) && p.Controller.matches(ctx, dst) {
+		addLockoutError(
+			&diags,
+			fmt.Sprintf("Firewall policy %q would %s traffic", plan.Name.ValueString(), strings.ToLower(plan.Action.ValueString())),
+			fmt.Sprintf("it matches the connection from %s (%s) to %s (%s)", p.Provider.Name, p.Provider.IP, p.Controller.Name, p.Controller.IP),
+		)
+	}
+	return diags
+}
+
+// matches reports whether a firewall policy endpoint may match e.
+func (e pathEndpoint) matches(ctx context.Context, ep firewallPolicyEndpointModel) bool {
+	if !e.IP.IsValid() {
+		return false
+	}
+	if len(e.ZoneIDs) > 0 && !ep.ZoneID.IsUnknown() && !slices.Contains(e.ZoneIDs, ep.ZoneID.ValueString()) {
+		return false
+	}
+	if e.Port > 0 && !portMatches(ep, e.Port) {
+		return false
+	}
+
+	values := func(l types.List) []string {
+		var out []string
+		if !l.IsNull() && !l.IsUnknown() {
+			_ = l.ElementsAs(ctx, &out, false)
+		}
+		return out
+	}
+
+	switch ep.MatchingTarget.ValueString() {
+	case "ANY":
+		// Without zone information, ANY only matches within a zone the
+		// endpoint is known to be in.
+		return len(e.ZoneIDs) > 0
+	case "NETWORK":
+		return e.NetworkID != "" && slices.Contains(values(ep.NetworkIDs), e.NetworkID)
+	case "IP":
+		if !ep.IPGroupID.IsNull() && ep.IPGroupID.ValueString() != "" {
+			return true
+		}
+		return slices.ContainsFunc(values(ep.IPs), func(s string) bool { return ipMatches(s, e.IP) })
+	case "CLIENT", "MAC", "DEVICE":
+		return e.MAC != "" && slices.ContainsFunc(values(ep.ClientMACs), func(s string) bool { return cleanMAC(s) == e.MAC })
+	}
+	return false
+}
+
+// portMatches reports whether a destination endpoint may match port.
+func portMatches(ep firewallPolicyEndpointModel, port int) bool {
+	if ep.PortMatchingType.ValueString() != "SPECIFIC" {
+		return true
+	}
+	if !ep.PortGroupID.IsNull() && ep.PortGroupID.ValueString() != "" {
+		return true
+	}
+	for _, part := range strings.Split(ep.Port.ValueString(), ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		from, err := strconv.Atoi(lo)
+		if err != nil {
+			continue
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(hi); err != nil {
+				continue
+			}
+		}
+		if port >= from && port <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// ipMatches reports whether s, an address, CIDR prefix or address range,
+// contains ip.
+func ipMatches(s string, ip netip.Addr) bool {
+	s = strings.TrimSpace(s)
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		lo, err1 := netip.ParseAddr(strings.TrimSpace(from))
+		hi, err2 := netip.ParseAddr(strings.TrimSpace(to))
+		return err1 == nil && err2 == nil && lo.Compare(ip) <= 0 && ip.Compare(hi) <= 0
+	}
+	if prefix, err := netip.ParsePrefix(s); err == nil {
+		return prefix.Contains(ip)
+	}
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr == ip
+}
@@ -0,0 +1,500 @@
+package unifi
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testManagementPath is a provider on the default LAN behind two switches,
+// managing a controller on the gateway.
+//
+//	gateway (aa:00) port 2 <- usw-core (aa:01) port 5 <- usw-desk (aa:02) port 7 <- provider
+func testManagementPath() *managementPath {
+	topo := lockoutTopology{
+		Networks: []lockoutNetwork{
+			{ID: "lan", Subnet: netip.MustParsePrefix("192.168.1.1/24")},
+			{ID: "iot", Subnet: netip.MustParsePrefix("192.168.20.1/24")},
+		},
+		Zones: []lockoutZone{
+			{ID: "zone-internal", Key: "internal", NetworkIDs: []string{"lan"}},
+			{ID: "zone-iot", Key: "iot", NetworkIDs: []string{"iot"}},
+			{ID: "zone-gateway", Key: "gateway"},
+		},
+		Clients: []lockoutClient{
+			{IP: "192.168.1.50", MAC: "BB-00-00-00-00-01", SwMAC: "aa:00:00:00:00:02", SwPort: 7},
+			{IP: "192.168.20.10", MAC: "bb:00:00:00:00:02", SwMAC: "aa:00:00:00:00:01", SwPort: 9},
+		},
+		Devices: []lockoutDevice{
+			{MAC: "aa:00:00:00:00:00", Name: "gateway"},
+			{MAC: "aa:00:00:00:00:01", Name: "usw-core"},
+			{MAC: "aa:00:00:00:00:02", Name: "usw-desk"},
+		},
+	}
+	topo.Devices[1].Uplink.UplinkMAC = "aa:00:00:00:00:00"
+	topo.Devices[1].Uplink.RemotePort = 2
+	topo.Devices[1].Uplink.PortIdx = 1
+	topo.Devices[2].Uplink.UplinkMAC = "aa:00:00:00:00:01"
+	topo.Devices[2].Uplink.RemotePort = 5
+	topo.Devices[2].Uplink.PortIdx = 1
+
+	return newManagementPath(
+		"default",
+		netip.MustParseAddr("192.168.1.50"),
+		netip.MustParseAddr("192.168.1.1"),
+		443,
+		topo,
+	)
+}
+
+// nullObjectAttributes returns a null value for each attribute type, for
+// building objects in tests that only set a few attributes.
+func nullObjectAttributes(t *testing.T, attrTypes map[string]attr.Type) map[string]attr.Value {
+	t.Helper()
+	ctx := context.Background()
+	values := map[string]attr.Value{}
+	for name, typ := range attrTypes {
+		v, err := typ.ValueFromTerraform(ctx, tftypes.NewValue(typ.TerraformType(ctx), nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		values[name] = v
+	}
+	return values
+}
+
+func Test_newManagementPath(t *testing.T) {
+	p := testManagementPath()
+
+	if p.Provider.NetworkID != "lan" || p.Provider.MAC != "bb:00:00:00:00:01" {
+		t.Errorf("provider = %+v, want on lan with its MAC", p.Provider)
+	}
+	if got := p.Provider.ZoneIDs; len(got) != 1 || got[0] != "zone-internal" {
+		t.Errorf("provider zones = %v, want [zone-internal]", got)
+	}
+	if got := p.Controller.ZoneIDs; len(got) != 1 || got[0] != "zone-gateway" {
+		t.Errorf("controller zones = %v, want [zone-gateway]", got)
+	}
+
+	if _, ok := p.Networks["lan"]; !ok || len(p.Networks) != 1 {
+		t.Errorf("networks = %v, want only lan", p.Networks)
+	}
+	for _, mac := range []string{"aa:00:00:00:00:00", "aa:00:00:00:00:01", "aa:00:00:00:00:02"} {
+		if _, ok := p.Devices[mac]; !ok {
+			t.Errorf("device %s is not on the path", mac)
+		}
+	}
+
+	wantPorts := map[string][]int64{
+		"aa:00:00:00:00:00": {2},
+		"aa:00:00:00:00:01": {1, 5},
+		"aa:00:00:00:00:02": {1, 7},
+	}
+	for mac, ports := range wantPorts {
+		if len(p.Ports[mac]) != len(ports) {
+			t.Errorf("ports of %s = %v, want %v", mac, p.Ports[mac], ports)
+		}
+		for _, port := range ports {
+			if _, ok := p.Ports[mac][port]; !ok {
+				t.Errorf("port %d of %s is not on the path", port, mac)
+			}
+		}
+	}
+	if _, ok := p.Ports["aa:00:00:00:00:01"][9]; ok {
+		t.Error("port of another client is on the path")
+	}
+}
+
+func Test_ipMatches(t *testing.T) {
+	ip := netip.MustParseAddr("192.168.1.50")
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"192.168.1.50", true},
+		{"192.168.1.51", false},
+		{"192.168.1.0/24", true},
+		{"10.0.0.0/8", false},
+		{"192.168.1.10-192.168.1.100", true},
+		{"192.168.1.60-192.168.1.100", false},
+		{"not an address", false},
+	}
+	for _, tt := range tests {
+		if got := ipMatches(tt.in, ip); got != tt.want {
+			t.Errorf("ipMatches(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_portMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		ep   firewallPolicyEndpointModel
+		want bool
+	}{
+		{"any port", firewallPolicyEndpointModel{PortMatchingType: types.StringValue("ANY")}, true},
+		{"same port", firewallPolicyEndpointModel{PortMatchingType: types.StringValue("SPECIFIC"), Port: types.StringValue("443")}, true},
+		{"in a range", firewallPolicyEndpointModel{PortMatchingType: types.StringValue("SPECIFIC"), Port: types.StringValue("22,400-500")}, true},
+		{"other port", firewallPolicyEndpointModel{PortMatchingType: types.StringValue("SPECIFIC"), Port: types.StringValue("8443")}, false},
+		{"port group", firewallPolicyEndpointModel{PortMatchingType: types.StringValue("SPECIFIC"), PortGroupID: types.StringValue("g")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := portMatches(tt.ep, 443); got != tt.want {
+				t.Errorf("portMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagementPath_checkNetwork(t *testing.T) {
+	p := testManagementPath()
+	lan := networkResourceModel{
+		ID:      types.StringValue("lan"),
+		Site:    types.StringValue("default"),
+		Name:    types.StringValue("LAN"),
+		Enabled: types.BoolValue(true),
+		Vlan:    types.Int64Value(1),
+	}
+	with := func(f func(m *networkResourceModel)) *networkResourceModel {
+		m := lan
+		f(&m)
+		return &m
+	}
+
+	tests := []struct {
+		name    string
+		state   networkResourceModel
+		plan    *networkResourceModel
+		wantErr bool
+	}{
+		{"unrelated change", lan, with(func(m *networkResourceModel) { m.Name = types.StringValue("Main") }), false},
+		{"destroy", lan, nil, true},
+		{"new VLAN", lan, with(func(m *networkResourceModel) { m.Vlan = types.Int64Value(10) }), true},
+		{"disabled", lan, with(func(m *networkResourceModel) { m.Enabled = types.BoolValue(false) }), true},
+		{"other network", *with(func(m *networkResourceModel) { m.ID = types.StringValue("iot") }), nil, false},
+		{"other site", *with(func(m *networkResourceModel) { m.Site = types.StringValue("branch") }), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := p.checkNetwork(&tt.state, tt.plan)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("checkNetwork() = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+
+	if diags := (*managementPath)(nil).checkNetwork(&lan, nil); diags.HasError() {
+		t.Errorf("checkNetwork() without lockout protection = %v", diags)
+	}
+}
+
+func TestManagementPath_checkDevice(t *testing.T) {
+	ctx := context.Background()
+	p := testManagementPath()
+
+	portOverrides := func(ports ...map[string]attr.Value) types.Set {
+		elemType := types.ObjectType{AttrTypes: portOverrideAttrTypes()}
+		var elems []attr.Value
+		for _, set := range ports {
+			values := nullObjectAttributes(t, portOverrideAttrTypes())
+			for k, v := range set {
+				values[k] = v
+			}
+			elems = append(elems, types.ObjectValueMust(portOverrideAttrTypes(), values))
+		}
+		return types.SetValueMust(elemType, elems)
+	}
+	device := func(mac string, ports types.Set) *deviceResourceModel {
+		return &deviceResourceModel{
+			Site:          types.StringValue("default"),
+			Name:          types.StringValue("switch"),
+			Disabled:      types.BoolValue(false),
+			MgmtNetworkID: types.StringNull(),
+			MAC:           hwtypes.NewMACAddressValue(mac),
+			PortOverride:  ports,
+		}
+	}
+	noPorts := types.SetNull(types.ObjectType{AttrTypes: portOverrideAttrTypes()})
+
+	tests := []struct {
+		name    string
+		state   *deviceResourceModel
+		plan    *deviceResourceModel
+		wantErr bool
+	}{
+		{"unchanged", device("aa:00:00:00:00:02", noPorts), device("AA-00-00-00-00-02", noPorts), false},
+		{
+			"disabled",
+			device("aa:00:00:00:00:02", noPorts),
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:02", noPorts)
+				m.Disabled = types.BoolValue(true)
+				return m
+			}(),
+			true,
+		},
+		{
+			"already disabled",
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:02", noPorts)
+				m.Disabled = types.BoolValue(true)
+				return m
+			}(),
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:02", noPorts)
+				m.Disabled = types.BoolValue(true)
+				return m
+			}(),
+			false,
+		},
+		{
+			"new management network",
+			device("aa:00:00:00:00:01", noPorts),
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:01", noPorts)
+				m.MgmtNetworkID = types.StringValue("iot")
+				return m
+			}(),
+			true,
+		},
+		{
+			"uplink port disabled",
+			nil,
+			device("aa:00:00:00:00:02", portOverrides(map[string]attr.Value{
+				"index":   types.Int64Value(1),
+				"forward": types.StringValue("disabled"),
+			})),
+			true,
+		},
+		{
+			"provider's port moved to another network",
+			device("aa:00:00:00:00:02", noPorts),
+			device("aa:00:00:00:00:02", portOverrides(map[string]attr.Value{
+				"index":                 types.Int64Value(7),
+				"native_networkconf_id": types.StringValue("iot"),
+			})),
+			true,
+		},
+		{
+			"management network excluded",
+			nil,
+			device("aa:00:00:00:00:01", portOverrides(map[string]attr.Value{
+				"index":                    types.Int64Value(5),
+				"excluded_networkconf_ids": types.SetValueMust(types.StringType, []attr.Value{types.StringValue("lan")}),
+			})),
+			true,
+		},
+		{
+			"other port",
+			nil,
+			device("aa:00:00:00:00:02", portOverrides(map[string]attr.Value{
+				"index":                 types.Int64Value(3),
+				"forward":               types.StringValue("disabled"),
+				"native_networkconf_id": types.StringValue("iot"),
+			})),
+			false,
+		},
+		{
+			"destroyed and forgotten",
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:01", noPorts)
+				m.ForgetOnDestroy = types.BoolValue(true)
+				return m
+			}(),
+			nil,
+			true,
+		},
+		{
+			"destroyed without forgetting",
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:01", noPorts)
+				m.ForgetOnDestroy = types.BoolValue(false)
+				return m
+			}(),
+			nil,
+			false,
+		},
+		{
+			"destroyed off the path",
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:99", noPorts)
+				m.ForgetOnDestroy = types.BoolValue(true)
+				return m
+			}(),
+			nil,
+			false,
+		},
+		{
+			"device off the path",
+			nil,
+			func() *deviceResourceModel {
+				m := device("aa:00:00:00:00:99", noPorts)
+				m.Disabled = types.BoolValue(true)
+				return m
+			}(),
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := p.checkDevice(ctx, tt.state, tt.plan)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("checkDevice() = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManagementPath_checkFirewallPolicy(t *testing.T) {
+	ctx := context.Background()
+	p := testManagementPath()
+
+	endpointTypes := firewallPolicyEndpointModel{}.AttributeTypes()
+	endpoint := func(set map[string]attr.Value) types.Object {
+		values := nullObjectAttributes(t, endpointTypes)
+		for k, v := range set {
+			values[k] = v
+		}
+		return types.ObjectValueMust(endpointTypes, values)
+	}
+	stringList := func(s ...string) types.List {
+		var elems []attr.Value
+		for _, v := range s {
+			elems = append(elems, types.StringValue(v))
+		}
+		return types.ListValueMust(types.StringType, elems)
+	}
+	fromLAN := endpoint(map[string]attr.Value{
+		"zone_id":         types.StringValue("zone-internal"),
+		"matching_target": types.StringValue("ANY"),
+	})
+	toGateway := endpoint(map[string]attr.Value{
+		"zone_id":         types.StringValue("zone-gateway"),
+		"matching_target": types.StringValue("ANY"),
+	})
+	policy := func(action string, src, dst types.Object) *firewallPolicyModel {
+		return &firewallPolicyModel{
+			Site:        types.StringValue("default"),
+			Name:        types.StringValue("test"),
+			Action:      types.StringValue(action),
+			Enabled:     types.BoolValue(true),
+			Source:      src,
+			Destination: dst,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		state   *firewallPolicyModel
+		policy  *firewallPolicyModel
+		wantErr bool
+	}{
+		{"block zone to zone", nil, policy("BLOCK", fromLAN, toGateway), true},
+		{"allow", nil, policy("ALLOW", fromLAN, toGateway), false},
+		{
+			"already applied",
+			policy("BLOCK", fromLAN, toGateway),
+			policy("BLOCK", fromLAN, toGateway),
+			false,
+		},
+		{
+			"already applied and renamed",
+			policy("BLOCK", fromLAN, toGateway),
+			func() *firewallPolicyModel {
+				m := policy("BLOCK", fromLAN, toGateway)
+				m.Name = types.StringValue("renamed")
+				return m
+			}(),
+			false,
+		},
+		{
+			"changed from allow",
+			policy("ALLOW", fromLAN, toGateway),
+			policy("BLOCK", fromLAN, toGateway),
+			true,
+		},
+		{
+			"enabled",
+			func() *firewallPolicyModel {
+				m := policy("BLOCK", fromLAN, toGateway)
+				m.Enabled = types.BoolValue(false)
+				return m
+			}(),
+			policy("BLOCK", fromLAN, toGateway),
+			true,
+		},
+		{
+			"disabled",
+			nil,
+			func() *firewallPolicyModel {
+				m := policy("BLOCK", fromLAN, toGateway)
+				m.Enabled = types.BoolValue(false)
+				return m
+			}(),
+			false,
+		},
+		{
+			"reject the provider's network",
+			nil,
+			policy("REJECT", endpoint(map[string]attr.Value{
+				"zone_id":         types.StringValue("zone-internal"),
+				"matching_target": types.StringValue("NETWORK"),
+				"network_ids":     stringList("lan"),
+			}), toGateway),
+			true,
+		},
+		{
+			"block another network",
+			nil,
+			policy("BLOCK", endpoint(map[string]attr.Value{
+				"zone_id":         types.StringValue("zone-internal"),
+				"matching_target": types.StringValue("NETWORK"),
+				"network_ids":     stringList("iot"),
+			}), toGateway),
+			false,
+		},
+		{
+			"block the provider's address",
+			nil,
+			policy("BLOCK", endpoint(map[string]attr.Value{
+				"zone_id":         types.StringValue("zone-internal"),
+				"matching_target": types.StringValue("IP"),
+				"ips":             stringList("192.168.1.0/25"),
+			}), toGateway),
+			true,
+		},
+		{
+			"block another port",
+			nil,
+			policy("BLOCK", fromLAN, endpoint(map[string]attr.Value{
+				"zone_id":            types.StringValue("zone-gateway"),
+				"matching_target":    types.StringValue("ANY"),
+				"port_matching_type": types.StringValue("SPECIFIC"),
+				"port":               types.StringValue("22"),
+			})),
+			false,
+		},
+		{"block another zone", nil, policy("BLOCK", endpoint(map[string]attr.Value{
+			"zone_id":         types.StringValue("zone-iot"),
+			"matching_target": types.StringValue("ANY"),
+		}), toGateway), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := p.checkFirewallPolicy(ctx, tt.state, tt.policy)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("checkFirewallPolicy() = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(diags[0].Detail(), "192.168.1.50") {
+				t.Errorf("detail = %q, want it to name the provider's address", diags[0].Detail())
+			}
+		})
+	}
+}
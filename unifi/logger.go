@@ -12,6 +12,16 @@
 	subsystem = "api-client"
 )
 
+// maskedFieldKeys are the fields whose values are never logged. The audit log
+// applies the same rule to request bodies.
+var maskedFieldKeys = []string{
+	"unifi_api_key",
+	"unifi_password",
+	"api_key",
+	"password",
+	"authorization",
+}
+
 type UnifiLogger struct {
 	ctx context.Context
 	mu  sync.Mutex
@@ -19,15 +29,7 @@ type UnifiLogger struct {
 
 func NewLogger(ctx context.Context) *UnifiLogger {
 	ctx = tflog.NewSubsystem(ctx, subsystem)
-	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(
-		ctx,
-		subsystem,
-		"unifi_api_key",
-		"unifi_password",
-		"api_key",
-		"password",
-		"authorization",
-	)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystem, maskedFieldKeys...)
 
 	return &UnifiLogger{ctx: ctx}
 }
@@ -0,0 +1,35 @@
+package unifi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// normalizeMAC converts a 48-bit MAC address in any common notation into the
+// lowercase, colon-separated form the controller stores and returns. It accepts
+// colon (`AA:BB:CC:DD:EE:FF`), hyphen (`AA-BB-CC-DD-EE-FF`), Cisco dot
+// (`aabb.ccdd.eeff`) and bare hex (`aabbccddeeff`) notation.
+//
+// Unlike cleanMAC, which only rewrites separators, it validates the address.
+func normalizeMAC(mac string) (string, error) {
+	mac = strings.TrimSpace(mac)
+
+	if len(mac) == 12 {
+		raw, err := hex.DecodeString(mac)
+		if err == nil {
+			return net.HardwareAddr(raw).String(), nil
+		}
+	}
+
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return "", fmt.Errorf("invalid MAC address %q", mac)
+	}
+	if len(hw) != 6 {
+		return "", fmt.Errorf("invalid MAC address %q: only 48-bit addresses are supported", mac)
+	}
+
+	return hw.String(), nil
+}
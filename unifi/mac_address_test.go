@@ -0,0 +1,35 @@
+package unifi
+
+import "testing"
+
+func Test_normalizeMAC(t *testing.T) {
+	tests := []struct {
+		name    string
+		mac     string
+		want    string
+		wantErr bool
+	}{
+		{"lowercase colons", "aa:bb:cc:dd:ee:ff", "aa:bb:cc:dd:ee:ff", false},
+		{"uppercase colons", "AA:BB:CC:DD:EE:FF", "aa:bb:cc:dd:ee:ff", false},
+		{"hyphens", "AA-BB-CC-DD-EE-FF", "aa:bb:cc:dd:ee:ff", false},
+		{"cisco dots", "AABB.CCDD.EEFF", "aa:bb:cc:dd:ee:ff", false},
+		{"bare hex", "AABBCCDDEEFF", "aa:bb:cc:dd:ee:ff", false},
+		{"surrounding whitespace", " aa:bb:cc:dd:ee:ff\n", "aa:bb:cc:dd:ee:ff", false},
+		{"empty", "", "", true},
+		{"too short", "aa:bb:cc:dd:ee", "", true},
+		{"not hex", "gg:bb:cc:dd:ee:ff", "", true},
+		{"bare hex not hex", "gghhiijjkkll", "", true},
+		{"EUI-64", "aa:bb:cc:dd:ee:ff:00:11", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeMAC(tt.mac)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeMAC(%q) error = %v, wantErr %v", tt.mac, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeMAC(%q) = %q, want %q", tt.mac, got, tt.want)
+			}
+		})
+	}
+}
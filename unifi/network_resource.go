@@ -898,6 +898,20 @@ func (r *networkResource) ModifyPlan(
 	req resource.ModifyPlanRequest,
 	resp *resource.ModifyPlanResponse,
 ) {
+	if r.client != nil && r.client.lockout != nil && !req.State.Raw.IsNull() {
+		var state networkResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		var plan *networkResourceModel
+		if !req.Plan.Raw.IsNull() {
+			plan = &networkResourceModel{}
+			resp.Diagnostics.Append(req.Plan.Get(ctx, plan)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(r.client.lockout.checkNetwork(&state, plan)...)
+	}
+
 	if req.Plan.Raw.IsNull() {
 		return // resource is being destroyed
 	}
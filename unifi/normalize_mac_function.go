@@ -0,0 +1,66 @@
+package unifi
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &normalizeMACFunction{}
+
+// NewNormalizeMACFunction returns a new instance of the normalize_mac function.
+func NewNormalizeMACFunction() function.Function {
+	return &normalizeMACFunction{}
+}
+
+type normalizeMACFunction struct{}
+
+func (f *normalizeMACFunction) Metadata(
+	ctx context.Context,
+	req function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "normalize_mac"
+}
+
+func (f *normalizeMACFunction) Definition(
+	ctx context.Context,
+	req function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Normalize a MAC address to the controller's format",
+		MarkdownDescription: "Converts a 48-bit MAC address into the lowercase, colon-separated form " +
+			"the UniFi controller stores (`aa:bb:cc:dd:ee:ff`). Colon, hyphen, Cisco dot " +
+			"(`aabb.ccdd.eeff`) and bare hex (`aabbccddeeff`) notations are accepted. " +
+			"Invalid addresses are an error.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "mac",
+				MarkdownDescription: "The MAC address to normalize.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *normalizeMACFunction) Run(
+	ctx context.Context,
+	req function.RunRequest,
+	resp *function.RunResponse,
+) {
+	var mac string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &mac))
+	if resp.Error != nil {
+		return
+	}
+
+	normalized, err := normalizeMAC(mac)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, normalized))
+}
@@ -0,0 +1,59 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewNormalizeMACFunction(t *testing.T) {
+	if got := NewNormalizeMACFunction(); got == nil {
+		t.Fatal("NewNormalizeMACFunction() returned nil")
+	}
+}
+
+func Test_normalizeMACFunction_Metadata(t *testing.T) {
+	f := &normalizeMACFunction{}
+	resp := &function.MetadataResponse{}
+	f.Metadata(context.Background(), function.MetadataRequest{}, resp)
+	if resp.Name != "normalize_mac" {
+		t.Errorf("Name = %q, want %q", resp.Name, "normalize_mac")
+	}
+}
+
+func Test_normalizeMACFunction_Run(t *testing.T) {
+	tests := []struct {
+		name    string
+		mac     string
+		want    string
+		wantErr bool
+	}{
+		{"hyphens", "AA-BB-CC-DD-EE-FF", "aa:bb:cc:dd:ee:ff", false},
+		{"cisco dots", "aabb.ccdd.eeff", "aa:bb:cc:dd:ee:ff", false},
+		{"invalid", "not-a-mac", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &normalizeMACFunction{}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+			f.Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tt.mac)}),
+			}, resp)
+
+			if (resp.Error != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", resp.Error, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("Run() = %s, want %q", got, tt.want)
+			}
+		})
+	}
+}
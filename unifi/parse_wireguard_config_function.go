@@ -0,0 +1,122 @@
+package unifi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util"
+)
+
+var _ function.Function = &parseWireGuardConfigFunction{}
+
+// parseWireGuardConfigAttrTypes is the object type returned by
+// parse_wireguard_config.
+var parseWireGuardConfigAttrTypes = map[string]attr.Type{
+	"private_key":   types.StringType,
+	"address":       types.StringType,
+	"dns":           types.ListType{ElemType: types.StringType},
+	"public_key":    types.StringType,
+	"endpoint":      types.StringType,
+	"endpoint_ip":   types.StringType,
+	"endpoint_port": types.Int64Type,
+	"allowed_ips":   types.ListType{ElemType: types.StringType},
+	"preshared_key": types.StringType,
+}
+
+// NewParseWireGuardConfigFunction returns a new instance of the
+// parse_wireguard_config function.
+func NewParseWireGuardConfigFunction() function.Function {
+	return &parseWireGuardConfigFunction{}
+}
+
+type parseWireGuardConfigFunction struct{}
+
+func (f *parseWireGuardConfigFunction) Metadata(
+	ctx context.Context,
+	req function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "parse_wireguard_config"
+}
+
+func (f *parseWireGuardConfigFunction) Definition(
+	ctx context.Context,
+	req function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Parse a WireGuard configuration file",
+		MarkdownDescription: "Parses the contents of a WireGuard `.conf` file into an object, using the " +
+			"same parser as `unifi_vpn_client` `wireguard.config`. The `[Peer]` section must contain " +
+			"`PublicKey` and `Endpoint`. Keys that are not present in the file are `null`.\n\n" +
+			"The returned object has the attributes `private_key`, `address`, `dns` (list), " +
+			"`public_key`, `endpoint`, `endpoint_ip`, `endpoint_port`, `allowed_ips` (list) and " +
+			"`preshared_key`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "content",
+				MarkdownDescription: "The contents of the WireGuard configuration file.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: parseWireGuardConfigAttrTypes,
+		},
+	}
+}
+
+func (f *parseWireGuardConfigFunction) Run(
+	ctx context.Context,
+	req function.RunRequest,
+	resp *function.RunResponse,
+) {
+	var content string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &content))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := parseWireGuardConfig(content)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	dns, diags := types.ListValueFrom(ctx, types.StringType, parsed.DNS)
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+
+	var allowedIPs []string
+	for _, cidr := range strings.Split(parsed.AllowedIPs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			allowedIPs = append(allowedIPs, cidr)
+		}
+	}
+	allowed, diags := types.ListValueFrom(ctx, types.StringType, allowedIPs)
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+
+	endpointPort := types.Int64Null()
+	if parsed.EndpointPort != 0 {
+		endpointPort = types.Int64Value(parsed.EndpointPort)
+	}
+
+	result, diags := types.ObjectValue(parseWireGuardConfigAttrTypes, map[string]attr.Value{
+		"private_key":   util.StringValueOrNull(parsed.PrivateKey),
+		"address":       util.StringValueOrNull(parsed.Address),
+		"dns":           dns,
+		"public_key":    types.StringValue(parsed.PublicKey),
+		"endpoint":      types.StringValue(parsed.Endpoint),
+		"endpoint_ip":   util.StringValueOrNull(parsed.EndpointIP),
+		"endpoint_port": endpointPort,
+		"allowed_ips":   allowed,
+		"preshared_key": util.StringValueOrNull(parsed.PresharedKey),
+	})
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,107 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewParseWireGuardConfigFunction(t *testing.T) {
+	if got := NewParseWireGuardConfigFunction(); got == nil {
+		t.Fatal("NewParseWireGuardConfigFunction() returned nil")
+	}
+}
+
+func Test_parseWireGuardConfigFunction_Metadata(t *testing.T) {
+	f := &parseWireGuardConfigFunction{}
+	resp := &function.MetadataResponse{}
+	f.Metadata(context.Background(), function.MetadataRequest{}, resp)
+	if resp.Name != "parse_wireguard_config" {
+		t.Errorf("Name = %q, want %q", resp.Name, "parse_wireguard_config")
+	}
+}
+
+func Test_parseWireGuardConfigFunction_Run(t *testing.T) {
+	ctx := context.Background()
+	f := &parseWireGuardConfigFunction{}
+
+	t.Run("full config", func(t *testing.T) {
+		resp := &function.RunResponse{
+			Result: function.NewResultData(types.ObjectUnknown(parseWireGuardConfigAttrTypes)),
+		}
+		f.Run(ctx, function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(`
+[Interface]
+PrivateKey = cGFyc2UtcHJpdmF0ZQ==
+Address = 10.10.0.2/32
+DNS = 1.1.1.1, 9.9.9.9
+
+[Peer]
+PublicKey = cGFyc2UtcHVibGlj
+Endpoint = vpn.example.com:51820
+AllowedIPs = 0.0.0.0/0, ::/0
+`)}),
+		}, resp)
+		if resp.Error != nil {
+			t.Fatalf("Run() error = %v", resp.Error)
+		}
+
+		got, ok := resp.Result.Value().(types.Object)
+		if !ok {
+			t.Fatalf("Run() returned %T, want types.Object", resp.Result.Value())
+		}
+		attrs := got.Attributes()
+		wantStrings := map[string]string{
+			"private_key": "cGFyc2UtcHJpdmF0ZQ==",
+			"address":     "10.10.0.2/32",
+			"public_key":  "cGFyc2UtcHVibGlj",
+			"endpoint":    "vpn.example.com:51820",
+			"endpoint_ip": "vpn.example.com",
+		}
+		for name, want := range wantStrings {
+			if !attrs[name].Equal(types.StringValue(want)) {
+				t.Errorf("%s = %s, want %q", name, attrs[name], want)
+			}
+		}
+		if !attrs["endpoint_port"].Equal(types.Int64Value(51820)) {
+			t.Errorf("endpoint_port = %s, want 51820", attrs["endpoint_port"])
+		}
+		if !attrs["preshared_key"].IsNull() {
+			t.Errorf("preshared_key = %s, want null", attrs["preshared_key"])
+		}
+		wantAllowed := types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("0.0.0.0/0"),
+			types.StringValue("::/0"),
+		})
+		if !attrs["allowed_ips"].Equal(wantAllowed) {
+			t.Errorf("allowed_ips = %s, want %s", attrs["allowed_ips"], wantAllowed)
+		}
+		wantDNS := types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("1.1.1.1"),
+			types.StringValue("9.9.9.9"),
+		})
+		if !attrs["dns"].Equal(wantDNS) {
+			t.Errorf("dns = %s, want %s", attrs["dns"], wantDNS)
+		}
+	})
+
+	t.Run("missing peer", func(t *testing.T) {
+		resp := &function.RunResponse{
+			Result: function.NewResultData(types.ObjectUnknown(parseWireGuardConfigAttrTypes)),
+		}
+		f.Run(ctx, function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{
+				types.StringValue("[Interface]\nPrivateKey = abc\n"),
+			}),
+		}, resp)
+		if resp.Error == nil {
+			t.Fatal("Run() expected an error for a config without a peer")
+		}
+		if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 {
+			t.Errorf("error should reference argument 0, got %v", resp.Error.FunctionArgument)
+		}
+	})
+}
@@ -101,6 +101,11 @@ func (a *portAction) Invoke(
 	req action.InvokeRequest,
 	resp *action.InvokeResponse,
 ) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_port")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var config portActionModel
 
 	// Read the action configuration
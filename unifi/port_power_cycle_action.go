@@ -0,0 +1,355 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/hwtypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util/retry"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action              = &portPowerCycleAction{}
+	_ action.ActionWithConfigure = &portPowerCycleAction{}
+)
+
+// NewPortPowerCycleAction returns a new instance of the port power cycle
+// action.
+func NewPortPowerCycleAction() action.Action {
+	return &portPowerCycleAction{}
+}
+
+type portPowerCycleAction struct {
+	client *Client
+}
+
+// portPowerCycleActionModel describes the action request data model.
+type portPowerCycleActionModel struct {
+	DeviceMAC       hwtypes.MACAddress `tfsdk:"device_mac"`
+	PortNumbers     types.List         `tfsdk:"port_numbers"`
+	WaitForNeighbor types.Bool         `tfsdk:"wait_for_neighbor"`
+	Timeouts        timeouts.Value     `tfsdk:"timeouts"`
+}
+
+func (a *portPowerCycleAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_port_power_cycle"
+}
+
+func (a *portPowerCycleAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Power-cycles PoE ports on a UniFi switch, for example to recover a hung camera " +
+			"or access point. Unlike setting `poe_mode` with the `unifi_port` action, the port's " +
+			"configuration is never changed, so an interrupted apply cannot leave the port switched " +
+			"off. " + directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"device_mac": schema.StringAttribute{
+				MarkdownDescription: "MAC address of the switch.",
+				CustomType:          hwtypes.MACAddressType{},
+				Required:            true,
+			},
+			"port_numbers": schema.ListAttribute{
+				MarkdownDescription: "Port numbers (indexes) to power-cycle. Every port must supply PoE.",
+				ElementType:         types.Int64Type,
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueInt64sAre(int64validator.AtLeast(1)),
+				},
+			},
+			"wait_for_neighbor": schema.BoolAttribute{
+				MarkdownDescription: "Wait until an LLDP neighbor or a wired client reappears on every port " +
+					"after the power cycle. Defaults to `false`.",
+				Optional: true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *portPowerCycleAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *portPowerCycleAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_port_power_cycle")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config portPowerCycleActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	mac, err := normalizeMAC(config.DeviceMAC.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Device MAC Address", err.Error())
+		return
+	}
+	var ports []int64
+	resp.Diagnostics.Append(config.PortNumbers.ElementsAs(ctx, &ports, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	site := a.client.Site
+
+	device, err := a.client.getDeviceStatus(ctx, site, mac)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Finding Device",
+			fmt.Sprintf("Could not find device with MAC address %s: %s", mac, err.Error()),
+		)
+		return
+	}
+	if err := checkPowerCyclePorts(device, ports); err != nil {
+		resp.Diagnostics.AddError("Invalid Port Number", err.Error())
+		return
+	}
+
+	sentAt := time.Now()
+	for _, port := range ports {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Power-cycling port %d on %s", port, device.displayName()),
+		})
+		err := a.client.executeCommand(ctx, site, "devmgr", map[string]any{
+			"cmd":      "power-cycle",
+			"mac":      mac,
+			"port_idx": port,
+		}, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Power-Cycling Port",
+				fmt.Sprintf("Could not power-cycle port %d on device %s: %s", port, mac, err.Error()),
+			)
+			return
+		}
+	}
+
+	if !config.WaitForNeighbor.ValueBool() {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Waiting for neighbors to reappear on %s", device.displayName()),
+	})
+	neighbors, err := a.client.waitForPortNeighbors(ctx, site, mac, ports, sentAt)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Waiting for Port Neighbor",
+			fmt.Sprintf("The ports on device %s were power-cycled but not all came back: %s", mac, err.Error()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Ports power-cycled", map[string]any{
+		"mac":       mac,
+		"neighbors": neighbors,
+		"duration":  time.Since(sentAt).String(),
+	})
+}
+
+// checkPowerCyclePorts returns an error if one of ports is missing from the
+// device or does not supply PoE.
+func checkPowerCyclePorts(d *deviceStatus, ports []int64) error {
+	for _, port := range ports {
+		i := slices.IndexFunc(d.PortTable, func(p devicePort) bool { return p.PortIdx == port })
+		switch {
+		case i < 0:
+			return fmt.Errorf("device %s has no port %d", d.displayName(), port)
+		case !d.PortTable[i].PortPoe:
+			return fmt.Errorf("port %d on device %s does not supply PoE", port, d.displayName())
+		}
+	}
+	return nil
+}
+
+// portClient is the part of a `stat/sta` entry that places a wired client on
+// a switch port.
+type portClient struct {
+	MAC      string `json:"mac"`
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	IsWired  bool   `json:"is_wired"`
+	SwMAC    string `json:"sw_mac"`
+	SwPort   int64  `json:"sw_port"`
+	Uptime   int64  `json:"uptime"`
+}
+
+func (c *portClient) displayName() string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.Hostname != "":
+		return c.Hostname
+	}
+	return c.MAC
+}
+
+// portNeighbor describes what is connected to port on device d, or returns
+// an empty string if the port is down or nothing is seen on it. reconnected
+// reports whether a client on the port has been connected for less than the
+// time since sentAt.
+func portNeighbor(d *deviceStatus, clients []portClient, port int64, sentAt time.Time) (neighbor string, reconnected bool) {
+	if i := slices.IndexFunc(d.PortTable, func(p devicePort) bool { return p.PortIdx == port }); i >= 0 && !d.PortTable[i].Up {
+		return "", false
+	}
+
+	for _, c := range clients {
+		if !c.IsWired || !strings.EqualFold(c.SwMAC, d.MAC) || c.SwPort != port {
+			continue
+		}
+		uptime := time.Duration(c.Uptime) * time.Second
+		if c.Uptime > 0 && uptime < time.Since(sentAt) {
+			return c.displayName(), true
+		}
+		neighbor = c.displayName()
+	}
+	if neighbor != "" {
+		return neighbor, false
+	}
+
+	for _, n := range d.LLDPTable {
+		if n.LocalPortIdx == port {
+			return n.displayName(), false
+		}
+	}
+	return "", false
+}
+
+// waitForPortNeighbors waits until something is connected again to each of
+// ports on a switch whose ports were power-cycled at sentAt, and returns what
+// was found on each port. A port counts as back once it has an LLDP neighbor
+// or a wired client after having been seen empty, or once a client on it
+// reports a connection time shorter than the time since sentAt.
+func (c *Client) waitForPortNeighbors(
+	ctx context.Context,
+	site, mac string,
+	ports []int64,
+	sentAt time.Time,
+) (map[int64]string, error) {
+	var (
+		// found is read after a timeout, while a refresh may still be
+		// running.
+		mu    sync.Mutex
+		found = map[int64]string{}
+		empty = map[int64]bool{}
+	)
+
+	wait := retry.StateChangeConf{
+		Pending: []string{"waiting"},
+		Target:  []string{"connected"},
+		Refresh: func() (any, string, error) {
+			d, err := c.getDeviceStatus(ctx, site, mac)
+			if err != nil {
+				return nil, "", err
+			}
+			var clients []portClient
+			err = c.controllerRequest(
+				withFreshReads(ctx),
+				http.MethodGet,
+				"/api/s/"+url.PathEscape(site)+"/stat/sta",
+				nil,
+				&clients,
+			)
+			if err != nil {
+				return nil, "", err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, port := range ports {
+				if _, ok := found[port]; ok {
+					continue
+				}
+				neighbor, reconnected := portNeighbor(d, clients, port, sentAt)
+				switch {
+				case neighbor == "":
+					empty[port] = true
+				case empty[port] || reconnected:
+					found[port] = neighbor
+				}
+			}
+			if len(found) < len(ports) {
+				return d, "waiting", nil
+			}
+			return d, "connected", nil
+		},
+		Timeout:      timeUntilDeadline(ctx),
+		PollInterval: devicePollInterval,
+	}
+
+	_, err := wait.WaitForStateContext(ctx)
+	mu.Lock()
+	defer mu.Unlock()
+	var timeout *retry.TimeoutError
+	if errors.As(err, &timeout) || errors.Is(err, context.DeadlineExceeded) {
+		var missing []string
+		for _, port := range ports {
+			if _, ok := found[port]; !ok {
+				missing = append(missing, fmt.Sprint(port))
+			}
+		}
+		err = fmt.Errorf("%w; nothing reappeared on port %s", err, strings.Join(missing, ", "))
+	}
+	return maps.Clone(found), err
+}
@@ -0,0 +1,216 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util/retry"
+)
+
+func TestNewPortPowerCycleAction(t *testing.T) {
+	got := NewPortPowerCycleAction()
+	if got == nil {
+		t.Fatal("NewPortPowerCycleAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_portPowerCycleAction_Metadata(t *testing.T) {
+	a := &portPowerCycleAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_port_power_cycle" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_port_power_cycle")
+	}
+}
+
+func Test_portPowerCycleAction_Schema(t *testing.T) {
+	a := &portPowerCycleAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"device_mac", "port_numbers", "wait_for_neighbor", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_portPowerCycleAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &portPowerCycleAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_checkPowerCyclePorts(t *testing.T) {
+	d := &deviceStatus{
+		Name: "switch",
+		PortTable: []devicePort{
+			{PortIdx: 1, PortPoe: true},
+			{PortIdx: 2, PortPoe: false},
+		},
+	}
+	tests := []struct {
+		ports   []int64
+		wantErr string
+	}{
+		{[]int64{1}, ""},
+		{[]int64{1, 2}, "does not supply PoE"},
+		{[]int64{3}, "has no port 3"},
+	}
+	for _, tt := range tests {
+		err := checkPowerCyclePorts(d, tt.ports)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("checkPowerCyclePorts(%v) = %v, want nil", tt.ports, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("checkPowerCyclePorts(%v) = %v, want %q", tt.ports, err, tt.wantErr)
+		}
+	}
+}
+
+// portSnapshot is what the controller reports for a switch and its clients
+// at one poll.
+type portSnapshot struct {
+	device  deviceStatus
+	clients []portClient
+}
+
+// portSnapshotSequence serves `stat/device/{mac}` and `stat/sta` from a list
+// of snapshots, advancing on each device poll and repeating the last one.
+func portSnapshotSequence(t *testing.T, snapshots ...portSnapshot) *Client {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var data any
+		switch r.URL.Path {
+		case "/api/s/default/stat/device/aa:bb:cc:dd:ee:ff":
+			data = []deviceStatus{snapshots[min(polls, len(snapshots)-1)].device}
+		case "/api/s/default/stat/sta":
+			data = snapshots[min(polls, len(snapshots)-1)].clients
+			polls++
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"rc": "ok"}, "data": data})
+	}))
+	t.Cleanup(srv.Close)
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+}
+
+func TestClient_waitForPortNeighbors(t *testing.T) {
+	interval := devicePollInterval
+	devicePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { devicePollInterval = interval })
+
+	const mac = "aa:bb:cc:dd:ee:ff"
+	sw := func(up bool, lldp ...lldpNeighbor) deviceStatus {
+		return deviceStatus{
+			MAC:       mac,
+			PortTable: []devicePort{{PortIdx: 3, Up: up, PortPoe: true}, {PortIdx: 4, Up: up, PortPoe: true}},
+			LLDPTable: lldp,
+		}
+	}
+	camera := portClient{MAC: "11:11:11:11:11:11", Name: "camera", IsWired: true, SwMAC: mac, SwPort: 3, Uptime: 86400}
+	ap := lldpNeighbor{LocalPortIdx: 4, ChassisID: "22:22:22:22:22:22", PortID: "eth0"}
+
+	t.Run("seen down", func(t *testing.T) {
+		c := portSnapshotSequence(t,
+			portSnapshot{device: sw(true, ap), clients: []portClient{camera}},
+			portSnapshot{device: sw(false)},
+			portSnapshot{device: sw(true, ap), clients: []portClient{camera}},
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		got, err := c.waitForPortNeighbors(ctx, "default", mac, []int64{3, 4}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[3] != "camera" || got[4] != "22:22:22:22:22:22 port eth0" {
+			t.Errorf("neighbors = %v", got)
+		}
+	})
+
+	t.Run("client reconnected", func(t *testing.T) {
+		reconnected := camera
+		reconnected.Uptime = 1
+		c := portSnapshotSequence(t,
+			portSnapshot{device: sw(true), clients: []portClient{camera}},
+			portSnapshot{device: sw(true), clients: []portClient{reconnected}},
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		got, err := c.waitForPortNeighbors(ctx, "default", mac, []int64{3}, time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[3] != "camera" {
+			t.Errorf("neighbors = %v", got)
+		}
+	})
+
+	t.Run("nothing returns", func(t *testing.T) {
+		c := portSnapshotSequence(t,
+			portSnapshot{device: sw(true, ap), clients: []portClient{camera}},
+			portSnapshot{device: sw(false)},
+			portSnapshot{device: sw(true), clients: []portClient{camera}},
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		got, err := c.waitForPortNeighbors(ctx, "default", mac, []int64{3, 4}, time.Now())
+		var timeout *retry.TimeoutError
+		if !errors.As(err, &timeout) && !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error = %v, want a timeout", err)
+		}
+		if !strings.Contains(err.Error(), "nothing reappeared on port 4") {
+			t.Errorf("error = %v, want it to name port 4", err)
+		}
+		if got[3] != "camera" {
+			t.Errorf("neighbors = %v, want port 3 found", got)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package unifi
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// stagePrivateFile writes data to a new file next to dest, readable only by
+// the current user, creating parent directories as needed, and returns the
+// new file's name. dest is untouched until the file is renamed to it.
+func stagePrivateFile(dest string, data []byte) (string, error) {
+	name, _, err := stagePrivateFileFrom(dest, bytes.NewReader(data))
+	return name, err
+}
+
+// stagePrivateFileFrom is stagePrivateFile for data streamed from r. It also
+// returns the number of bytes written.
+func stagePrivateFileFrom(dest string, r io.Reader) (string, int64, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", 0, err
+	}
+	// CreateTemp creates the file with mode 0600.
+	f, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := io.Copy(f, r)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", 0, err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", 0, err
+	}
+	return f.Name(), n, nil
+}
+
+// writePrivateFile replaces dest with data, readable only by the current user,
+// creating parent directories as needed. A failed write leaves dest as it was.
+func writePrivateFile(dest string, data []byte) error {
+	tmp, err := stagePrivateFile(dest, data)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
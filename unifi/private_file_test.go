@@ -0,0 +1,69 @@
+package unifi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_stagePrivateFile(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "secrets", "passphrase.txt")
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dest, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp, err := stagePrivateFile(dest, []byte("new\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(tmp) != filepath.Dir(dest) {
+		t.Errorf("staged file %s is not next to %s", tmp, dest)
+	}
+	if got, err := os.ReadFile(dest); err != nil || string(got) != "old\n" {
+		t.Errorf("dest = %q, %v before rename, want it untouched", got, err)
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("staged file mode = %v, want 0600", perm)
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(dest); err != nil || string(got) != "new\n" {
+		t.Errorf("dest = %q, %v after rename, want %q", got, err, "new\n")
+	}
+}
+
+func Test_writePrivateFile(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "passphrase.txt")
+	if err := os.WriteFile(dest, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writePrivateFile(dest, []byte("new\n")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %v, want 0600", perm)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only %s", len(entries), dest)
+	}
+}
@@ -2,18 +2,31 @@
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	ui "github.com/ubiquiti-community/go-unifi/unifi"
 )
@@ -22,6 +35,7 @@
 var (
 	_ provider.Provider                       = &unifiProvider{}
 	_ provider.ProviderWithEphemeralResources = &unifiProvider{}
+	_ provider.ProviderWithFunctions          = &unifiProvider{}
 	_ provider.ProviderWithListResources      = &unifiProvider{}
 )
 
@@ -36,6 +50,31 @@ type unifiProviderModel struct {
 	AllowInsecure  types.Bool   `tfsdk:"allow_insecure"`
 	CloudConnector types.Bool   `tfsdk:"cloud_connector"`
 	HardwareID     types.String `tfsdk:"hardware_id"`
+
+	ReadOnly          types.Bool   `tfsdk:"read_only"`
+	AuditLogPath      types.String `tfsdk:"audit_log_path"`
+	LockoutProtection types.Bool   `tfsdk:"lockout_protection"`
+
+	Profile         types.String `tfsdk:"profile"`
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+
+	CACertPEM        types.String `tfsdk:"ca_cert_pem"`
+	CACertFile       types.String `tfsdk:"ca_cert_file"`
+	PinnedCertSHA256 types.List   `tfsdk:"pinned_cert_sha256"`
+	ClientCertPEM    types.String `tfsdk:"client_cert_pem"`
+	ClientCertFile   types.String `tfsdk:"client_cert_file"`
+	ClientKeyPEM     types.String `tfsdk:"client_key_pem"`
+	ClientKeyFile    types.String `tfsdk:"client_key_file"`
+
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	Retry                 types.Object  `tfsdk:"retry"`
+}
+
+type unifiProviderRetryModel struct {
+	MaxAttempts types.Int64          `tfsdk:"max_attempts"`
+	Backoff     timetypes.GoDuration `tfsdk:"backoff"`
+	StatusCodes types.List           `tfsdk:"status_codes"`
 }
 
 // Client wraps the UniFi client with site information.
@@ -50,6 +89,25 @@ type Client struct {
 	// so they stop each creating a duplicate group with the same name (#389).
 	groupCacheMu sync.Mutex
 	groupCache   map[string]map[string]string // site -> (name -> id)
+
+	// controller is detected once in Configure; resources that need a newer
+	// Network Application check it at plan time. controllerErr is why the
+	// detection failed, reported by controllerRequest when the API address is
+	// unknown.
+	controller    controllerInfo
+	controllerErr error
+
+	// readOnly refuses changes to the controller; see checkWritable.
+	readOnly bool
+
+	// httpClient and apiKey send the requests go-unifi has no method for; see
+	// controllerRequest.
+	httpClient *http.Client
+	apiKey     string
+
+	// lockout is the provider's own path to the controller, set when
+	// lockout_protection is enabled. Plans that would cut it are refused.
+	lockout *managementPath
 }
 
 // GetSiteName returns the site name for this client.
@@ -127,6 +185,158 @@ func (p *unifiProvider) Schema(
 					"`UNIFI_HARDWARE_ID` environment variable. Only used when `cloud_connector` is enabled.",
 				Optional: true,
 			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Refuse every change to the controller. Plan, refresh, data sources, list " +
+					"resources and imports work normally, while creating, updating or deleting a resource " +
+					"and invoking an action fail before any request that could change the controller is " +
+					"sent. Intended for auditing and dashboard workspaces pointed at production consoles. " +
+					"Can be specified with the `UNIFI_READ_ONLY` environment variable. Default: `false`.",
+				Optional: true,
+			},
+			"audit_log_path": schema.StringAttribute{
+				MarkdownDescription: "Path of a file to append a JSON line to for every `POST`, `PUT` or `DELETE` " +
+					"sent to the controller, with the timestamp, site, resource type, object ID, method, " +
+					"endpoint, response status and the request body. Passwords, API keys, authorization " +
+					"values and the controller's `x_` secret fields are masked as in the provider logs. Each " +
+					"line includes the SHA-256 of the previous one in `prev_sha256`, so edits and deletions " +
+					"can be detected. Can be specified with the `UNIFI_AUDIT_LOG_PATH` environment variable.",
+				Optional: true,
+			},
+			"lockout_protection": schema.BoolAttribute{
+				MarkdownDescription: "Refuse plans that would cut the provider's own connection to the controller. " +
+					"When enabled, the provider works out which networks the host running Terraform and the " +
+					"controller are on, and which devices and switch ports connect them to the gateway, and " +
+					"fails the plan for changes that would block, re-VLAN or disable that path: destroying or " +
+					"re-VLANing those networks, disabling those devices or destroying them with " +
+					"`forget_on_destroy`, disabling those ports or changing their native or management network, " +
+					"and creating or changing `BLOCK` or `REJECT` firewall policies to match the connection. " +
+					"Policies and devices that are already applied unchanged are not refused. " +
+					"Can be specified with the `UNIFI_LOCKOUT_PROTECTION` environment variable. Default: `false`.",
+				Optional: true,
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Name of a profile in the credentials file to read `api_url`, `api_key` or " +
+					"`username` and `password`, `site`, `allow_insecure` and the TLS settings from. Arguments " +
+					"and `UNIFI_*` environment variables take precedence over the profile. The profile's " +
+					"credentials are only used when no `api_key`, `username` or `password` is configured. " +
+					"Can be specified with the `UNIFI_PROFILE` environment variable.",
+				Optional: true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the TOML credentials file that `profile` is read from. Each profile " +
+					"is a table named after it, with keys named like the provider arguments. Relative " +
+					"certificate and key paths are resolved against the directory of the file. Can be " +
+					"specified with the `UNIFI_CREDENTIALS_FILE` environment variable. Default: " +
+					"`$XDG_CONFIG_HOME/unifi/credentials.toml`, or `~/.config/unifi/credentials.toml`.",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificates to trust in addition to the system roots, for " +
+					"controllers with certificates from an internal CA. Can be specified with the " +
+					"`UNIFI_CA_CERT_PEM` environment variable. Conflicts with `ca_cert_file`.",
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file of PEM-encoded CA certificates, as an alternative to " +
+					"`ca_cert_pem`. Can be specified with the `UNIFI_CA_CERT_FILE` environment variable.",
+				Optional: true,
+			},
+			"pinned_cert_sha256": schema.ListAttribute{
+				MarkdownDescription: "SHA-256 fingerprints of the controller certificates to accept, in hex with " +
+					"or without colons (the output of `openssl x509 -noout -fingerprint -sha256` is accepted). " +
+					"When set, the controller's certificate must match one of them and is trusted without " +
+					"verifying its chain, so self-signed console certificates work without `allow_insecure`. " +
+					"List more than one to rotate certificates. Can be specified as a comma-separated list " +
+					"with the `UNIFI_PINNED_CERT_SHA256` environment variable.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate to present, for mTLS reverse proxies in " +
+					"front of the controller. Requires `client_key_pem` or `client_key_file`. Can be specified " +
+					"with the `UNIFI_CLIENT_CERT_PEM` environment variable. Conflicts with `client_cert_file`.",
+				Optional: true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM-encoded client certificate, as an alternative to " +
+					"`client_cert_pem`. Can be specified with the `UNIFI_CLIENT_CERT_FILE` environment variable.",
+				Optional: true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of the client certificate. Can be specified with " +
+					"the `UNIFI_CLIENT_KEY_PEM` environment variable. Conflicts with `client_key_file`.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the PEM-encoded private key of the client certificate, as an " +
+					"alternative to `client_key_pem`. Can be specified with the `UNIFI_CLIENT_KEY_FILE` " +
+					"environment variable.",
+				Optional: true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of API requests in flight at once, shared by every resource, " +
+					"data source, list resource and action using this provider configuration. Useful with high " +
+					"`-parallelism`, which can make UniFi OS consoles answer `429` or drop sessions. Can be " +
+					"specified with the `UNIFI_MAX_CONCURRENT_REQUESTS` environment variable. Default: `0` (no limit).",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum sustained rate of API requests per second, shared like " +
+					"`max_concurrent_requests`. Bursts of up to one second's worth of requests are allowed. " +
+					"Can be specified with the `UNIFI_REQUESTS_PER_SECOND` environment variable. Default: `0` (no limit).",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"retry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Retry policy for transient controller errors such as `502`, `503`, `429` or a " +
+					"reset connection. Requests are retried with exponential backoff. A request that creates an " +
+					"object is only sent again when the controller cannot have processed it; otherwise the " +
+					"provider first checks whether the request created the object and, if it did, uses it " +
+					"instead of creating a duplicate. Objects that existed before the request are never " +
+					"used. To tell them apart, the provider lists the collection before each such create, " +
+					"which adds one `GET` per created object and counts towards `requests_per_second`; " +
+					"`max_attempts = 1` avoids it. Independently of this policy, an expired username/password " +
+					"session is renewed once and the request is sent again.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf(
+							"Maximum number of attempts per request, including the first. `1` disables retries. "+
+								"Default: `%d`.",
+							defaultRetryMaxAttempts,
+						),
+						Optional: true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf(
+							"Wait before the first retry, as a Go duration (e.g. `500ms`). The wait doubles "+
+								"after each retry, up to `%s`. Default: `%s`.",
+							maxRetryBackoff,
+							defaultRetryBackoff,
+						),
+						Optional:   true,
+						CustomType: timetypes.GoDurationType{},
+					},
+					"status_codes": schema.ListAttribute{
+						MarkdownDescription: "HTTP status codes that are treated as transient. Default: " +
+							"`[429, 502, 503, 504]`.",
+						Optional:    true,
+						ElementType: types.Int64Type,
+						Validators: []validator.List{
+							listvalidator.ValueInt64sAre(int64validator.Between(400, 599)),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -193,12 +403,130 @@ func (p *unifiProvider) Configure(
 		}
 	}
 
+	readOnly := config.ReadOnly.ValueBool()
+	if !readOnly {
+		if v := os.Getenv("UNIFI_READ_ONLY"); v != "" {
+			readOnly = v == "true"
+		}
+	}
+
+	lockoutProtection := config.LockoutProtection.ValueBool()
+	if !lockoutProtection {
+		if v := os.Getenv("UNIFI_LOCKOUT_PROTECTION"); v != "" {
+			lockoutProtection = v == "true"
+		}
+	}
+
+	tlsCfg := tlsSettings{
+		CACertPEM:      stringValueOrEnv(config.CACertPEM, "UNIFI_CA_CERT_PEM"),
+		CACertFile:     stringValueOrEnv(config.CACertFile, "UNIFI_CA_CERT_FILE"),
+		ClientCertPEM:  stringValueOrEnv(config.ClientCertPEM, "UNIFI_CLIENT_CERT_PEM"),
+		ClientCertFile: stringValueOrEnv(config.ClientCertFile, "UNIFI_CLIENT_CERT_FILE"),
+		ClientKeyPEM:   stringValueOrEnv(config.ClientKeyPEM, "UNIFI_CLIENT_KEY_PEM"),
+		ClientKeyFile:  stringValueOrEnv(config.ClientKeyFile, "UNIFI_CLIENT_KEY_FILE"),
+	}
+	if !config.PinnedCertSHA256.IsNull() {
+		resp.Diagnostics.Append(config.PinnedCertSHA256.ElementsAs(ctx, &tlsCfg.PinnedCertSHA256, false)...)
+	} else if v := os.Getenv("UNIFI_PINNED_CERT_SHA256"); v != "" {
+		for _, pin := range strings.Split(v, ",") {
+			if pin = strings.TrimSpace(pin); pin != "" {
+				tlsCfg.PinnedCertSHA256 = append(tlsCfg.PinnedCertSHA256, pin)
+			}
+		}
+	}
+
+	maxConcurrentRequests := config.MaxConcurrentRequests.ValueInt64()
+	if config.MaxConcurrentRequests.IsNull() {
+		if v := os.Getenv("UNIFI_MAX_CONCURRENT_REQUESTS"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("max_concurrent_requests"),
+					"Invalid UNIFI_MAX_CONCURRENT_REQUESTS Value",
+					"UNIFI_MAX_CONCURRENT_REQUESTS must be a non-negative integer, got "+strconv.Quote(v)+".",
+				)
+			}
+			maxConcurrentRequests = n
+		}
+	}
+
+	requestsPerSecond := config.RequestsPerSecond.ValueFloat64()
+	if config.RequestsPerSecond.IsNull() {
+		if v := os.Getenv("UNIFI_REQUESTS_PER_SECOND"); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("requests_per_second"),
+					"Invalid UNIFI_REQUESTS_PER_SECOND Value",
+					"UNIFI_REQUESTS_PER_SECOND must be a non-negative number, got "+strconv.Quote(v)+".",
+				)
+			}
+			requestsPerSecond = f
+		}
+	}
+
+	retryCfg := retryConfig{
+		MaxAttempts: defaultRetryMaxAttempts,
+		Backoff:     defaultRetryBackoff,
+		StatusCodes: defaultRetryStatusCodes,
+	}
+	if !config.Retry.IsNull() && !config.Retry.IsUnknown() {
+		var retryModel unifiProviderRetryModel
+		resp.Diagnostics.Append(config.Retry.As(ctx, &retryModel, basetypes.ObjectAsOptions{})...)
+
+		if !retryModel.MaxAttempts.IsNull() {
+			retryCfg.MaxAttempts = retryModel.MaxAttempts.ValueInt64()
+		}
+		if !retryModel.Backoff.IsNull() {
+			backoff, d := retryModel.Backoff.ValueGoDuration()
+			resp.Diagnostics.Append(d...)
+			if backoff <= 0 || backoff > maxRetryBackoff {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("retry").AtName("backoff"),
+					"Invalid Retry Backoff",
+					fmt.Sprintf("backoff must be greater than 0 and at most %s, got %s.", maxRetryBackoff, backoff),
+				)
+			}
+			retryCfg.Backoff = backoff
+		}
+		if !retryModel.StatusCodes.IsNull() {
+			retryCfg.StatusCodes = nil
+			resp.Diagnostics.Append(retryModel.StatusCodes.ElementsAs(ctx, &retryCfg.StatusCodes, false)...)
+		}
+	}
+
 	site := config.Site.ValueString()
 	if site == "" {
 		if v := os.Getenv("UNIFI_SITE"); v != "" {
 			site = v
 		}
 	}
+
+	// Arguments and environment variables take precedence over the profile,
+	// like the shared credentials file of the AWS provider.
+	if profileName := stringValueOrEnv(config.Profile, "UNIFI_PROFILE"); profileName != "" {
+		credentialsFile := stringValueOrEnv(config.CredentialsFile, "UNIFI_CREDENTIALS_FILE")
+		profile, err := loadCredentialsProfile(credentialsFile, profileName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile"),
+				"Unable to Load Credentials Profile",
+				err.Error(),
+			)
+			return
+		}
+		if apiUrl == "" {
+			apiUrl = profile.APIURL
+		}
+		profile.fillAuth(&apiKey, &username, &password)
+		if site == "" {
+			site = profile.Site
+		}
+		if config.AllowInsecure.IsNull() && os.Getenv("UNIFI_INSECURE") == "" {
+			allowInsecure = profile.AllowInsecure
+		}
+		profile.fillTLS(&tlsCfg)
+	}
 	if site == "" {
 		site = "default"
 	}
@@ -210,6 +538,10 @@ func (p *unifiProvider) Configure(
 	ctx = tflog.SetField(ctx, "unifi_site", site)
 	ctx = tflog.SetField(ctx, "unifi_allow_insecure", allowInsecure)
 	ctx = tflog.SetField(ctx, "unifi_cloud_connector", cloudConnector)
+	ctx = tflog.SetField(ctx, "unifi_read_only", readOnly)
+	ctx = tflog.SetField(ctx, "unifi_lockout_protection", lockoutProtection)
+	ctx = tflog.SetField(ctx, "unifi_max_concurrent_requests", maxConcurrentRequests)
+	ctx = tflog.SetField(ctx, "unifi_requests_per_second", requestsPerSecond)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "unifi_api_key")
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "unifi_password")
 
@@ -227,6 +559,7 @@ func (p *unifiProvider) Configure(
 		}
 		// Force secure connections for cloud
 		allowInsecure = false
+		tlsCfg = tlsSettings{}
 	} else {
 		// Direct connection validation
 		if apiUrl == "" {
@@ -250,6 +583,58 @@ func (p *unifiProvider) Configure(
 		return
 	}
 
+	tlsMaterial, err := tlsCfg.load()
+	if err != nil {
+		var settingErr *settingError
+		if errors.As(err, &settingErr) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(settingErr.Attribute),
+				"Invalid TLS Configuration",
+				settingErr.Err.Error(),
+			)
+		} else {
+			resp.Diagnostics.AddError("Invalid TLS Configuration", err.Error())
+		}
+		return
+	}
+
+	// go-unifi ignores username and password when an API key is set, and API
+	// keys do not expire, so there is no session to renew.
+	if apiKey == "" && !cloudConnector {
+		retryCfg.Username = username
+		retryCfg.Password = password
+	}
+
+	var audit *auditLog
+	if auditLogPath := stringValueOrEnv(config.AuditLogPath, "UNIFI_AUDIT_LOG_PATH"); auditLogPath != "" {
+		audit, err = openAuditLog(auditLogPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("audit_log_path"),
+				"Unable to Open Audit Log",
+				"Could not open the audit log: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	httpClient, err := newHTTPClient(transportConfig{
+		AllowInsecure:         allowInsecure,
+		TLS:                   tlsMaterial,
+		MaxConcurrentRequests: maxConcurrentRequests,
+		RequestsPerSecond:     requestsPerSecond,
+		Retry:                 retryCfg,
+		ReadOnly:              readOnly,
+		AuditLog:              audit,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create HTTP Client",
+			"Could not create the HTTP client for the UniFi controller: "+err.Error(),
+		)
+		return
+	}
+
 	// Create UniFi client
 	client, err := ui.New(ctx, &ui.Config{
 		BaseURL:        apiUrl,
@@ -260,6 +645,7 @@ func (p *unifiProvider) Configure(
 		CloudConnector: cloudConnector,
 		HardwareID:     hardwareID,
 		Logger:         NewLogger(ctx),
+		HTTPClient:     httpClient,
 	})
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -274,10 +660,55 @@ func (p *unifiProvider) Configure(
 		return
 	}
 
+	controller := controllerInfo{Console: consoleTypeCloudConnector}
+	var controllerErr error
+	if !cloudConnector {
+		controller, controllerErr = detectController(ctx, httpClient, apiUrl, site, apiKey)
+		if controllerErr != nil {
+			tflog.Warn(ctx, "Unable to detect the UniFi controller version, skipping minimum version checks", map[string]any{
+				"error": controllerErr.Error(),
+			})
+		}
+	}
+	tflog.Debug(ctx, "Detected UniFi controller", map[string]any{
+		"console_type": string(controller.Console),
+		"version":      controller.Version,
+	})
+
 	// Create wrapper client with site info
 	configuredClient := &Client{
-		ApiClient: client,
-		Site:      site,
+		ApiClient:     client,
+		Site:          site,
+		controller:    controller,
+		controllerErr: controllerErr,
+		readOnly:      readOnly,
+		httpClient:    httpClient,
+		apiKey:        apiKey,
+	}
+
+	if lockoutProtection {
+		configuredClient.lockout, err = detectManagementPath(ctx, configuredClient, httpClient, apiUrl, apiKey)
+		switch {
+		case err != nil:
+			resp.Diagnostics.AddWarning(
+				"Unable to Detect Management Path",
+				"lockout_protection is enabled, but the provider could not work out its connection to "+
+					"the controller, so no changes will be refused: "+err.Error(),
+			)
+		case configuredClient.lockout.empty():
+			resp.Diagnostics.AddWarning(
+				"Unable to Detect Management Path",
+				"lockout_protection is enabled, but neither the host running Terraform nor the "+
+					"controller is on a network or behind a device of site "+site+", so no changes will be refused.",
+			)
+		default:
+			tflog.Debug(ctx, "Detected management path", map[string]any{
+				"provider_ip":   configuredClient.lockout.Provider.IP.String(),
+				"controller_ip": configuredClient.lockout.Controller.IP.String(),
+				"networks":      len(configuredClient.lockout.Networks),
+				"devices":       len(configuredClient.lockout.Devices),
+			})
+		}
 	}
 
 	resp.DataSourceData = configuredClient
@@ -317,6 +748,7 @@ func (p *unifiProvider) Resources(ctx context.Context) []func() resource.Resourc
 		NewWireguardPeerResource,
 		NewClientQosRateResource,
 		NewTrafficRouteResource,
+		NewHotspotVouchersResource,
 	}
 }
 
@@ -335,13 +767,22 @@ func (p *unifiProvider) DataSources(ctx context.Context) []func() datasource.Dat
 		NewPortProfileDataSource,
 		NewRadiusProfileDataSource,
 		NewClientQosRateDataSource,
+		NewSpeedtestResultsDataSource,
+		NewBackupsDataSource,
+		NewDeviceDataSource,
+		NewWLANDataSource,
+		NewFirewallGroupDataSource,
+		NewSiteHealthDataSource,
 	}
 }
 
 func (p *unifiProvider) EphemeralResources(
 	ctx context.Context,
 ) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		NewWireguardKeypairEphemeralResource,
+		NewGeneratedPassphraseEphemeralResource,
+	}
 }
 
 func (p *unifiProvider) Actions(
@@ -349,6 +790,25 @@ func (p *unifiProvider) Actions(
 ) []func() action.Action {
 	return []func() action.Action{
 		NewPortAction,
+		NewDeviceRestartAction,
+		NewDeviceUpgradeAction,
+		NewPortPowerCycleAction,
+		NewClientCommandAction,
+		NewSpeedtestAction,
+		NewBackupAction,
+		NewDeviceCommandAction,
+		NewWLANRotatePassphraseAction,
+		NewHotspotVouchersGenerateAction,
+	}
+}
+
+// Functions implements [provider.ProviderWithFunctions].
+func (p *unifiProvider) Functions(context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewNormalizeMACFunction,
+		NewParseWireGuardConfigFunction,
+		NewVLANSubnetFunction,
+		NewWireGuardClientConfigFunction,
 	}
 }
 
@@ -380,5 +840,15 @@ func (p *unifiProvider) ListResources(context.Context) []func() list.ListResourc
 		NewPortProfileListResource,
 		NewDeviceListResource,
 		NewFirewallPolicyListResource,
+		NewHotspotVouchersListResource,
+	}
+}
+
+// stringValueOrEnv returns the configured value, or the environment variable
+// if the argument is not set.
+func stringValueOrEnv(v types.String, env string) string {
+	if s := v.ValueString(); s != "" {
+		return s
 	}
+	return os.Getenv(env)
 }
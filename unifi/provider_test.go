@@ -16,6 +16,7 @@
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/compose"
 	"github.com/ubiquiti-community/go-unifi/unifi"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
 	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util"
 )
 
@@ -38,9 +39,48 @@ func TestMain(m *testing.M) {
 		os.Exit(m.Run())
 	}
 
+	// UNIFI_TEST_CONTROLLER=fake runs against the in-process fake controller
+	// instead of the container, so no Docker is needed.
+	if os.Getenv("UNIFI_TEST_CONTROLLER") == "fake" {
+		os.Exit(runFakeControllerTests(m))
+	}
+
 	os.Exit(runAcceptanceTests(m))
 }
 
+// runFakeControllerTests points the provider at a fakecontroller.Server for
+// the duration of the run. The fake stores objects as the provider sends them
+// and does not emulate hardware, so tests that need adopted devices still
+// require the container.
+func runFakeControllerTests(m *testing.M) int {
+	server := fakecontroller.New()
+	defer server.Close()
+
+	env := map[string]string{
+		"UNIFI_USERNAME": fakecontroller.DefaultUsername,
+		"UNIFI_PASSWORD": fakecontroller.DefaultPassword,
+		"UNIFI_INSECURE": "true",
+		"UNIFI_API":      server.URL,
+		"UNIFI_API_KEY":  "",
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			panic(err)
+		}
+	}
+
+	return m.Run()
+}
+
+// skipOnFakeController skips tests that depend on controller behavior the fake
+// does not emulate, such as device adoption. reason says what is missing.
+func skipOnFakeController(t *testing.T, reason string) {
+	t.Helper()
+	if os.Getenv("UNIFI_TEST_CONTROLLER") == "fake" {
+		t.Skip("not supported by the fake controller: " + reason)
+	}
+}
+
 type logConsumer struct {
 	StdOut bool
 
@@ -475,7 +515,13 @@ func Test_unifiProvider_Schema(t *testing.T) {
 	if resp.Diagnostics.HasError() {
 		t.Errorf("Schema() produced errors: %v", resp.Diagnostics)
 	}
-	for _, attr := range []string{"api_key", "username", "password", "api_url", "site", "allow_insecure"} {
+	for _, attr := range []string{
+		"api_key", "username", "password", "api_url", "site", "allow_insecure",
+		"max_concurrent_requests", "requests_per_second", "retry",
+		"ca_cert_pem", "ca_cert_file", "pinned_cert_sha256",
+		"client_cert_pem", "client_cert_file", "client_key_pem", "client_key_file",
+		"profile", "credentials_file", "read_only", "audit_log_path", "lockout_protection",
+	} {
 		if _, ok := resp.Schema.Attributes[attr]; !ok {
 			t.Errorf("missing attribute %q", attr)
 		}
@@ -510,7 +556,15 @@ func Test_unifiProvider_DataSources(t *testing.T) {
 
 func Test_unifiProvider_EphemeralResources(t *testing.T) {
 	p := &unifiProvider{}
-	_ = p.EphemeralResources(context.Background())
+	got := p.EphemeralResources(context.Background())
+	if len(got) == 0 {
+		t.Error("EphemeralResources() returned empty slice")
+	}
+	for i, factory := range got {
+		if er := factory(); er == nil {
+			t.Errorf("EphemeralResources()[%d]() returned nil", i)
+		}
+	}
 }
 
 func Test_unifiProvider_Actions(t *testing.T) {
@@ -526,6 +580,19 @@ func Test_unifiProvider_Actions(t *testing.T) {
 	}
 }
 
+func Test_unifiProvider_Functions(t *testing.T) {
+	p := &unifiProvider{}
+	got := p.Functions(context.Background())
+	if len(got) == 0 {
+		t.Error("Functions() returned empty slice")
+	}
+	for i, factory := range got {
+		if f := factory(); f == nil {
+			t.Errorf("Functions()[%d]() returned nil", i)
+		}
+	}
+}
+
 func Test_unifiProvider_ListResources(t *testing.T) {
 	p := &unifiProvider{}
 	got := p.ListResources(context.Background())
@@ -538,3 +605,22 @@ func Test_unifiProvider_ListResources(t *testing.T) {
 		}
 	}
 }
+
+// Test_unifiProvider_GetProviderSchema validates every schema the provider
+// serves, as Terraform does before each run; an invalid schema, such as one
+// using a reserved attribute name like `count`, breaks the whole provider.
+func Test_unifiProvider_GetProviderSchema(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(New())()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Errorf("GetProviderSchema() error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+)
+
+// readCacheTTL bounds how long a snapshot is served. Writes made through the
+// provider invalidate it immediately; the TTL only limits how stale a view of
+// changes made elsewhere, such as devices changing state, can get.
+const readCacheTTL = 30 * time.Second
+
+type freshReadKey struct{}
+
+// withFreshReads returns a context whose requests bypass the read cache. Use
+// it when polling for a change the provider did not make, such as a device
+// finishing provisioning, and when reading live state such as the statistics
+// of connected clients.
+func withFreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+// readCacheTransport keeps a short-lived snapshot of GET responses per site and
+// collection. During a refresh many resources list the same collection and
+// filter for their own object, so concurrent identical requests are collapsed
+// into one (singleflight) and later ones are served from the snapshot.
+//
+// Any other request invalidates the collection it writes to and the
+// collections derived from it, or the whole site when the collection cannot be
+// determined.
+type readCacheTransport struct {
+	next  http.RoundTripper
+	group singleflight.Group
+	now   func() time.Time
+
+	mu sync.Mutex
+	// entries is keyed by site, then collection, then URL.
+	entries map[string]map[string]map[string]*readCacheEntry
+	// The generations count invalidations of everything, of a site and of a
+	// collection, so a read that was in flight during a write is neither
+	// stored nor shared with requests made after it.
+	globalGen      uint64
+	siteGens       map[string]uint64
+	collectionGens map[string]map[string]uint64
+}
+
+// readCacheGen identifies the state of the cache a read started from.
+type readCacheGen struct {
+	global, site, collection uint64
+}
+
+type readCacheEntry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func newReadCacheTransport(next http.RoundTripper) *readCacheTransport {
+	return &readCacheTransport{
+		next:           next,
+		now:            time.Now,
+		entries:        map[string]map[string]map[string]*readCacheEntry{},
+		siteGens:       map[string]uint64{},
+		collectionGens: map[string]map[string]uint64{},
+	}
+}
+
+func (t *readCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	site, collection := cacheCollection(req.URL.Path)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if readOnlyAllowed(req) {
+			// Logins, POSTed reports and read-only commands change nothing.
+			return t.next.RoundTrip(req)
+		}
+		t.invalidate(site, collection)
+		resp, err := t.next.RoundTrip(req)
+		// Invalidate again: a read that started while the write was in
+		// flight may have stored the old state.
+		t.invalidate(site, collection)
+		return resp, err
+	}
+
+	if req.Method != http.MethodGet || site == "" || collection == "" {
+		return t.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	fresh, _ := req.Context().Value(freshReadKey{}).(bool)
+
+	t.mu.Lock()
+	gen := t.generation(site, collection)
+	entry := t.entries[site][collection][key]
+	t.mu.Unlock()
+
+	if entry != nil && !fresh && t.now().Before(entry.expires) {
+		tflog.Trace(req.Context(), "Serving UniFi API response from read cache", map[string]any{
+			"url": req.URL.Redacted(),
+		})
+		return entry.response(req), nil
+	}
+
+	flightKey := fmt.Sprintf("%s#%d.%d.%d", key, gen.global, gen.site, gen.collection)
+	if fresh {
+		// Do not join a flight that may have started before the change
+		// being polled for.
+		flightKey += "#fresh"
+	}
+
+	v, err, _ := t.group.Do(flightKey, func() (any, error) {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &readCacheEntry{
+			status:  resp.StatusCode,
+			header:  resp.Header.Clone(),
+			body:    body,
+			expires: t.now().Add(readCacheTTL),
+		}
+		if resp.StatusCode == http.StatusOK {
+			t.store(site, collection, key, gen, e)
+		}
+		return e, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	e, _ := v.(*readCacheEntry)
+	return e.response(req), nil
+}
+
+// generation must be called with mu held.
+func (t *readCacheTransport) generation(site, collection string) readCacheGen {
+	return readCacheGen{
+		global:     t.globalGen,
+		site:       t.siteGens[site],
+		collection: t.collectionGens[site][collection],
+	}
+}
+
+func (t *readCacheTransport) store(site, collection, key string, gen readCacheGen, e *readCacheEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.generation(site, collection) != gen {
+		return
+	}
+	if t.entries[site] == nil {
+		t.entries[site] = map[string]map[string]*readCacheEntry{}
+	}
+	if t.entries[site][collection] == nil {
+		t.entries[site][collection] = map[string]*readCacheEntry{}
+	}
+	t.entries[site][collection][key] = e
+}
+
+// invalidate drops the snapshot of a collection. An empty collection drops
+// the whole site, and an empty site drops everything.
+func (t *readCacheTransport) invalidate(site, collection string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case site == "":
+		t.globalGen++
+		clear(t.entries)
+	case collection == "":
+		t.siteGens[site]++
+		delete(t.entries, site)
+	default:
+		if t.collectionGens[site] == nil {
+			t.collectionGens[site] = map[string]uint64{}
+		}
+		for _, c := range append([]string{collection}, cacheDerivedCollections[collection]...) {
+			t.collectionGens[site][c]++
+			delete(t.entries[site], c)
+		}
+	}
+}
+
+func (e *readCacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// cacheCollections maps the endpoints that read or write the same objects to a
+// single collection, so a write through one invalidates reads through the
+// others. stat/device and stat/sta are cached like the rest of the collection,
+// as the resources list devices and clients through them; readers of their
+// live fields use withFreshReads.
+var cacheCollections = map[string]string{
+	"rest/device":  "device",
+	"stat/device":  "device",
+	"cmd/devmgr":   "device",
+	"rest/user":    "user",
+	"stat/sta":     "user",
+	"stat/alluser": "user",
+	"list/user":    "user",
+	"cmd/stamgr":   "user",
+	"get/setting":  "setting",
+	"set/setting":  "setting",
+	"rest/setting": "setting",
+}
+
+// cacheDerivedCollections lists, for a collection, the other collections whose
+// objects the controller changes when it is written. Networks are members of
+// firewall zones, and the controller keeps the predefined policies between
+// zones and the policies of port forwards in step with them. AP groups list
+// device MACs, and devices reference port profiles. Live statistics such as
+// stat/health are never cached, so they need no entry.
+var cacheDerivedCollections = map[string][]string{
+	"networkconf": {"v2/firewall", "v2/firewall-policies"},
+	"v2/firewall": {"networkconf", "v2/firewall-policies"},
+	"portforward": {"v2/firewall-policies"},
+	"device":      {"v2/apgroups"},
+	"portconf":    {"device"},
+}
+
+// cacheCollection returns the site and collection a controller path belongs
+// to. The collection is empty when it is unknown or the endpoint reports live
+// state that must not be cached; the site is empty for paths outside a site.
+func cacheCollection(path string) (string, string) {
+	if i := strings.Index(path, "/api/s/"); i >= 0 {
+		parts := strings.Split(strings.Trim(path[i+len("/api/s/"):], "/"), "/")
+		if len(parts) < 3 {
+			return parts[0], ""
+		}
+		site, kind, name := parts[0], parts[1], parts[2]
+
+		if collection, ok := cacheCollections[kind+"/"+name]; ok {
+			return site, collection
+		}
+		switch kind {
+		case "rest", "upd", "list":
+			return site, name
+		}
+		return site, ""
+	}
+
+	if i := strings.Index(path, "/v2/api/site/"); i >= 0 {
+		parts := strings.Split(strings.Trim(path[i+len("/v2/api/site/"):], "/"), "/")
+		if len(parts) < 2 {
+			return parts[0], ""
+		}
+		return parts[0], "v2/" + parts[1]
+	}
+
+	return "", ""
+}
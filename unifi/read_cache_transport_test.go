@@ -0,0 +1,272 @@
+package unifi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_cacheCollection(t *testing.T) {
+	tests := []struct {
+		path           string
+		wantSite       string
+		wantCollection string
+	}{
+		{"/api/s/default/rest/networkconf", "default", "networkconf"},
+		{"/proxy/network/api/s/default/rest/networkconf/6500", "default", "networkconf"},
+		{"/api/s/default/stat/device", "default", "device"},
+		{"/api/s/default/stat/device/aa:bb:cc:dd:ee:ff", "default", "device"},
+		{"/api/s/default/rest/device/6500", "default", "device"},
+		{"/api/s/default/cmd/devmgr", "default", "device"},
+		{"/api/s/branch/stat/alluser", "branch", "user"},
+		{"/api/s/branch/cmd/stamgr", "branch", "user"},
+		{"/api/s/default/get/setting/mgmt", "default", "setting"},
+		{"/api/s/default/set/setting/mgmt", "default", "setting"},
+		{"/api/s/default/stat/health", "default", ""},
+		{"/api/s/default/cmd/sitemgr", "default", ""},
+		{"/v2/api/site/default/trafficroutes", "default", "v2/trafficroutes"},
+		{"/proxy/network/v2/api/site/default/apgroups/6500", "default", "v2/apgroups"},
+		{"/v2/api/site/default/firewall/zone/6500", "default", "v2/firewall"},
+		{"/api/self/sites", "", ""},
+		{"/api/login", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			site, collection := cacheCollection(tt.path)
+			if site != tt.wantSite || collection != tt.wantCollection {
+				t.Errorf("cacheCollection() = (%q, %q), want (%q, %q)",
+					site, collection, tt.wantSite, tt.wantCollection)
+			}
+		})
+	}
+}
+
+// countingServer counts GETs per path and answers with the number of writes
+// seen so far, so tests can tell a stale snapshot from a fresh one.
+func countingServer(t *testing.T) (*httptest.Server, *sync.Map, *atomic.Int64) {
+	t.Helper()
+	var gets sync.Map
+	var writes atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes.Add(1)
+			return
+		}
+		n, _ := gets.LoadOrStore(r.URL.Path, new(atomic.Int64))
+		if c, ok := n.(*atomic.Int64); ok {
+			c.Add(1)
+		}
+		_, _ = io.WriteString(w, strings.Repeat("w", int(writes.Load())))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gets, &writes
+}
+
+func getCount(gets *sync.Map, path string) int64 {
+	n, ok := gets.Load(path)
+	if !ok {
+		return 0
+	}
+	c, _ := n.(*atomic.Int64)
+	return c.Load()
+}
+
+func cachedGet(t *testing.T, c *http.Client, ctx context.Context, url string) string {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func Test_readCacheTransport(t *testing.T) {
+	ctx := context.Background()
+	networks := "/api/s/default/rest/networkconf"
+	wlans := "/api/s/default/rest/wlanconf"
+
+	t.Run("serves repeated reads from the snapshot", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+
+		for range 5 {
+			cachedGet(t, c, ctx, srv.URL+networks)
+		}
+		if got := getCount(gets, networks); got != 1 {
+			t.Errorf("GETs = %d, want 1", got)
+		}
+	})
+
+	t.Run("collapses concurrent reads", func(t *testing.T) {
+		var gets atomic.Int64
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gets.Add(1)
+			<-release
+		}))
+		defer srv.Close()
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				cachedGet(t, c, ctx, srv.URL+networks)
+			}()
+		}
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		if got := gets.Load(); got != 1 {
+			t.Errorf("GETs = %d, want 1", got)
+		}
+	})
+
+	t.Run("writes invalidate only their collection", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+
+		cachedGet(t, c, ctx, srv.URL+networks)
+		cachedGet(t, c, ctx, srv.URL+wlans)
+
+		resp, err := c.Post(srv.URL+networks, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if got := cachedGet(t, c, ctx, srv.URL+networks); got != "w" {
+			t.Errorf("read after write = %q, want the fresh response", got)
+		}
+		cachedGet(t, c, ctx, srv.URL+wlans)
+
+		if got := getCount(gets, networks); got != 2 {
+			t.Errorf("network GETs = %d, want 2", got)
+		}
+		if got := getCount(gets, wlans); got != 1 {
+			t.Errorf("WLAN GETs = %d, want 1", got)
+		}
+	})
+
+	t.Run("writes invalidate derived collections", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+		zones := "/v2/api/site/default/firewall/zone"
+		policies := "/v2/api/site/default/firewall-policies"
+
+		cachedGet(t, c, ctx, srv.URL+zones)
+		cachedGet(t, c, ctx, srv.URL+policies)
+		cachedGet(t, c, ctx, srv.URL+wlans)
+
+		resp, err := c.Post(srv.URL+networks, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if got := cachedGet(t, c, ctx, srv.URL+zones); got != "w" {
+			t.Errorf("zones after network write = %q, want the fresh response", got)
+		}
+		cachedGet(t, c, ctx, srv.URL+policies)
+		cachedGet(t, c, ctx, srv.URL+wlans)
+
+		if got := getCount(gets, policies); got != 2 {
+			t.Errorf("policy GETs = %d, want 2", got)
+		}
+		if got := getCount(gets, wlans); got != 1 {
+			t.Errorf("WLAN GETs = %d, want 1", got)
+		}
+	})
+
+	t.Run("device commands invalidate device stats", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+		devices := "/api/s/default/stat/device"
+
+		cachedGet(t, c, ctx, srv.URL+devices)
+		resp, err := c.Post(srv.URL+"/api/s/default/cmd/devmgr", "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		cachedGet(t, c, ctx, srv.URL+devices)
+
+		if got := getCount(gets, devices); got != 2 {
+			t.Errorf("device GETs = %d, want 2", got)
+		}
+	})
+
+	t.Run("read-only commands keep device stats", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+		devices := "/api/s/default/stat/device"
+
+		cachedGet(t, c, ctx, srv.URL+devices)
+		resp, err := c.Post(
+			srv.URL+"/api/s/default/cmd/devmgr",
+			"application/json",
+			strings.NewReader(`{"cmd":"speedtest-status"}`),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		cachedGet(t, c, ctx, srv.URL+devices)
+
+		if got := getCount(gets, devices); got != 1 {
+			t.Errorf("device GETs = %d, want 1", got)
+		}
+	})
+
+	t.Run("fresh reads bypass the snapshot", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+
+		cachedGet(t, c, ctx, srv.URL+networks)
+		cachedGet(t, c, withFreshReads(ctx), srv.URL+networks)
+
+		if got := getCount(gets, networks); got != 2 {
+			t.Errorf("GETs = %d, want 2", got)
+		}
+	})
+
+	t.Run("snapshots expire", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		rt := newReadCacheTransport(http.DefaultTransport)
+		now := time.Now()
+		rt.now = func() time.Time { return now }
+		c := &http.Client{Transport: rt}
+
+		cachedGet(t, c, ctx, srv.URL+networks)
+		now = now.Add(readCacheTTL + time.Second)
+		cachedGet(t, c, ctx, srv.URL+networks)
+
+		if got := getCount(gets, networks); got != 2 {
+			t.Errorf("GETs = %d, want 2", got)
+		}
+	})
+
+	t.Run("uncacheable endpoints pass through", func(t *testing.T) {
+		srv, gets, _ := countingServer(t)
+		c := &http.Client{Transport: newReadCacheTransport(http.DefaultTransport)}
+		health := "/api/s/default/stat/health"
+
+		cachedGet(t, c, ctx, srv.URL+health)
+		cachedGet(t, c, ctx, srv.URL+health)
+
+		if got := getCount(gets, health); got != 2 {
+			t.Errorf("GETs = %d, want 2", got)
+		}
+	})
+}
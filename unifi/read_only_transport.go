@@ -0,0 +1,107 @@
+package unifi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// errReadOnly is returned for requests refused because `read_only` is set.
+var errReadOnly = errors.New("the provider is configured with read_only = true")
+
+// readOnlyTransport refuses every request that could change the controller
+// before it is sent. Resources call the API client directly, so this is what
+// makes Create, Update and Delete fail in read-only mode; actions check
+// Client.checkWritable first to fail with a clearer diagnostic.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !readOnlyAllowed(req) {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: refusing to send %s %s", errReadOnly, req.Method, req.URL.Path)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// readOnlyAllowed reports whether req only reads. Besides GET and HEAD, that
+// is logging in and out, the `stat/*` reports some controllers expect to be
+// POSTed, and the commands listed in readOnlyCommands.
+func readOnlyAllowed(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+	default:
+		return false
+	}
+
+	if isLoginRequest(req) || isLogoutRequest(req) {
+		return true
+	}
+	if i := strings.Index(req.URL.Path, "/api/s/"); i >= 0 {
+		parts := strings.Split(strings.Trim(req.URL.Path[i+len("/api/s/"):], "/"), "/")
+		if len(parts) >= 2 && parts[1] == "stat" {
+			return true
+		}
+		if len(parts) == 3 && parts[1] == "cmd" {
+			return slices.Contains(readOnlyCommands[parts[2]], requestCommand(req))
+		}
+	}
+	return false
+}
+
+// readOnlyCommands lists, per `cmd/{manager}` endpoint, the commands that
+// only read.
+var readOnlyCommands = map[string][]string{
+	"backup": {"list-backups"},
+	"devmgr": {"speedtest-status"},
+}
+
+// requestCommand returns the `cmd` of a command request body, read through
+// GetBody so the body itself is left for the transport. It is empty if the
+// body cannot be read again.
+func requestCommand(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var cmd struct {
+		Cmd string `json:"cmd"`
+	}
+	if json.NewDecoder(body).Decode(&cmd) != nil {
+		return ""
+	}
+	return cmd.Cmd
+}
+
+// checkWritable reports an error diagnostic in read-only mode. Actions call
+// it before contacting the controller.
+func (c *Client) checkWritable(typeName string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if c != nil && c.readOnly {
+		diags.AddError(
+			"Provider Is Read-Only",
+			fmt.Sprintf(
+				"%s cannot be invoked because the provider is configured with read_only = true "+
+					"(or UNIFI_READ_ONLY). Plan, refresh, data sources, list resources and imports "+
+					"work in this mode; changes to the controller do not.",
+				typeName,
+			),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,90 @@
+package unifi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_readOnlyAllowed(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   bool
+	}{
+		{http.MethodGet, "/api/s/default/rest/networkconf", "", true},
+		{http.MethodHead, "/", "", true},
+		{http.MethodPost, "/api/login", "", true},
+		{http.MethodPost, "/api/auth/login", "", true},
+		{http.MethodPost, "/api/auth/logout", "", true},
+		{http.MethodPost, "/proxy/network/api/s/default/stat/report/daily.site", "", true},
+		{http.MethodPost, "/api/s/default/rest/networkconf", "", false},
+		{http.MethodPost, "/api/s/default/cmd/devmgr", "", false},
+		{http.MethodPost, "/v2/api/site/default/trafficroutes", "", false},
+		{http.MethodPut, "/api/s/default/rest/networkconf/1", "", false},
+		{http.MethodPut, "/api/s/default/stat/device", "", false},
+		{http.MethodDelete, "/api/s/default/rest/networkconf/1", "", false},
+		{http.MethodPost, "/api/s/default/cmd/backup", `{"cmd":"list-backups"}`, true},
+		{http.MethodPost, "/api/s/default/cmd/backup", `{"cmd":"backup","days":0}`, false},
+		{http.MethodPost, "/api/s/default/cmd/devmgr", `{"cmd":"list-backups"}`, false},
+		{http.MethodPost, "/api/s/default/cmd/devmgr", `{"cmd":"speedtest-status"}`, true},
+		{http.MethodPost, "/api/s/default/cmd/devmgr", `{"cmd":"speedtest"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://unifi"+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readOnlyAllowed(req); got != tt.want {
+				t.Errorf("readOnlyAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readOnlyTransport(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	c, err := newHTTPClient(transportConfig{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Get(srv.URL + "/api/s/default/rest/networkconf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	_, err = c.Post(srv.URL+"/api/s/default/rest/networkconf", "application/json", strings.NewReader(`{}`))
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("POST error = %v, want %v", err, errReadOnly)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests reaching the controller = %d, want 1", got)
+	}
+}
+
+func TestClient_checkWritable(t *testing.T) {
+	if diags := (&Client{}).checkWritable("unifi_port"); diags.HasError() {
+		t.Errorf("checkWritable() = %v, want no diagnostics", diags)
+	}
+	if diags := (*Client)(nil).checkWritable("unifi_port"); diags.HasError() {
+		t.Errorf("checkWritable() on an unconfigured provider = %v, want no diagnostics", diags)
+	}
+
+	diags := (&Client{readOnly: true}).checkWritable("unifi_port")
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "unifi_port") {
+		t.Errorf("checkWritable() = %v, want an error naming the action", diags)
+	}
+}
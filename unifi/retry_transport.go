@@ -0,0 +1,586 @@
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util/retry"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBackoff     = time.Second
+
+	// maxRetryBackoff is where retry.StateChangeConf caps its exponential
+	// backoff.
+	maxRetryBackoff = 10 * time.Second
+)
+
+// defaultRetryStatusCodes are the responses UniFi consoles send when they are
+// overloaded or restarting the Network application.
+var defaultRetryStatusCodes = []int64{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retryConfig is the retry policy from the provider `retry` block.
+type retryConfig struct {
+	MaxAttempts int64
+	Backoff     time.Duration
+	StatusCodes []int64
+
+	// Username and Password are set for session authentication, so an
+	// expired session can be renewed. They are empty with an API key.
+	Username string
+	Password string
+}
+
+// retryTransport retries requests that failed with a transient error and
+// renews expired sessions.
+//
+// Requests that are not idempotent are only replayed when the controller
+// cannot have acted on them: the request was never written, or it was
+// answered with 429. Before a create (a POST to a REST or v2 collection) the
+// IDs already in the collection are recorded. After any other failure of the
+// create, the collection is listed again and, if a new object with the same
+// name or MAC is there, its creation is reported instead of creating a
+// duplicate.
+type retryTransport struct {
+	next http.RoundTripper
+	jar  http.CookieJar
+	cfg  retryConfig
+
+	// authMu serializes logins. authGen counts them, so requests that saw the
+	// same expired session log in only once.
+	authMu    sync.Mutex
+	authGen   int
+	csrfToken string
+}
+
+// newRetryTransport wraps next. It returns next unchanged when neither retries
+// nor session renewal are enabled.
+func newRetryTransport(next http.RoundTripper, jar http.CookieJar, cfg retryConfig) http.RoundTripper {
+	if cfg.MaxAttempts <= 1 && cfg.Username == "" {
+		return next
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &retryTransport{next: next, jar: jar, cfg: cfg}
+}
+
+// retryDecision is what to do after a failed attempt.
+type retryDecision int
+
+const (
+	retryNo retryDecision = iota
+	retryYes
+	// retryIfAbsent replays a create only if the object was not created.
+	retryIfAbsent
+)
+
+// attempt is the outcome of one round trip.
+type attempt struct {
+	resp *http.Response
+	err  error
+	// wrote reports whether the request was fully sent. It is set from the
+	// transport's write loop.
+	wrote atomic.Bool
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var before map[string]bool
+	if t.cfg.MaxAttempts > 1 {
+		before = t.snapshotCreate(req, body)
+	}
+
+	final, again := t.settle(req, body, before, t.do(req, body), 1)
+	if !again {
+		return final.resp, final.err
+	}
+
+	attempts := int64(1)
+	conf := &retry.StateChangeConf{
+		Pending:    []string{"retry"},
+		Target:     []string{"done"},
+		Delay:      t.cfg.Backoff,
+		MinTimeout: t.cfg.Backoff,
+		// Attempts and the request context bound the loop.
+		Timeout: 24 * time.Hour,
+		Refresh: func() (any, string, error) {
+			closeBody(final.resp)
+			attempts++
+			tflog.Debug(req.Context(), "Retrying UniFi API request", map[string]any{
+				"method":  req.Method,
+				"url":     req.URL.Redacted(),
+				"attempt": attempts,
+				"cause":   describeAttempt(final),
+			})
+
+			var again bool
+			final, again = t.settle(req, body, before, t.do(req, body), attempts)
+			if again {
+				return final, "retry", nil
+			}
+			return final, "done", nil
+		},
+	}
+
+	result, err := conf.WaitForStateContext(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	a, ok := result.(*attempt)
+	if !ok {
+		return nil, fmt.Errorf("unexpected retry result %T", result)
+	}
+	return a.resp, a.err
+}
+
+// settle decides whether to retry after the given attempt. An ambiguous create
+// is resolved here, by reporting the object if it was created anyway. before
+// holds the IDs the collection had before the create.
+func (t *retryTransport) settle(
+	req *http.Request,
+	body []byte,
+	before map[string]bool,
+	a *attempt,
+	attempts int64,
+) (*attempt, bool) {
+	decision := t.decide(req, a)
+	if decision == retryNo {
+		return a, false
+	}
+	// The lookup and the next attempt need a rate limiter slot of their own,
+	// and the backoff should not hold one either.
+	bufferBody(a.resp)
+
+	if decision == retryIfAbsent {
+		resp, found, ok := t.findCreated(req, body, before)
+		if !ok {
+			return a, false
+		}
+		if found {
+			return &attempt{resp: resp}, false
+		}
+	}
+	return a, attempts < t.cfg.MaxAttempts
+}
+
+// do sends the request once, renewing the session and sending it again if the
+// controller reports that the session expired.
+func (t *retryTransport) do(req *http.Request, body []byte) *attempt {
+	t.authMu.Lock()
+	gen := t.authGen
+	t.authMu.Unlock()
+
+	a := t.send(req, body)
+	if !t.sessionExpired(req, a) {
+		return a
+	}
+	// The login needs a rate limiter slot of its own.
+	bufferBody(a.resp)
+
+	if err := t.reauthenticate(req.Context(), req.URL, gen); err != nil {
+		tflog.Warn(req.Context(), "Could not renew UniFi session", map[string]any{
+			"error": err.Error(),
+		})
+		return a
+	}
+
+	return t.send(req, body)
+}
+
+func (t *retryTransport) send(req *http.Request, body []byte) *attempt {
+	a := &attempt{}
+
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			a.wrote.Store(info.Err == nil)
+		},
+	}
+	r := req.Clone(httptrace.WithClientTrace(req.Context(), trace))
+	if body != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	t.authMu.Lock()
+	if t.authGen > 0 {
+		// The session was renewed here rather than by go-unifi, so the
+		// cookie and CSRF token it attached are stale.
+		r.Header.Del("Cookie")
+		for _, c := range t.jar.Cookies(r.URL) {
+			r.AddCookie(c)
+		}
+		if t.csrfToken != "" {
+			r.Header.Set("X-Csrf-Token", t.csrfToken)
+		}
+	}
+	t.authMu.Unlock()
+
+	a.resp, a.err = t.next.RoundTrip(r)
+
+	if a.resp != nil {
+		if token := a.resp.Header.Get("X-Updated-Csrf-Token"); token != "" {
+			t.authMu.Lock()
+			if t.authGen > 0 {
+				t.csrfToken = token
+			}
+			t.authMu.Unlock()
+		}
+	}
+
+	return a
+}
+
+// decide classifies a failed attempt.
+func (t *retryTransport) decide(req *http.Request, a *attempt) retryDecision {
+	if req.Context().Err() != nil {
+		return retryNo
+	}
+
+	if a.err != nil {
+		if isTLSVerificationError(a.err) {
+			return retryNo
+		}
+		if isIdempotent(req) || !a.wrote.Load() {
+			return retryYes
+		}
+		return retryIfAbsent
+	}
+
+	if !slices.Contains(t.cfg.StatusCodes, int64(a.resp.StatusCode)) {
+		return retryNo
+	}
+	if isIdempotent(req) || a.resp.StatusCode == http.StatusTooManyRequests {
+		return retryYes
+	}
+	return retryIfAbsent
+}
+
+func (t *retryTransport) sessionExpired(req *http.Request, a *attempt) bool {
+	return t.cfg.Username != "" &&
+		a.err == nil &&
+		a.resp.StatusCode == http.StatusUnauthorized &&
+		!isLoginRequest(req)
+}
+
+// reauthenticate logs in again unless another request already did so since
+// the session generation gen was observed.
+func (t *retryTransport) reauthenticate(ctx context.Context, u *url.URL, gen int) error {
+	t.authMu.Lock()
+	defer t.authMu.Unlock()
+
+	if t.authGen != gen {
+		return nil
+	}
+
+	loginURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/api/login"}
+	if strings.HasPrefix(u.Path, "/proxy/network/") {
+		loginURL.Path = "/api/auth/login"
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"username": t.cfg.Username,
+		"password": t.cfg.Password,
+		"remember": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	tflog.Debug(ctx, "Renewing expired UniFi session")
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("login returned %s", resp.Status)
+	}
+
+	t.jar.SetCookies(loginURL, resp.Cookies())
+	t.csrfToken = resp.Header.Get("X-Csrf-Token")
+	t.authGen++
+
+	return nil
+}
+
+// snapshotCreate returns the IDs in the collection a create is sent to, so an
+// ambiguous create can tell the object it created from one that already
+// existed. The list is taken before every such create, as it cannot be taken
+// once the outcome is in doubt; this costs one GET per create while retries
+// are enabled. It returns nil when the request is not a create that can be
+// resolved, or the collection cannot be listed.
+func (t *retryTransport) snapshotCreate(req *http.Request, body []byte) map[string]bool {
+	if !isCollectionCreate(req) {
+		return nil
+	}
+	var sent map[string]any
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return nil
+	}
+	if key, _ := createIdentity(sent); key == "" {
+		return nil
+	}
+
+	objects, ok := t.listCollection(req)
+	if !ok {
+		return nil
+	}
+	ids := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		if id := objectID(obj); id != "" {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// findCreated looks for the object a failed create may have created anyway:
+// an object with the same identity whose ID was not in the collection before
+// the create. ok is false when that cannot be determined, in which case the
+// create must not be replayed.
+func (t *retryTransport) findCreated(
+	req *http.Request,
+	body []byte,
+	before map[string]bool,
+) (*http.Response, bool, bool) {
+	if before == nil || !isCollectionCreate(req) {
+		return nil, false, false
+	}
+
+	var sent map[string]any
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return nil, false, false
+	}
+	key, value := createIdentity(sent)
+	if key == "" {
+		return nil, false, false
+	}
+
+	objects, ok := t.listCollection(req)
+	if !ok {
+		return nil, false, false
+	}
+
+	for _, obj := range objects {
+		id := objectID(obj)
+		if id == "" || before[id] {
+			continue
+		}
+		if got, ok := obj[key].(string); ok && strings.EqualFold(got, value) {
+			tflog.Info(req.Context(), "UniFi create failed but the object was created, not creating it again", map[string]any{
+				"url": req.URL.Redacted(),
+				"id":  id,
+				key:   value,
+			})
+
+			var payload any = obj
+			if isV1Collection(req) {
+				payload = map[string]any{"meta": map[string]any{"rc": "ok"}, "data": []any{obj}}
+			}
+			out, err := json.Marshal(payload)
+			if err != nil {
+				return nil, false, false
+			}
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         "HTTP/1.1",
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        http.Header{"Content-Type": []string{"application/json"}},
+				Body:          io.NopCloser(bytes.NewReader(out)),
+				ContentLength: int64(len(out)),
+				Request:       req,
+			}, true, true
+		}
+	}
+
+	return nil, false, true
+}
+
+// listCollection lists the collection a create request is sent to.
+func (t *retryTransport) listCollection(req *http.Request) ([]map[string]any, bool) {
+	list := req.Clone(req.Context())
+	list.Method = http.MethodGet
+	list.Body = nil
+	list.GetBody = nil
+	list.ContentLength = 0
+
+	a := t.do(list, nil)
+	if a.err != nil {
+		return nil, false
+	}
+	defer closeBody(a.resp)
+	if a.resp.StatusCode != http.StatusOK {
+		return nil, false
+	}
+
+	raw, err := io.ReadAll(a.resp.Body)
+	if err != nil {
+		return nil, false
+	}
+
+	var objects []map[string]any
+	if isV1Collection(req) {
+		var envelope struct {
+			Data []map[string]any `json:"data"`
+		}
+		err = json.Unmarshal(raw, &envelope)
+		objects = envelope.Data
+	} else {
+		err = json.Unmarshal(raw, &objects)
+	}
+	if err != nil {
+		return nil, false
+	}
+	return objects, true
+}
+
+// isCollectionCreate reports whether req creates an object in a REST or v2
+// collection.
+func isCollectionCreate(req *http.Request) bool {
+	return req.Method == http.MethodPost &&
+		(isV1Collection(req) || strings.Contains(req.URL.Path, "/v2/api/site/"))
+}
+
+func isV1Collection(req *http.Request) bool {
+	return strings.Contains(req.URL.Path, "/api/s/") && strings.Contains(req.URL.Path, "/rest/")
+}
+
+// objectID returns the controller ID of a listed object.
+func objectID(obj map[string]any) string {
+	for _, key := range []string{"_id", "id"} {
+		if v, ok := obj[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// createIdentity returns the attribute that identifies the object in a create
+// request, if it has one.
+func createIdentity(obj map[string]any) (string, string) {
+	for _, key := range []string{"name", "mac"} {
+		if v, ok := obj[key].(string); ok && v != "" {
+			return key, v
+		}
+	}
+	return "", ""
+}
+
+// requestBody reads the request body so it can be sent more than once.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	rc := req.Body
+	if req.GetBody != nil {
+		var err error
+		if rc, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// isTLSVerificationError reports whether err is a TLS handshake that failed
+// because a certificate was rejected, by us or by the controller. Retrying
+// cannot change the outcome.
+func isTLSVerificationError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var alert tls.AlertError
+	return errors.As(err, &certErr) || errors.As(err, &alert) || errors.Is(err, errPinMismatch)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	// Logging in again has no side effects beyond issuing a new session.
+	return isLoginRequest(req)
+}
+
+func isLoginRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/api/login") || strings.HasSuffix(req.URL.Path, "/api/auth/login")
+}
+
+func isLogoutRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/api/logout") || strings.HasSuffix(req.URL.Path, "/api/auth/logout")
+}
+
+func describeAttempt(a *attempt) string {
+	if a.err != nil {
+		return a.err.Error()
+	}
+	return a.resp.Status
+}
+
+// bufferBody reads a response body into memory and closes it, so the
+// connection and any rate limiter slot are released while the response may
+// still be returned. An error reading the body is returned by the new body.
+func bufferBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	var r io.Reader = bytes.NewReader(b)
+	if err != nil {
+		r = io.MultiReader(r, errReader{err})
+	}
+	resp.Body = io.NopCloser(r)
+}
+
+// errReader is a reader that fails with err.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// closeBody drains and closes a response body so the connection, and any rate
+// limiter slot, is released.
+func closeBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
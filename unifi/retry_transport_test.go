@@ -0,0 +1,444 @@
+package unifi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func testRetryClient(t *testing.T, cfg retryConfig) *http.Client {
+	t.Helper()
+	if cfg.Backoff == 0 {
+		cfg.Backoff = time.Millisecond
+	}
+	if cfg.StatusCodes == nil {
+		cfg.StatusCodes = defaultRetryStatusCodes
+	}
+	c, err := newHTTPClient(transportConfig{AllowInsecure: true, Retry: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func Test_newRetryTransport_disabled(t *testing.T) {
+	next := http.DefaultTransport
+	if got := newRetryTransport(next, nil, retryConfig{MaxAttempts: 1}); got != next {
+		t.Errorf("expected the transport to be returned unchanged, got %T", got)
+	}
+}
+
+func Test_retryTransport_idempotent(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int64
+		maxAttempts  int64
+		wantStatus   int
+		wantAttempts int64
+	}{
+		{"succeeds after transient failures", 2, 3, http.StatusOK, 3},
+		{"gives up after max attempts", 5, 3, http.StatusServiceUnavailable, 3},
+		{"retries disabled", 1, 1, http.StatusServiceUnavailable, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"name":"x"}` {
+					t.Errorf("attempt %d body = %q", calls.Load()+1, body)
+				}
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+
+			c := testRetryClient(t, retryConfig{MaxAttempts: tt.maxAttempts})
+			req, _ := http.NewRequest(http.MethodPut, srv.URL+"/api/s/default/rest/networkconf/1", strings.NewReader(`{"name":"x"}`))
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := calls.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_retryTransport_create(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		status     int
+		before     string
+		after      string
+		wantStatus int
+		wantPosts  int64
+		wantLists  int64
+	}{
+		{
+			name:       "v1 object created despite the error is returned",
+			path:       "/api/s/default/rest/networkconf",
+			body:       `{"name":"iot"}`,
+			status:     http.StatusBadGateway,
+			before:     `{"meta":{"rc":"ok"},"data":[{"_id":"0","name":"lan"}]}`,
+			after:      `{"meta":{"rc":"ok"},"data":[{"_id":"0","name":"lan"},{"_id":"1","name":"iot"}]}`,
+			wantStatus: http.StatusOK,
+			wantPosts:  1,
+			wantLists:  2,
+		},
+		{
+			name:       "v2 object created despite the error is returned",
+			path:       "/v2/api/site/default/trafficroutes",
+			body:       `{"name":"iot"}`,
+			status:     http.StatusBadGateway,
+			before:     `[]`,
+			after:      `[{"_id":"1","name":"iot"}]`,
+			wantStatus: http.StatusOK,
+			wantPosts:  1,
+			wantLists:  2,
+		},
+		{
+			name:       "object matched by MAC",
+			path:       "/api/s/default/rest/user",
+			body:       `{"mac":"AA:BB:CC:DD:EE:FF"}`,
+			status:     http.StatusGatewayTimeout,
+			before:     `{"meta":{"rc":"ok"},"data":[]}`,
+			after:      `{"meta":{"rc":"ok"},"data":[{"_id":"1","mac":"aa:bb:cc:dd:ee:ff"}]}`,
+			wantStatus: http.StatusOK,
+			wantPosts:  1,
+			wantLists:  2,
+		},
+		{
+			name:       "absent object is created again",
+			path:       "/api/s/default/rest/networkconf",
+			body:       `{"name":"iot"}`,
+			status:     http.StatusBadGateway,
+			before:     `{"meta":{"rc":"ok"},"data":[]}`,
+			after:      `{"meta":{"rc":"ok"},"data":[{"_id":"1","name":"other"}]}`,
+			wantStatus: http.StatusOK,
+			wantPosts:  2,
+			wantLists:  2,
+		},
+		{
+			name:       "object that existed before the create is not claimed",
+			path:       "/api/s/default/rest/networkconf",
+			body:       `{"name":"iot"}`,
+			status:     http.StatusBadGateway,
+			before:     `{"meta":{"rc":"ok"},"data":[{"_id":"1","name":"iot"}]}`,
+			after:      `{"meta":{"rc":"ok"},"data":[{"_id":"1","name":"iot"}]}`,
+			wantStatus: http.StatusOK,
+			wantPosts:  2,
+			wantLists:  2,
+		},
+		{
+			name:       "create is not replayed when the collection cannot be listed first",
+			path:       "/api/s/default/rest/networkconf",
+			body:       `{"name":"iot"}`,
+			status:     http.StatusBadGateway,
+			wantStatus: http.StatusBadGateway,
+			wantPosts:  1,
+			wantLists:  1,
+		},
+		{
+			name:       "429 is replayed without a lookup",
+			path:       "/api/s/default/rest/networkconf",
+			body:       `{"name":"iot"}`,
+			status:     http.StatusTooManyRequests,
+			before:     `{"meta":{"rc":"ok"},"data":[]}`,
+			wantStatus: http.StatusOK,
+			wantPosts:  2,
+			wantLists:  1,
+		},
+		{
+			name:       "command without identity is not replayed",
+			path:       "/api/s/default/cmd/devmgr",
+			body:       `{"cmd":"restart","mac":"aa:bb:cc:dd:ee:ff"}`,
+			status:     http.StatusBadGateway,
+			wantStatus: http.StatusBadGateway,
+			wantPosts:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posts, lists atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					lists.Add(1)
+					listing := tt.after
+					if posts.Load() == 0 {
+						listing = tt.before
+					}
+					if listing == "" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					_, _ = io.WriteString(w, listing)
+				case http.MethodPost:
+					if posts.Add(1) == 1 {
+						w.WriteHeader(tt.status)
+						return
+					}
+					_, _ = io.WriteString(w, `{"meta":{"rc":"ok"},"data":[]}`)
+				}
+			}))
+			defer srv.Close()
+
+			c := testRetryClient(t, retryConfig{MaxAttempts: 3})
+			resp, err := c.Post(srv.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := posts.Load(); got != tt.wantPosts {
+				t.Errorf("POSTs = %d, want %d", got, tt.wantPosts)
+			}
+			if got := lists.Load(); got != tt.wantLists {
+				t.Errorf("lists = %d, want %d", got, tt.wantLists)
+			}
+
+			if tt.wantPosts == 1 && tt.wantStatus == http.StatusOK {
+				raw, _ := io.ReadAll(resp.Body)
+				if !strings.Contains(string(raw), `"_id":"1"`) {
+					t.Errorf("body = %s, want the created object", raw)
+				}
+			}
+		})
+	}
+}
+
+func Test_retryTransport_decide(t *testing.T) {
+	rt := &retryTransport{cfg: retryConfig{StatusCodes: defaultRetryStatusCodes}}
+
+	wrote := func(b bool) *attempt {
+		a := &attempt{err: errors.New("connection reset by peer")}
+		a.wrote.Store(b)
+		return a
+	}
+	status := func(code int) *attempt {
+		return &attempt{resp: &http.Response{StatusCode: code}}
+	}
+	failed := func(err error) *attempt {
+		a := &attempt{err: &url.Error{Op: "Get", URL: "https://unifi", Err: err}}
+		a.wrote.Store(false)
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		a      *attempt
+		want   retryDecision
+	}{
+		{"GET connection error", http.MethodGet, "/api/s/default/rest/networkconf", wrote(true), retryYes},
+		{"POST not written", http.MethodPost, "/api/s/default/rest/networkconf", wrote(false), retryYes},
+		{"POST written", http.MethodPost, "/api/s/default/rest/networkconf", wrote(true), retryIfAbsent},
+		{"login written", http.MethodPost, "/api/auth/login", wrote(true), retryYes},
+		{"DELETE 502", http.MethodDelete, "/api/s/default/rest/networkconf/1", status(502), retryYes},
+		{"POST 503", http.MethodPost, "/api/s/default/rest/networkconf", status(503), retryIfAbsent},
+		{"POST 429", http.MethodPost, "/api/s/default/rest/networkconf", status(429), retryYes},
+		{"GET 500", http.MethodGet, "/api/s/default/rest/networkconf", status(500), retryNo},
+		{"GET 400", http.MethodGet, "/api/s/default/rest/networkconf", status(400), retryNo},
+		{
+			"pinned certificate mismatch",
+			http.MethodGet,
+			"/api/self",
+			failed(fmt.Errorf("%w: server certificate fingerprint is 00", errPinMismatch)),
+			retryNo,
+		},
+		{
+			"untrusted certificate",
+			http.MethodGet,
+			"/api/self",
+			failed(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}),
+			retryNo,
+		},
+		{"client certificate rejected", http.MethodGet, "/api/self", failed(tls.AlertError(42)), retryNo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "https://unifi"+tt.path, nil)
+			if got := rt.decide(req, tt.a); got != tt.want {
+				t.Errorf("decide() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, "https://unifi/api/self", nil).WithContext(ctx)
+		if got := rt.decide(req, status(503)); got != retryNo {
+			t.Errorf("decide() = %d, want %d", got, retryNo)
+		}
+	})
+}
+
+func Test_retryTransport_renewsSession(t *testing.T) {
+	srv := fakecontroller.New()
+	defer srv.Close()
+
+	c := testRetryClient(t, retryConfig{
+		MaxAttempts: 1,
+		Username:    fakecontroller.DefaultUsername,
+		Password:    fakecontroller.DefaultPassword,
+	})
+
+	payload, _ := json.Marshal(map[string]string{
+		"username": fakecontroller.DefaultUsername,
+		"password": fakecontroller.DefaultPassword,
+	})
+	resp, err := c.Post(srv.URL+"/api/login", "application/json", strings.NewReader(string(payload)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login status = %d", resp.StatusCode)
+	}
+
+	srv.ExpireSessions()
+
+	resp, err = c.Get(srv.URL + "/api/s/default/rest/networkconf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status after session expiry = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	t.Run("without credentials", func(t *testing.T) {
+		c := testRetryClient(t, retryConfig{MaxAttempts: 2})
+		resp, err := c.Get(srv.URL + "/api/s/default/rest/networkconf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+		}
+	})
+}
+
+// The login after an expired session and the lookup after an ambiguous create
+// each need a rate limiter slot while the failed response is still in hand.
+func Test_retryTransport_rateLimited(t *testing.T) {
+	newClient := func(t *testing.T, cfg retryConfig) *http.Client {
+		t.Helper()
+		cfg.Backoff = time.Millisecond
+		cfg.StatusCodes = defaultRetryStatusCodes
+		c, err := newHTTPClient(transportConfig{AllowInsecure: true, MaxConcurrentRequests: 1, Retry: cfg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("session renewal", func(t *testing.T) {
+		srv := fakecontroller.New()
+		defer srv.Close()
+
+		c := newClient(t, retryConfig{
+			MaxAttempts: 1,
+			Username:    fakecontroller.DefaultUsername,
+			Password:    fakecontroller.DefaultPassword,
+		})
+		srv.ExpireSessions()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/s/default/rest/networkconf", nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("ambiguous create", func(t *testing.T) {
+		var posts atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				posts.Add(1)
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			if posts.Load() == 0 {
+				_, _ = io.WriteString(w, `{"meta":{"rc":"ok"},"data":[]}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"meta":{"rc":"ok"},"data":[{"_id":"1","name":"iot"}]}`)
+		}))
+		defer srv.Close()
+
+		c := newClient(t, retryConfig{MaxAttempts: 3})
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(
+			ctx,
+			http.MethodPost,
+			srv.URL+"/api/s/default/rest/networkconf",
+			strings.NewReader(`{"name":"iot"}`),
+		)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if got := posts.Load(); got != 1 {
+			t.Errorf("POSTs = %d, want 1", got)
+		}
+	})
+
+	t.Run("failed response still readable", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, "restarting")
+		}))
+		defer srv.Close()
+
+		c := newClient(t, retryConfig{MaxAttempts: 2})
+		resp, err := c.Get(srv.URL + "/api/s/default/rest/networkconf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if body, _ := io.ReadAll(resp.Body); string(body) != "restarting" {
+			t.Errorf("body = %q, want %q", body, "restarting")
+		}
+	})
+}
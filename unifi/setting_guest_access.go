@@ -0,0 +1,343 @@
+package unifi
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/cidrtypes"
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/iptypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// settingGuestAccessModel is the nested guest_access block. Like mgmt, only
+// the attributes set in the plan are written and read back, so portal options
+// managed in the UI do not show up as drift.
+type settingGuestAccessModel struct {
+	Auth             types.String        `tfsdk:"auth"`
+	PortalEnabled    types.Bool          `tfsdk:"portal_enabled"`
+	ExternalPortalIP iptypes.IPv4Address `tfsdk:"external_portal_ip"`
+	ExpireMinutes    types.Int64         `tfsdk:"expire_minutes"`
+	VoucherEnabled   types.Bool          `tfsdk:"voucher_enabled"`
+	PasswordEnabled  types.Bool          `tfsdk:"password_enabled"`
+	Password         types.String        `tfsdk:"password"`
+	PaymentEnabled   types.Bool          `tfsdk:"payment_enabled"`
+	PaymentGateway   types.String        `tfsdk:"payment_gateway"`
+	RedirectURL      types.String        `tfsdk:"redirect_url"`
+	TermsOfService   types.String        `tfsdk:"terms_of_service"`
+	AllowedSubnets   types.List          `tfsdk:"allowed_subnets"`
+	PortalHostname   types.String        `tfsdk:"portal_hostname"`
+	Customization    types.Object        `tfsdk:"customization"`
+}
+
+type settingGuestPortalCustomizationModel struct {
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	Title           types.String `tfsdk:"title"`
+	WelcomeText     types.String `tfsdk:"welcome_text"`
+	SuccessText     types.String `tfsdk:"success_text"`
+	ButtonText      types.String `tfsdk:"button_text"`
+	BackgroundColor types.String `tfsdk:"background_color"`
+	BoxColor        types.String `tfsdk:"box_color"`
+	TextColor       types.String `tfsdk:"text_color"`
+	LinkColor       types.String `tfsdk:"link_color"`
+	ButtonColor     types.String `tfsdk:"button_color"`
+	ButtonTextColor types.String `tfsdk:"button_text_color"`
+}
+
+var (
+	guestPortalCustomizationAttrTypes = map[string]attr.Type{
+		"enabled":           types.BoolType,
+		"title":             types.StringType,
+		"welcome_text":      types.StringType,
+		"success_text":      types.StringType,
+		"button_text":       types.StringType,
+		"background_color":  types.StringType,
+		"box_color":         types.StringType,
+		"text_color":        types.StringType,
+		"link_color":        types.StringType,
+		"button_color":      types.StringType,
+		"button_text_color": types.StringType,
+	}
+	guestAccessAttrTypes = map[string]attr.Type{
+		"auth":               types.StringType,
+		"portal_enabled":     types.BoolType,
+		"external_portal_ip": iptypes.IPv4AddressType{},
+		"expire_minutes":     types.Int64Type,
+		"voucher_enabled":    types.BoolType,
+		"password_enabled":   types.BoolType,
+		"password":           types.StringType,
+		"payment_enabled":    types.BoolType,
+		"payment_gateway":    types.StringType,
+		"redirect_url":       types.StringType,
+		"terms_of_service":   types.StringType,
+		"allowed_subnets":    types.ListType{ElemType: cidrtypes.IPv4PrefixType{}},
+		"portal_hostname":    types.StringType,
+		"customization":      types.ObjectType{AttrTypes: guestPortalCustomizationAttrTypes},
+	}
+)
+
+var guestPortalColorValidator = stringvalidator.RegexMatches(
+	regexp.MustCompile(`^#[0-9a-fA-F]{6}$`),
+	"must be a color in #rrggbb form",
+)
+
+// guestPortalCustomizationKeys maps the customization attributes that are
+// plain strings to their guest_access keys.
+var guestPortalCustomizationKeys = []struct {
+	key   string
+	value func(*settingGuestPortalCustomizationModel) *types.String
+}{
+	{"portal_customized_title", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.Title }},
+	{"portal_customized_success_text", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.SuccessText }},
+	{"portal_customized_button_text", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.ButtonText }},
+	{"portal_customized_bg_color", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.BackgroundColor }},
+	{"portal_customized_box_color", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.BoxColor }},
+	{"portal_customized_text_color", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.TextColor }},
+	{"portal_customized_link_color", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.LinkColor }},
+	{"portal_customized_button_color", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.ButtonColor }},
+	{"portal_customized_button_text_color", func(m *settingGuestPortalCustomizationModel) *types.String { return &m.ButtonTextColor }},
+}
+
+// allowedSubnetPrefix is the prefix of the numbered allowed_subnet_N keys that
+// hold the pre-authorization subnets.
+const allowedSubnetPrefix = "allowed_subnet_"
+
+// guestAccessModelToSetting overlays the configured attributes onto the
+// current remote setting (base). The setting is handled as a raw object
+// rather than a typed struct, so the payment gateway credentials and the many
+// portal options this block does not cover go back to the controller
+// unchanged, and the numbered allowed_subnet_N keys can be addressed one by
+// one.
+func (r *settingResource) guestAccessModelToSetting(
+	ctx context.Context,
+	model *settingGuestAccessModel,
+	base map[string]any,
+	diags *diag.Diagnostics,
+) map[string]any {
+	setting := base
+
+	setString := func(key string, v types.String) {
+		if !v.IsNull() && !v.IsUnknown() {
+			setting[key] = v.ValueString()
+		}
+	}
+	setBool := func(key string, v types.Bool) {
+		if !v.IsNull() && !v.IsUnknown() {
+			setting[key] = v.ValueBool()
+		}
+	}
+	// setToggled sets key to v, and toggle to whether v is non-empty.
+	setToggled := func(toggle, key string, v types.String) {
+		if !v.IsNull() && !v.IsUnknown() {
+			setting[toggle] = v.ValueString() != ""
+			if v.ValueString() != "" {
+				setting[key] = v.ValueString()
+			}
+		}
+	}
+
+	setString("auth", model.Auth)
+	setBool("portal_enabled", model.PortalEnabled)
+	if !model.ExternalPortalIP.IsNull() && !model.ExternalPortalIP.IsUnknown() {
+		setting["custom_ip"] = model.ExternalPortalIP.ValueString()
+	}
+	if !model.ExpireMinutes.IsNull() && !model.ExpireMinutes.IsUnknown() {
+		// The UI stores the duration as number and unit as well; minutes keep
+		// it exact.
+		setting["expire"] = model.ExpireMinutes.ValueInt64()
+		setting["expire_number"] = model.ExpireMinutes.ValueInt64()
+		setting["expire_unit"] = 1
+	}
+	setBool("voucher_enabled", model.VoucherEnabled)
+	setBool("password_enabled", model.PasswordEnabled)
+	setString("x_password", model.Password)
+	setBool("payment_enabled", model.PaymentEnabled)
+	setString("gateway", model.PaymentGateway)
+	setToggled("redirect_enabled", "redirect_url", model.RedirectURL)
+	setToggled("portal_customized_tos_enabled", "portal_customized_tos", model.TermsOfService)
+	setToggled("portal_use_hostname", "portal_hostname", model.PortalHostname)
+
+	if !model.AllowedSubnets.IsNull() && !model.AllowedSubnets.IsUnknown() {
+		var subnets []string
+		diags.Append(model.AllowedSubnets.ElementsAs(ctx, &subnets, false)...)
+		// Blank the slots that are no longer used; the controller keeps keys
+		// that are left out.
+		for _, key := range allowedSubnetKeys(setting) {
+			setting[key] = ""
+		}
+		for i, subnet := range subnets {
+			setting[allowedSubnetPrefix+strconv.Itoa(i+1)] = subnet
+		}
+	}
+
+	if !model.Customization.IsNull() && !model.Customization.IsUnknown() {
+		var custom settingGuestPortalCustomizationModel
+		diags.Append(model.Customization.As(ctx, &custom, basetypes.ObjectAsOptions{})...)
+		setBool("portal_customized", custom.Enabled)
+		setToggled("portal_customized_welcome_text_enabled", "portal_customized_welcome_text", custom.WelcomeText)
+		for _, k := range guestPortalCustomizationKeys {
+			setString(k.key, *k.value(&custom))
+		}
+	}
+
+	return setting
+}
+
+// guestAccessSettingToModel reads the attributes configured in plan from the
+// remote setting; the others stay null.
+func (r *settingResource) guestAccessSettingToModel(
+	ctx context.Context,
+	setting map[string]any,
+	plan *settingGuestAccessModel,
+	diags *diag.Diagnostics,
+) *settingGuestAccessModel {
+	model := &settingGuestAccessModel{}
+
+	str := func(key string) string {
+		s, _ := setting[key].(string)
+		return s
+	}
+	boolean := func(key string) bool {
+		b, _ := setting[key].(bool)
+		return b
+	}
+	stringOrNull := func(planVal types.String, key string) types.String {
+		if !planVal.IsNull() && !planVal.IsUnknown() {
+			return types.StringValue(str(key))
+		}
+		return types.StringNull()
+	}
+	boolOrNull := func(planVal types.Bool, key string) types.Bool {
+		if !planVal.IsNull() && !planVal.IsUnknown() {
+			return types.BoolValue(boolean(key))
+		}
+		return types.BoolNull()
+	}
+	// toggledOrNull reads key, or "" when toggle is off.
+	toggledOrNull := func(planVal types.String, toggle, key string) types.String {
+		if !planVal.IsNull() && !planVal.IsUnknown() {
+			if !boolean(toggle) {
+				return types.StringValue("")
+			}
+			return types.StringValue(str(key))
+		}
+		return types.StringNull()
+	}
+
+	model.Auth = stringOrNull(plan.Auth, "auth")
+	model.PortalEnabled = boolOrNull(plan.PortalEnabled, "portal_enabled")
+	model.ExternalPortalIP = iptypes.NewIPv4AddressNull()
+	if !plan.ExternalPortalIP.IsNull() && !plan.ExternalPortalIP.IsUnknown() && str("custom_ip") != "" {
+		model.ExternalPortalIP = iptypes.NewIPv4AddressValue(str("custom_ip"))
+	}
+	model.ExpireMinutes = types.Int64Null()
+	if !plan.ExpireMinutes.IsNull() && !plan.ExpireMinutes.IsUnknown() {
+		expire, _ := setting["expire"].(float64)
+		model.ExpireMinutes = types.Int64Value(int64(expire))
+	}
+	model.VoucherEnabled = boolOrNull(plan.VoucherEnabled, "voucher_enabled")
+	model.PasswordEnabled = boolOrNull(plan.PasswordEnabled, "password_enabled")
+	model.Password = stringOrNull(plan.Password, "x_password")
+	model.PaymentEnabled = boolOrNull(plan.PaymentEnabled, "payment_enabled")
+	model.PaymentGateway = stringOrNull(plan.PaymentGateway, "gateway")
+	model.RedirectURL = toggledOrNull(plan.RedirectURL, "redirect_enabled", "redirect_url")
+	model.TermsOfService = toggledOrNull(plan.TermsOfService, "portal_customized_tos_enabled", "portal_customized_tos")
+	model.PortalHostname = toggledOrNull(plan.PortalHostname, "portal_use_hostname", "portal_hostname")
+
+	model.AllowedSubnets = types.ListNull(cidrtypes.IPv4PrefixType{})
+	if !plan.AllowedSubnets.IsNull() && !plan.AllowedSubnets.IsUnknown() {
+		subnets := make([]attr.Value, 0)
+		for _, key := range allowedSubnetKeys(setting) {
+			if s := str(key); s != "" {
+				subnets = append(subnets, cidrtypes.NewIPv4PrefixValue(s))
+			}
+		}
+		value, d := types.ListValue(cidrtypes.IPv4PrefixType{}, subnets)
+		diags.Append(d...)
+		model.AllowedSubnets = value
+	}
+
+	model.Customization = types.ObjectNull(guestPortalCustomizationAttrTypes)
+	if !plan.Customization.IsNull() && !plan.Customization.IsUnknown() {
+		var planCustom settingGuestPortalCustomizationModel
+		diags.Append(plan.Customization.As(ctx, &planCustom, basetypes.ObjectAsOptions{})...)
+
+		custom := settingGuestPortalCustomizationModel{
+			Enabled: boolOrNull(planCustom.Enabled, "portal_customized"),
+			WelcomeText: toggledOrNull(
+				planCustom.WelcomeText,
+				"portal_customized_welcome_text_enabled",
+				"portal_customized_welcome_text",
+			),
+		}
+		for _, k := range guestPortalCustomizationKeys {
+			*k.value(&custom) = stringOrNull(*k.value(&planCustom), k.key)
+		}
+		obj, d := types.ObjectValueFrom(ctx, guestPortalCustomizationAttrTypes, custom)
+		diags.Append(d...)
+		model.Customization = obj
+	}
+
+	return model
+}
+
+// allowedSubnetKeys returns the allowed_subnet_N keys of setting in order of
+// N.
+func allowedSubnetKeys(setting map[string]any) []string {
+	index := make(map[string]int)
+	for key := range setting {
+		if n, ok := strings.CutPrefix(key, allowedSubnetPrefix); ok {
+			if i, err := strconv.Atoi(n); err == nil {
+				index[key] = i
+			}
+		}
+	}
+	keys := slices.Collect(maps.Keys(index))
+	slices.SortFunc(keys, func(a, b string) int { return cmp.Compare(index[a], index[b]) })
+	return keys
+}
+
+// getGuestAccessSetting returns the site's guest_access setting as the raw
+// object the controller stores. go-unifi's typed setting is not used: it
+// drops the keys it does not know, such as newer portal options, and leaves
+// out empty ones, so blanked allowed_subnet_N slots would never be sent. The
+// setting is therefore read and written with controllerRequest, which is not
+// available with Cloud Connector. A site whose setting was never saved gets an
+// empty one.
+func (c *Client) getGuestAccessSetting(ctx context.Context, site string) (map[string]any, error) {
+	var out []map[string]any
+	err := c.controllerRequest(
+		ctx,
+		http.MethodGet,
+		"/api/s/"+url.PathEscape(site)+"/get/setting/guest_access",
+		nil,
+		&out,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return map[string]any{"key": "guest_access"}, nil
+	}
+	return out[0], nil
+}
+
+// updateGuestAccessSetting saves setting, as returned by
+// getGuestAccessSetting and then modified, with every key it holds.
+func (c *Client) updateGuestAccessSetting(ctx context.Context, site string, setting map[string]any) error {
+	path := "/api/s/" + url.PathEscape(site) + "/set/setting/guest_access"
+	if id, _ := setting["_id"].(string); id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return c.controllerRequest(ctx, http.MethodPut, path, setting, nil)
+}
@@ -0,0 +1,399 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/cidrtypes"
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/iptypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSettingResource_guestAccess(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSettingConfig_guestAccess(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("unifi_setting.test", "guest_access.auth", "hotspot"),
+					resource.TestCheckResourceAttr("unifi_setting.test", "guest_access.voucher_enabled", "true"),
+					resource.TestCheckResourceAttr(
+						"unifi_setting.test",
+						"guest_access.redirect_url",
+						"https://hotel.example.com/welcome",
+					),
+					resource.TestCheckResourceAttr("unifi_setting.test", "guest_access.allowed_subnets.#", "2"),
+					resource.TestCheckResourceAttr(
+						"unifi_setting.test",
+						"guest_access.customization.title",
+						"Hotel Wi-Fi",
+					),
+				),
+			},
+			{
+				Config: testAccSettingConfig_guestAccessUpdate(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("unifi_setting.test", "guest_access.redirect_url", ""),
+					resource.TestCheckResourceAttr("unifi_setting.test", "guest_access.allowed_subnets.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSettingConfig_guestAccess() string {
+	return `
+resource "unifi_setting" "test" {
+  guest_access = {
+    auth             = "hotspot"
+    portal_enabled   = true
+    voucher_enabled  = true
+    redirect_url     = "https://hotel.example.com/welcome"
+    terms_of_service = "Be nice."
+    allowed_subnets  = ["192.0.2.0/24", "198.51.100.0/24"]
+
+    customization = {
+      enabled          = true
+      title            = "Hotel Wi-Fi"
+      background_color = "#1d3557"
+    }
+  }
+}
+`
+}
+
+func testAccSettingConfig_guestAccessUpdate() string {
+	return `
+resource "unifi_setting" "test" {
+  guest_access = {
+    auth             = "hotspot"
+    portal_enabled   = true
+    voucher_enabled  = true
+    redirect_url     = ""
+    terms_of_service = "Be nice."
+    allowed_subnets  = ["192.0.2.0/24"]
+
+    customization = {
+      enabled          = true
+      title            = "Hotel Wi-Fi"
+      background_color = "#1d3557"
+    }
+  }
+}
+`
+}
+
+// nullGuestAccessModel returns a guest_access model with every attribute
+// unset.
+func nullGuestAccessModel() *settingGuestAccessModel {
+	return &settingGuestAccessModel{
+		Auth:             types.StringNull(),
+		PortalEnabled:    types.BoolNull(),
+		ExternalPortalIP: iptypes.NewIPv4AddressNull(),
+		ExpireMinutes:    types.Int64Null(),
+		VoucherEnabled:   types.BoolNull(),
+		PasswordEnabled:  types.BoolNull(),
+		Password:         types.StringNull(),
+		PaymentEnabled:   types.BoolNull(),
+		PaymentGateway:   types.StringNull(),
+		RedirectURL:      types.StringNull(),
+		TermsOfService:   types.StringNull(),
+		AllowedSubnets:   types.ListNull(cidrtypes.IPv4PrefixType{}),
+		PortalHostname:   types.StringNull(),
+		Customization:    types.ObjectNull(guestPortalCustomizationAttrTypes),
+	}
+}
+
+func Test_settingResource_guestAccessModelToSetting(t *testing.T) {
+	r := &settingResource{}
+	ctx := context.Background()
+
+	t.Run("configured fields overlaid onto base", func(t *testing.T) {
+		base := map[string]any{
+			"_id":              "ga1",
+			"key":              "guest_access",
+			"auth":             "none",
+			"x_stripe_api_key": "sk_live_secret",
+			"redirect_enabled": true,
+			"redirect_url":     "https://old.example.com",
+		}
+		model := nullGuestAccessModel()
+		model.Auth = types.StringValue("hotspot")
+		model.VoucherEnabled = types.BoolValue(true)
+		model.RedirectURL = types.StringValue("")
+		model.ExpireMinutes = types.Int64Value(480)
+
+		var diags diag.Diagnostics
+		got := r.guestAccessModelToSetting(ctx, model, base, &diags)
+		if diags.HasError() {
+			t.Fatalf("unexpected diags: %v", diags)
+		}
+		if got["auth"] != "hotspot" {
+			t.Errorf("auth = %v, want hotspot", got["auth"])
+		}
+		if got["voucher_enabled"] != true {
+			t.Errorf("voucher_enabled = %v, want true", got["voucher_enabled"])
+		}
+		if got["redirect_enabled"] != false {
+			t.Errorf("redirect_enabled = %v, want false", got["redirect_enabled"])
+		}
+		if got["expire"] != int64(480) || got["expire_unit"] != 1 {
+			t.Errorf("expire = %v, expire_unit = %v, want 480 minutes", got["expire"], got["expire_unit"])
+		}
+		// Fields the block does not manage must be preserved.
+		if got["x_stripe_api_key"] != "sk_live_secret" {
+			t.Errorf("x_stripe_api_key = %v, want it preserved", got["x_stripe_api_key"])
+		}
+		if _, ok := got["password_enabled"]; ok {
+			t.Error("password_enabled should not be set when unconfigured")
+		}
+	})
+
+	t.Run("allowed subnets replace the numbered keys", func(t *testing.T) {
+		base := map[string]any{
+			"allowed_subnet_1": "10.0.0.0/8",
+			"allowed_subnet_2": "172.16.0.0/12",
+			"allowed_subnet_3": "192.168.0.0/16",
+		}
+		subnets, d := types.ListValueFrom(ctx, cidrtypes.IPv4PrefixType{}, []string{"192.0.2.0/24"})
+		if d.HasError() {
+			t.Fatalf("building list: %v", d)
+		}
+		model := nullGuestAccessModel()
+		model.AllowedSubnets = subnets
+
+		var diags diag.Diagnostics
+		got := r.guestAccessModelToSetting(ctx, model, base, &diags)
+		if diags.HasError() {
+			t.Fatalf("unexpected diags: %v", diags)
+		}
+		want := map[string]any{
+			"allowed_subnet_1": "192.0.2.0/24",
+			"allowed_subnet_2": "",
+			"allowed_subnet_3": "",
+		}
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("%s = %v, want %q", k, got[k], v)
+			}
+		}
+	})
+}
+
+func Test_settingResource_guestAccessSettingToModel(t *testing.T) {
+	r := &settingResource{}
+	ctx := context.Background()
+
+	setting := map[string]any{
+		"auth":                          "hotspot",
+		"voucher_enabled":               true,
+		"redirect_enabled":              false,
+		"redirect_url":                  "https://stale.example.com",
+		"portal_customized_tos_enabled": true,
+		"portal_customized_tos":         "Be nice.",
+		"expire":                        float64(1440),
+		"allowed_subnet_10":             "203.0.113.0/24",
+		"allowed_subnet_2":              "198.51.100.0/24",
+		"allowed_subnet_1":              "192.0.2.0/24",
+		"allowed_subnet_3":              "",
+		"portal_customized":             true,
+		"portal_customized_title":       "Hotel Wi-Fi",
+	}
+
+	plan := nullGuestAccessModel()
+	plan.Auth = types.StringValue("none")
+	plan.VoucherEnabled = types.BoolValue(false)
+	plan.RedirectURL = types.StringValue("https://hotel.example.com")
+	plan.TermsOfService = types.StringValue("")
+	plan.ExpireMinutes = types.Int64Value(60)
+	plan.AllowedSubnets = types.ListValueMust(cidrtypes.IPv4PrefixType{}, []attr.Value{})
+	plan.Customization = types.ObjectValueMust(guestPortalCustomizationAttrTypes, map[string]attr.Value{
+		"enabled":           types.BoolValue(false),
+		"title":             types.StringValue("Old"),
+		"welcome_text":      types.StringNull(),
+		"success_text":      types.StringNull(),
+		"button_text":       types.StringNull(),
+		"background_color":  types.StringNull(),
+		"box_color":         types.StringNull(),
+		"text_color":        types.StringNull(),
+		"link_color":        types.StringNull(),
+		"button_color":      types.StringNull(),
+		"button_text_color": types.StringNull(),
+	})
+
+	var diags diag.Diagnostics
+	got := r.guestAccessSettingToModel(ctx, setting, plan, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+
+	if got.Auth.ValueString() != "hotspot" {
+		t.Errorf("Auth = %q, want hotspot", got.Auth.ValueString())
+	}
+	if !got.VoucherEnabled.ValueBool() {
+		t.Error("VoucherEnabled should be true")
+	}
+	if got.RedirectURL.ValueString() != "" {
+		t.Errorf("RedirectURL = %q, want empty while redirect is disabled", got.RedirectURL.ValueString())
+	}
+	if got.TermsOfService.ValueString() != "Be nice." {
+		t.Errorf("TermsOfService = %q, want %q", got.TermsOfService.ValueString(), "Be nice.")
+	}
+	if got.ExpireMinutes.ValueInt64() != 1440 {
+		t.Errorf("ExpireMinutes = %d, want 1440", got.ExpireMinutes.ValueInt64())
+	}
+	if !got.PasswordEnabled.IsNull() || !got.Password.IsNull() {
+		t.Error("unconfigured attributes should stay null")
+	}
+
+	var subnets []string
+	if d := got.AllowedSubnets.ElementsAs(ctx, &subnets, false); d.HasError() {
+		t.Fatalf("reading allowed_subnets: %v", d)
+	}
+	want := []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"}
+	if len(subnets) != len(want) {
+		t.Fatalf("AllowedSubnets = %v, want %v", subnets, want)
+	}
+	for i := range want {
+		if subnets[i] != want[i] {
+			t.Errorf("AllowedSubnets[%d] = %q, want %q", i, subnets[i], want[i])
+		}
+	}
+
+	custom := got.Customization.Attributes()
+	if !custom["enabled"].Equal(types.BoolValue(true)) {
+		t.Errorf("customization.enabled = %v, want true", custom["enabled"])
+	}
+	if !custom["title"].Equal(types.StringValue("Hotel Wi-Fi")) {
+		t.Errorf("customization.title = %v, want Hotel Wi-Fi", custom["title"])
+	}
+	if !custom["welcome_text"].IsNull() {
+		t.Errorf("customization.welcome_text = %v, want null", custom["welcome_text"])
+	}
+}
+
+func TestClient_guestAccessSetting(t *testing.T) {
+	// stored is the controller's setting. Like the controller, a PUT only
+	// changes the keys it holds.
+	stored := map[string]any{
+		"_id":                  "ga1",
+		"key":                  "guest_access",
+		"auth":                 "none",
+		"x_stripe_api_key":     "sk_live_secret",
+		"portal_future_option": "kept",
+		"allowed_subnet_1":     "10.0.0.0/8",
+		"allowed_subnet_2":     "172.16.0.0/12",
+		"allowed_subnet_3":     "192.168.0.0/16",
+	}
+	var putPath string
+	var put map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/s/default/get/setting/guest_access":
+		case r.Method == http.MethodPut && r.URL.Path == "/api/s/default/set/setting/guest_access/ga1":
+			putPath = r.URL.Path
+			put = nil
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for k, v := range put {
+				stored[k] = v
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"meta": map[string]any{"rc": "ok"},
+			"data": []map[string]any{stored},
+		})
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+	ctx := context.Background()
+
+	subnets, d := types.ListValueFrom(ctx, cidrtypes.IPv4PrefixType{}, []string{"192.0.2.0/24"})
+	if d.HasError() {
+		t.Fatalf("building list: %v", d)
+	}
+	model := nullGuestAccessModel()
+	model.Auth = types.StringValue("hotspot")
+	model.AllowedSubnets = subnets
+
+	current, err := c.getGuestAccessSetting(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var diags diag.Diagnostics
+	r := &settingResource{client: c}
+	setting := r.guestAccessModelToSetting(ctx, model, current, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if err := c.updateGuestAccessSetting(ctx, "default", setting); err != nil {
+		t.Fatal(err)
+	}
+
+	if putPath == "" {
+		t.Fatal("no PUT to the setting's ID")
+	}
+	// The unused slots must be sent blank, or the controller keeps them.
+	for _, key := range []string{"allowed_subnet_2", "allowed_subnet_3"} {
+		if v, ok := put[key]; !ok || v != "" {
+			t.Errorf("PUT %s = %v (sent: %v), want it blanked", key, v, ok)
+		}
+	}
+
+	got, err := c.getGuestAccessSetting(ctx, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"auth":                 "hotspot",
+		"x_stripe_api_key":     "sk_live_secret",
+		"portal_future_option": "kept",
+		"allowed_subnet_1":     "192.0.2.0/24",
+		"allowed_subnet_2":     "",
+		"allowed_subnet_3":     "",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v after update, want %q", k, got[k], v)
+		}
+	}
+
+	read := r.guestAccessSettingToModel(ctx, got, model, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	var readSubnets []string
+	diags.Append(read.AllowedSubnets.ElementsAs(ctx, &readSubnets, false)...)
+	if len(readSubnets) != 1 || readSubnets[0] != "192.0.2.0/24" {
+		t.Errorf("allowed_subnets = %v after update, want [192.0.2.0/24]", readSubnets)
+	}
+}
+
+func TestClient_guestAccessSetting_cloudConnector(t *testing.T) {
+	c := &Client{controller: controllerInfo{Console: consoleTypeCloudConnector}}
+	if _, err := c.getGuestAccessSetting(context.Background(), "default"); err == nil {
+		t.Error("getGuestAccessSetting() with cloud_connector succeeded, want an error")
+	}
+}
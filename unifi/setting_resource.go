@@ -7,6 +7,8 @@
 	"regexp"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/cidrtypes"
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/iptypes"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
@@ -237,6 +239,7 @@ type settingResourceModel struct {
 	Radius        types.Object   `tfsdk:"radius"`
 	USG           types.Object   `tfsdk:"usg"`
 	IgmpSnooping  types.Object   `tfsdk:"igmp_snooping"`
+	GuestAccess   types.Object   `tfsdk:"guest_access"`
 	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
@@ -1273,6 +1276,144 @@ func (r *settingResource) Schema(
 					},
 				},
 			},
+			"guest_access": schema.SingleNestedAttribute{
+				MarkdownDescription: "Guest portal and hotspot settings. Only the attributes you set are managed; payment gateway credentials and other portal options configured in the UI are preserved across updates. " + directConnectionDescription,
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"auth": schema.StringAttribute{
+						MarkdownDescription: "How guests authenticate: `none` (no portal), `hotspot` (vouchers, password or payment), `facebook_wifi` or `custom` (an external portal server at `external_portal_ip`).",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("none", "hotspot", "facebook_wifi", "custom"),
+						},
+					},
+					"portal_enabled": schema.BoolAttribute{
+						MarkdownDescription: "Whether guests are shown the portal before they get access.",
+						Optional:            true,
+					},
+					"external_portal_ip": schema.StringAttribute{
+						MarkdownDescription: "IP address of the external portal server, when `auth` is `custom`.",
+						CustomType:          iptypes.IPv4AddressType{},
+						Optional:            true,
+					},
+					"expire_minutes": schema.Int64Attribute{
+						MarkdownDescription: "How long a guest stays authorized after signing in with the password or a payment, in minutes.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"voucher_enabled": schema.BoolAttribute{
+						MarkdownDescription: "Whether guests can sign in with a voucher (see `unifi_hotspot_vouchers`).",
+						Optional:            true,
+					},
+					"password_enabled": schema.BoolAttribute{
+						MarkdownDescription: "Whether guests can sign in with the shared `password`.",
+						Optional:            true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "The shared password guests sign in with.",
+						Optional:            true,
+						Sensitive:           true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"payment_enabled": schema.BoolAttribute{
+						MarkdownDescription: "Whether guests can pay for access. The gateway's credentials are configured in the UI.",
+						Optional:            true,
+					},
+					"payment_gateway": schema.StringAttribute{
+						MarkdownDescription: "The payment gateway: `paypal`, `stripe`, `authorize`, `quickpay`, `merchantwarrior` or `ippay`.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("paypal", "stripe", "authorize", "quickpay", "merchantwarrior", "ippay"),
+						},
+					},
+					"redirect_url": schema.StringAttribute{
+						MarkdownDescription: "URL to send guests to after they are authorized. An empty string sends them to the page they first requested.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.Any(
+								stringvalidator.OneOf(""),
+								stringvalidator.RegexMatches(
+									regexp.MustCompile(`^https?://`),
+									"must be an http:// or https:// URL",
+								),
+							),
+						},
+					},
+					"terms_of_service": schema.StringAttribute{
+						MarkdownDescription: "Terms of service guests must accept on the portal. An empty string removes the terms.",
+						Optional:            true,
+					},
+					"allowed_subnets": schema.ListAttribute{
+						MarkdownDescription: "Subnets guests can reach before they are authorized, such as a hotel's booking site.",
+						ElementType:         cidrtypes.IPv4PrefixType{},
+						Optional:            true,
+					},
+					"portal_hostname": schema.StringAttribute{
+						MarkdownDescription: "Hostname the portal is served under, for a portal certificate that matches. An empty string serves the portal under the gateway's IP address.",
+						Optional:            true,
+					},
+					"customization": schema.SingleNestedAttribute{
+						MarkdownDescription: "Design of the portal page the controller builds. Custom HTML and CSS portal files are not managed: they live on the controller's file system and must be uploaded to its portal directory outside Terraform.",
+						Optional:            true,
+						Attributes: map[string]schema.Attribute{
+							"enabled": schema.BoolAttribute{
+								MarkdownDescription: "Whether the portal is built from these settings. When `false`, the controller serves the custom HTML and CSS portal files in its portal directory instead; the provider does not upload or check those files, and the other `customization` attributes have no effect.",
+								Optional:            true,
+							},
+							"title": schema.StringAttribute{
+								MarkdownDescription: "Portal page title.",
+								Optional:            true,
+							},
+							"welcome_text": schema.StringAttribute{
+								MarkdownDescription: "Text shown above the sign-in form. An empty string hides it.",
+								Optional:            true,
+							},
+							"success_text": schema.StringAttribute{
+								MarkdownDescription: "Text shown once the guest is authorized.",
+								Optional:            true,
+							},
+							"button_text": schema.StringAttribute{
+								MarkdownDescription: "Label of the sign-in button.",
+								Optional:            true,
+							},
+							"background_color": schema.StringAttribute{
+								MarkdownDescription: "Page background color, as `#rrggbb`.",
+								Optional:            true,
+								Validators:          []validator.String{guestPortalColorValidator},
+							},
+							"box_color": schema.StringAttribute{
+								MarkdownDescription: "Sign-in box color, as `#rrggbb`.",
+								Optional:            true,
+								Validators:          []validator.String{guestPortalColorValidator},
+							},
+							"text_color": schema.StringAttribute{
+								MarkdownDescription: "Text color, as `#rrggbb`.",
+								Optional:            true,
+								Validators:          []validator.String{guestPortalColorValidator},
+							},
+							"link_color": schema.StringAttribute{
+								MarkdownDescription: "Link color, as `#rrggbb`.",
+								Optional:            true,
+								Validators:          []validator.String{guestPortalColorValidator},
+							},
+							"button_color": schema.StringAttribute{
+								MarkdownDescription: "Sign-in button color, as `#rrggbb`.",
+								Optional:            true,
+								Validators:          []validator.String{guestPortalColorValidator},
+							},
+							"button_text_color": schema.StringAttribute{
+								MarkdownDescription: "Sign-in button text color, as `#rrggbb`.",
+								Optional:            true,
+								Validators:          []validator.String{guestPortalColorValidator},
+							},
+						},
+					},
+				},
+			},
 			"timeouts": timeouts.Attributes(
 				ctx,
 				timeouts.Opts{Create: true, Read: true, Update: true, Delete: true},
@@ -1603,6 +1744,29 @@ func (r *settingResource) Create(
 		}
 	}
 
+	if !data.GuestAccess.IsNull() && !data.GuestAccess.IsUnknown() {
+		var guest settingGuestAccessModel
+		resp.Diagnostics.Append(data.GuestAccess.As(ctx, &guest, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		currentGuest, err := r.client.getGuestAccessSetting(ctx, site)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Reading Guest Access Setting", err.Error())
+			return
+		}
+
+		setting := r.guestAccessModelToSetting(ctx, &guest, currentGuest, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if err := r.client.updateGuestAccessSetting(ctx, site, setting); err != nil {
+			resp.Diagnostics.AddError("Error Creating Guest Access Setting", err.Error())
+			return
+		}
+	}
+
 	// Read back the settings
 	r.readSettings(ctx, site, &data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -1892,6 +2056,29 @@ func (r *settingResource) Update(
 		}
 	}
 
+	if !plan.GuestAccess.IsNull() && !plan.GuestAccess.IsUnknown() {
+		var guest settingGuestAccessModel
+		resp.Diagnostics.Append(plan.GuestAccess.As(ctx, &guest, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		currentGuest, err := r.client.getGuestAccessSetting(ctx, site)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Reading Guest Access Setting", err.Error())
+			return
+		}
+
+		setting := r.guestAccessModelToSetting(ctx, &guest, currentGuest, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if err := r.client.updateGuestAccessSetting(ctx, site, setting); err != nil {
+			resp.Diagnostics.AddError("Error Updating Guest Access Setting", err.Error())
+			return
+		}
+	}
+
 	// Read back the settings
 	r.readSettings(ctx, site, &plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -2307,6 +2494,31 @@ func (r *settingResource) readSettings(
 	} else {
 		data.IgmpSnooping = types.ObjectNull(igmpSnoopingAttrTypes)
 	}
+
+	// Guest access settings
+	if !data.GuestAccess.IsNull() && !data.GuestAccess.IsUnknown() {
+		var planGuest settingGuestAccessModel
+		diags.Append(data.GuestAccess.As(ctx, &planGuest, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return
+		}
+
+		guestSetting, err := r.client.getGuestAccessSetting(ctx, site)
+		if err != nil {
+			diags.AddError("Error Reading Guest Access Setting", err.Error())
+			return
+		}
+
+		guestModel := r.guestAccessSettingToModel(ctx, guestSetting, &planGuest, diags)
+		objValue, d := types.ObjectValueFrom(ctx, guestAccessAttrTypes, guestModel)
+		diags.Append(d...)
+		if diags.HasError() {
+			return
+		}
+		data.GuestAccess = objValue
+	} else {
+		data.GuestAccess = types.ObjectNull(guestAccessAttrTypes)
+	}
 }
 
 // Mgmt conversion functions.
@@ -0,0 +1,489 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/iptypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &siteHealthDataSource{}
+
+func NewSiteHealthDataSource() datasource.DataSource {
+	return &siteHealthDataSource{}
+}
+
+// siteHealthDataSource defines the data source implementation.
+type siteHealthDataSource struct {
+	client *Client
+}
+
+// siteHealthDataSourceModel describes the data source data model.
+type siteHealthDataSourceModel struct {
+	Site              types.String   `tfsdk:"site"`
+	WAN               types.Object   `tfsdk:"wan"`
+	LAN               types.Object   `tfsdk:"lan"`
+	WLAN              types.Object   `tfsdk:"wlan"`
+	VPN               types.Object   `tfsdk:"vpn"`
+	NumAdopted        types.Int64    `tfsdk:"num_adopted"`
+	NumDisconnected   types.Int64    `tfsdk:"num_disconnected"`
+	NumPending        types.Int64    `tfsdk:"num_pending"`
+	ControllerVersion types.String   `tfsdk:"controller_version"`
+	ControllerUptime  types.Int64    `tfsdk:"controller_uptime"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// siteHealthWANAttrTypes returns the attribute types of the WAN health.
+func siteHealthWANAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"status":          types.StringType,
+		"internet_status": types.StringType,
+		"isp_name":        types.StringType,
+		"public_ip":       iptypes.IPv4AddressType{},
+		"latency_ms":      types.Float64Type,
+		"uptime":          types.Int64Type,
+	}
+}
+
+// siteHealthLANAttrTypes returns the attribute types of the LAN health.
+func siteHealthLANAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"status":           types.StringType,
+		"num_users":        types.Int64Type,
+		"num_guests":       types.Int64Type,
+		"num_switches":     types.Int64Type,
+		"num_adopted":      types.Int64Type,
+		"num_disconnected": types.Int64Type,
+		"num_pending":      types.Int64Type,
+	}
+}
+
+// siteHealthWLANAttrTypes returns the attribute types of the WLAN health.
+func siteHealthWLANAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"status":            types.StringType,
+		"num_users":         types.Int64Type,
+		"num_guests":        types.Int64Type,
+		"num_access_points": types.Int64Type,
+		"num_adopted":       types.Int64Type,
+		"num_disconnected":  types.Int64Type,
+		"num_pending":       types.Int64Type,
+	}
+}
+
+// siteHealthVPNAttrTypes returns the attribute types of the VPN health.
+func siteHealthVPNAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"status":                    types.StringType,
+		"num_remote_users_active":   types.Int64Type,
+		"num_remote_users_inactive": types.Int64Type,
+	}
+}
+
+func (d *siteHealthDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_site_health"
+}
+
+func (d *siteHealthDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	statusDescription := "The health the controller computes for the subsystem: `ok`, `warning`, " +
+		"`error` or `unknown`."
+	countAttributes := func(role string) map[string]schema.Attribute {
+		return map[string]schema.Attribute{
+			"num_users": schema.Int64Attribute{
+				MarkdownDescription: "The number of connected clients, excluding guests.",
+				Computed:            true,
+			},
+			"num_guests": schema.Int64Attribute{
+				MarkdownDescription: "The number of connected guests.",
+				Computed:            true,
+			},
+			"num_adopted": schema.Int64Attribute{
+				MarkdownDescription: "The number of adopted " + role + ".",
+				Computed:            true,
+			},
+			"num_disconnected": schema.Int64Attribute{
+				MarkdownDescription: "The number of adopted " + role + " that are disconnected.",
+				Computed:            true,
+			},
+			"num_pending": schema.Int64Attribute{
+				MarkdownDescription: "The number of " + role + " waiting to be adopted.",
+				Computed:            true,
+			},
+		}
+	}
+
+	lan := countAttributes("switches")
+	lan["status"] = schema.StringAttribute{
+		MarkdownDescription: statusDescription,
+		Computed:            true,
+	}
+	lan["num_switches"] = schema.Int64Attribute{
+		MarkdownDescription: "The number of switches.",
+		Computed:            true,
+	}
+
+	wlan := countAttributes("access points")
+	wlan["status"] = schema.StringAttribute{
+		MarkdownDescription: statusDescription,
+		Computed:            true,
+	}
+	wlan["num_access_points"] = schema.Int64Attribute{
+		MarkdownDescription: "The number of access points.",
+		Computed:            true,
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`unifi_site_health` data source can be used to read the per-subsystem health the " +
+			"controller computes for a site, along with the controller version and uptime. It is read on every " +
+			"refresh, so it suits `check` blocks and postconditions. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site to read the health of.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"wan": schema.SingleNestedAttribute{
+				MarkdownDescription: "The health of the WAN and internet connection. Null if the site has no gateway.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						MarkdownDescription: statusDescription,
+						Computed:            true,
+					},
+					"internet_status": schema.StringAttribute{
+						MarkdownDescription: "The health of the internet connection as seen by the gateway, " +
+							"with the same values as `status`.",
+						Computed: true,
+					},
+					"isp_name": schema.StringAttribute{
+						MarkdownDescription: "The name of the internet service provider.",
+						Computed:            true,
+					},
+					"public_ip": schema.StringAttribute{
+						MarkdownDescription: "The IPv4 address of the WAN interface.",
+						CustomType:          iptypes.IPv4AddressType{},
+						Computed:            true,
+					},
+					"latency_ms": schema.Float64Attribute{
+						MarkdownDescription: "The latency to the internet in milliseconds.",
+						Computed:            true,
+					},
+					"uptime": schema.Int64Attribute{
+						MarkdownDescription: "How long the internet connection has been up, in seconds.",
+						Computed:            true,
+					},
+				},
+			},
+			"lan": schema.SingleNestedAttribute{
+				MarkdownDescription: "The health of the wired network. Null if the controller does not report it.",
+				Computed:            true,
+				Attributes:          lan,
+			},
+			"wlan": schema.SingleNestedAttribute{
+				MarkdownDescription: "The health of the wireless network. Null if the controller does not report it.",
+				Computed:            true,
+				Attributes:          wlan,
+			},
+			"vpn": schema.SingleNestedAttribute{
+				MarkdownDescription: "The health of the remote user VPN. Null if the controller does not report it.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						MarkdownDescription: statusDescription,
+						Computed:            true,
+					},
+					"num_remote_users_active": schema.Int64Attribute{
+						MarkdownDescription: "The number of connected remote VPN users.",
+						Computed:            true,
+					},
+					"num_remote_users_inactive": schema.Int64Attribute{
+						MarkdownDescription: "The number of remote VPN users that are not connected.",
+						Computed:            true,
+					},
+				},
+			},
+			"num_adopted": schema.Int64Attribute{
+				MarkdownDescription: "The number of adopted devices on the site.",
+				Computed:            true,
+			},
+			"num_disconnected": schema.Int64Attribute{
+				MarkdownDescription: "The number of adopted devices on the site that are disconnected.",
+				Computed:            true,
+			},
+			"num_pending": schema.Int64Attribute{
+				MarkdownDescription: "The number of devices waiting to be adopted.",
+				Computed:            true,
+			},
+			"controller_version": schema.StringAttribute{
+				MarkdownDescription: "The Network Application version.",
+				Computed:            true,
+			},
+			"controller_uptime": schema.Int64Attribute{
+				MarkdownDescription: "How long the Network Application has been running, in seconds.",
+				Computed:            true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *siteHealthDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *siteHealthDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data siteHealthDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := data.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = d.client.Site
+	}
+
+	health, err := d.client.getSiteHealth(ctx, site)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Site Health",
+			fmt.Sprintf("Could not read the health of site %s: %s", site, err),
+		)
+		return
+	}
+
+	sysinfo, err := d.client.getSysinfo(ctx, site)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Controller Info",
+			"Could not read the controller's system information: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(siteHealthToModel(health, sysinfo, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Site = types.StringValue(site)
+	if data.ControllerVersion.IsNull() && d.client.controller.Version != "" {
+		data.ControllerVersion = types.StringValue(d.client.controller.Version)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// siteHealthToModel sets the health and controller attributes of model.
+// Subsystems missing from health are null.
+func siteHealthToModel(health []subsystemHealth, sysinfo *controllerSysinfo, model *siteHealthDataSourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	subsystems := make(map[string]*subsystemHealth, len(health))
+	for i := range health {
+		subsystems[health[i].Subsystem] = &health[i]
+	}
+
+	var adopted, disconnected, pending int64
+	for _, name := range []string{"wan", "lan", "wlan"} {
+		if s := subsystems[name]; s != nil {
+			adopted += s.NumAdopted
+			disconnected += s.NumDisconnected
+			pending += s.NumPending
+		}
+	}
+	model.NumAdopted = types.Int64Value(adopted)
+	model.NumDisconnected = types.Int64Value(disconnected)
+	model.NumPending = types.Int64Value(pending)
+
+	model.WAN = types.ObjectNull(siteHealthWANAttrTypes())
+	if wan := subsystems["wan"]; wan != nil {
+		values := map[string]attr.Value{
+			"status":          types.StringValue(wan.Status),
+			"internet_status": types.StringNull(),
+			"isp_name":        util.StringValueOrNull(wan.ISPName),
+			"public_ip":       util.IPv4ValueOrNull(wan.WANIP),
+			"latency_ms":      types.Float64Null(),
+			"uptime":          types.Int64Null(),
+		}
+		if www := subsystems["www"]; www != nil {
+			values["internet_status"] = types.StringValue(www.Status)
+			values["latency_ms"] = types.Float64Value(float64(www.Latency))
+			values["uptime"] = types.Int64Value(www.Uptime)
+		}
+		var d diag.Diagnostics
+		model.WAN, d = types.ObjectValue(siteHealthWANAttrTypes(), values)
+		diags.Append(d...)
+	}
+
+	model.LAN = types.ObjectNull(siteHealthLANAttrTypes())
+	if lan := subsystems["lan"]; lan != nil {
+		var d diag.Diagnostics
+		model.LAN, d = types.ObjectValue(siteHealthLANAttrTypes(), map[string]attr.Value{
+			"status":           types.StringValue(lan.Status),
+			"num_users":        types.Int64Value(lan.NumUser),
+			"num_guests":       types.Int64Value(lan.NumGuest),
+			"num_switches":     types.Int64Value(lan.NumSw),
+			"num_adopted":      types.Int64Value(lan.NumAdopted),
+			"num_disconnected": types.Int64Value(lan.NumDisconnected),
+			"num_pending":      types.Int64Value(lan.NumPending),
+		})
+		diags.Append(d...)
+	}
+
+	model.WLAN = types.ObjectNull(siteHealthWLANAttrTypes())
+	if wlan := subsystems["wlan"]; wlan != nil {
+		var d diag.Diagnostics
+		model.WLAN, d = types.ObjectValue(siteHealthWLANAttrTypes(), map[string]attr.Value{
+			"status":            types.StringValue(wlan.Status),
+			"num_users":         types.Int64Value(wlan.NumUser),
+			"num_guests":        types.Int64Value(wlan.NumGuest),
+			"num_access_points": types.Int64Value(wlan.NumAP),
+			"num_adopted":       types.Int64Value(wlan.NumAdopted),
+			"num_disconnected":  types.Int64Value(wlan.NumDisconnected),
+			"num_pending":       types.Int64Value(wlan.NumPending),
+		})
+		diags.Append(d...)
+	}
+
+	model.VPN = types.ObjectNull(siteHealthVPNAttrTypes())
+	if vpn := subsystems["vpn"]; vpn != nil {
+		var d diag.Diagnostics
+		model.VPN, d = types.ObjectValue(siteHealthVPNAttrTypes(), map[string]attr.Value{
+			"status":                    types.StringValue(vpn.Status),
+			"num_remote_users_active":   types.Int64Value(vpn.RemoteUserNumActive),
+			"num_remote_users_inactive": types.Int64Value(vpn.RemoteUserNumInactive),
+		})
+		diags.Append(d...)
+	}
+
+	model.ControllerVersion = types.StringNull()
+	model.ControllerUptime = types.Int64Null()
+	if sysinfo != nil {
+		model.ControllerVersion = util.StringValueOrNull(sysinfo.Version)
+		model.ControllerUptime = types.Int64Value(sysinfo.Uptime)
+	}
+
+	return diags
+}
+
+// subsystemHealth is an entry of `stat/health`. Which fields are set depends
+// on the subsystem.
+type subsystemHealth struct {
+	Subsystem       string `json:"subsystem"`
+	Status          string `json:"status"`
+	NumUser         int64  `json:"num_user"`
+	NumGuest        int64  `json:"num_guest"`
+	NumAdopted      int64  `json:"num_adopted"`
+	NumDisconnected int64  `json:"num_disconnected"`
+	NumPending      int64  `json:"num_pending"`
+	NumSw           int64  `json:"num_sw"`
+	NumAP           int64  `json:"num_ap"`
+
+	// wan
+	WANIP   string `json:"wan_ip"`
+	ISPName string `json:"isp_name"`
+
+	// www
+	Latency jsonFloat `json:"latency"`
+	Uptime  int64     `json:"uptime"`
+
+	// vpn
+	RemoteUserNumActive   int64 `json:"remote_user_num_active"`
+	RemoteUserNumInactive int64 `json:"remote_user_num_inactive"`
+}
+
+// controllerSysinfo is the part of `stat/sysinfo` the site health needs.
+type controllerSysinfo struct {
+	Version string `json:"version"`
+	Uptime  int64  `json:"uptime"`
+}
+
+// getSiteHealth returns the health of each subsystem of site.
+func (c *Client) getSiteHealth(ctx context.Context, site string) ([]subsystemHealth, error) {
+	var health []subsystemHealth
+	err := c.controllerRequest(
+		ctx,
+		http.MethodGet,
+		"/api/s/"+url.PathEscape(site)+"/stat/health",
+		nil,
+		&health,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// getSysinfo returns the controller's system information, or nil if it
+// reported none.
+func (c *Client) getSysinfo(ctx context.Context, site string) (*controllerSysinfo, error) {
+	var sysinfo []controllerSysinfo
+	err := c.controllerRequest(
+		ctx,
+		http.MethodGet,
+		"/api/s/"+url.PathEscape(site)+"/stat/sysinfo",
+		nil,
+		&sysinfo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(sysinfo) == 0 {
+		return nil, nil
+	}
+	return &sysinfo[0], nil
+}
@@ -0,0 +1,162 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/fakecontroller"
+)
+
+func TestAccSiteHealthDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteHealthDataSourceConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.unifi_site_health.test", "site", "default"),
+					resource.TestCheckResourceAttrSet("data.unifi_site_health.test", "wan.status"),
+					resource.TestCheckResourceAttrSet("data.unifi_site_health.test", "lan.status"),
+					resource.TestCheckResourceAttrSet("data.unifi_site_health.test", "num_adopted"),
+					resource.TestCheckResourceAttrSet("data.unifi_site_health.test", "controller_version"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSiteHealthDataSourceConfig_basic() string {
+	return `
+data "unifi_site_health" "test" {
+}
+`
+}
+
+func TestNewSiteHealthDataSource(t *testing.T) {
+	d := NewSiteHealthDataSource()
+	if d == nil {
+		t.Fatal("NewSiteHealthDataSource() returned nil")
+	}
+}
+
+func Test_siteHealthDataSource_Metadata(t *testing.T) {
+	d := &siteHealthDataSource{}
+	resp := &fwdatasource.MetadataResponse{}
+	d.Metadata(context.Background(), fwdatasource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_site_health" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_site_health")
+	}
+}
+
+func Test_siteHealthDataSource_Schema(t *testing.T) {
+	d := &siteHealthDataSource{}
+	resp := &fwdatasource.SchemaResponse{}
+	d.Schema(context.Background(), fwdatasource.SchemaRequest{}, resp)
+	for _, attr := range []string{"site", "wan", "lan", "wlan", "vpn", "num_adopted", "num_disconnected", "num_pending", "controller_version", "controller_uptime", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_siteHealthDataSource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwdatasource.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwdatasource.ConfigureRequest{}, false},
+		{"wrong_type", fwdatasource.ConfigureRequest{ProviderData: "wrong"}, true},
+		{"correct_client", fwdatasource.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &siteHealthDataSource{}
+			resp := &fwdatasource.ConfigureResponse{}
+			d.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_siteHealthToModel(t *testing.T) {
+	health := []subsystemHealth{
+		{Subsystem: "wan", Status: "ok", WANIP: "203.0.113.7", ISPName: "Example ISP", NumAdopted: 1},
+		{Subsystem: "www", Status: "ok", Latency: 12.5, Uptime: 86400},
+		{Subsystem: "lan", Status: "warning", NumUser: 10, NumSw: 3, NumAdopted: 3, NumDisconnected: 1},
+		{Subsystem: "wlan", Status: "ok", NumUser: 20, NumGuest: 2, NumAP: 4, NumAdopted: 4, NumPending: 1},
+	}
+
+	var model siteHealthDataSourceModel
+	if diags := siteHealthToModel(health, &controllerSysinfo{Version: "9.4.19", Uptime: 3600}, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if got := model.NumAdopted.ValueInt64(); got != 8 {
+		t.Errorf("num_adopted = %d, want 8", got)
+	}
+	if got := model.NumDisconnected.ValueInt64(); got != 1 {
+		t.Errorf("num_disconnected = %d, want 1", got)
+	}
+	if got := model.NumPending.ValueInt64(); got != 1 {
+		t.Errorf("num_pending = %d, want 1", got)
+	}
+
+	wan := model.WAN.Attributes()
+	if got := wan["public_ip"].String(); got != `"203.0.113.7"` {
+		t.Errorf("public_ip = %s, want %q", got, "203.0.113.7")
+	}
+	if got := wan["latency_ms"].(types.Float64).ValueFloat64(); got != 12.5 {
+		t.Errorf("latency_ms = %v, want 12.5", got)
+	}
+	if got := wan["uptime"].(types.Int64).ValueInt64(); got != 86400 {
+		t.Errorf("uptime = %d, want 86400", got)
+	}
+	if got := model.LAN.Attributes()["status"].(types.String).ValueString(); got != "warning" {
+		t.Errorf("lan status = %q, want %q", got, "warning")
+	}
+	if got := model.WLAN.Attributes()["num_access_points"].(types.Int64).ValueInt64(); got != 4 {
+		t.Errorf("num_access_points = %d, want 4", got)
+	}
+	if !model.VPN.IsNull() {
+		t.Errorf("vpn = %s, want null without a vpn subsystem", model.VPN)
+	}
+	if got := model.ControllerVersion.ValueString(); got != "9.4.19" {
+		t.Errorf("controller_version = %q, want %q", got, "9.4.19")
+	}
+}
+
+func TestClient_getSiteHealth(t *testing.T) {
+	srv := fakecontroller.New(fakecontroller.WithAPIKey("key"))
+	defer srv.Close()
+	srv.Create("default", "device", map[string]any{"mac": "aa:bb:cc:dd:ee:01", "type": "usw", "adopted": true, "state": 1})
+
+	c := newTestCommandClient(t, srv, "key")
+	health, err := c.getSiteHealth(context.Background(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lan *subsystemHealth
+	for i := range health {
+		if health[i].Subsystem == "lan" {
+			lan = &health[i]
+		}
+	}
+	if lan == nil || lan.Status != "ok" || lan.NumSw != 1 {
+		t.Errorf("lan = %+v, want ok with one switch", lan)
+	}
+
+	sysinfo, err := c.getSysinfo(context.Background(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sysinfo == nil || sysinfo.Version != fakecontroller.DefaultVersion {
+		t.Errorf("sysinfo = %+v, want version %s", sysinfo, fakecontroller.DefaultVersion)
+	}
+}
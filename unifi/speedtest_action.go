@@ -0,0 +1,235 @@
+package unifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/ubiquiti-community/terraform-provider-unifi/unifi/util/retry"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action              = &speedtestAction{}
+	_ action.ActionWithConfigure = &speedtestAction{}
+)
+
+// speedtestRunning is the `status_summary` of a speed test in progress.
+const speedtestRunning = 1
+
+// NewSpeedtestAction returns a new instance of the speed test action.
+func NewSpeedtestAction() action.Action {
+	return &speedtestAction{}
+}
+
+type speedtestAction struct {
+	client *Client
+}
+
+// speedtestActionModel describes the action request data model.
+type speedtestActionModel struct {
+	MinDownloadMbps types.Float64  `tfsdk:"min_download_mbps"`
+	MinUploadMbps   types.Float64  `tfsdk:"min_upload_mbps"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (a *speedtestAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_speedtest"
+}
+
+func (a *speedtestAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a WAN speed test on the site's gateway and waits for it to finish. " +
+			"The result is reported as progress and can be read afterwards with the " +
+			"`unifi_speedtest_results` data source. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"min_download_mbps": schema.Float64Attribute{
+				MarkdownDescription: "Fail the action if the measured download speed is below this many Mbps.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"min_upload_mbps": schema.Float64Attribute{
+				MarkdownDescription: "Fail the action if the measured upload speed is below this many Mbps.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *speedtestAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *speedtestAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_speedtest")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config speedtestActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Running speed test"})
+
+	result, err := a.client.runSpeedtest(ctx, a.client.Site)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Running Speed Test", err.Error())
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf(
+			"Speed test finished: %.1f Mbps down, %.1f Mbps up, %.0f ms latency",
+			result.XputDownload, result.XputUpload, result.Latency,
+		),
+	})
+	tflog.Info(ctx, "Speed test finished", map[string]any{
+		"download_mbps": result.XputDownload,
+		"upload_mbps":   result.XputUpload,
+		"latency_ms":    result.Latency,
+	})
+
+	if !config.MinDownloadMbps.IsNull() && result.XputDownload < config.MinDownloadMbps.ValueFloat64() {
+		resp.Diagnostics.AddError(
+			"Download Speed Too Low",
+			fmt.Sprintf(
+				"The measured download speed of %.1f Mbps is below min_download_mbps (%.1f Mbps).",
+				result.XputDownload, config.MinDownloadMbps.ValueFloat64(),
+			),
+		)
+	}
+	if !config.MinUploadMbps.IsNull() && result.XputUpload < config.MinUploadMbps.ValueFloat64() {
+		resp.Diagnostics.AddError(
+			"Upload Speed Too Low",
+			fmt.Sprintf(
+				"The measured upload speed of %.1f Mbps is below min_upload_mbps (%.1f Mbps).",
+				result.XputUpload, config.MinUploadMbps.ValueFloat64(),
+			),
+		)
+	}
+}
+
+// speedtestStatus is the result of the `speedtest-status` command.
+type speedtestStatus struct {
+	RunDate       int64   `json:"rundate"`
+	StatusSummary int     `json:"status_summary"`
+	XputDownload  float64 `json:"xput_download"`
+	XputUpload    float64 `json:"xput_upload"`
+	Latency       float64 `json:"latency"`
+}
+
+// getSpeedtestStatus returns the status of the site's latest speed test.
+func (c *Client) getSpeedtestStatus(ctx context.Context, site string) (*speedtestStatus, error) {
+	var status []speedtestStatus
+	if err := c.executeCommand(ctx, site, "devmgr", map[string]any{"cmd": "speedtest-status"}, &status); err != nil {
+		return nil, err
+	}
+	if len(status) == 0 {
+		return &speedtestStatus{}, nil
+	}
+	return &status[0], nil
+}
+
+// runSpeedtest starts a speed test and waits for it to finish. A run counts
+// as finished once the controller reports a run date other than that of the
+// previous run and the test is no longer running; the run date is compared
+// rather than the clock, as the controller's clock may differ from ours.
+func (c *Client) runSpeedtest(ctx context.Context, site string) (*speedtestStatus, error) {
+	before, err := c.getSpeedtestStatus(ctx, site)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the speed test status: %w", err)
+	}
+	if err := c.executeCommand(ctx, site, "devmgr", map[string]any{"cmd": "speedtest"}, nil); err != nil {
+		return nil, fmt.Errorf("could not start the speed test: %w", err)
+	}
+
+	wait := retry.StateChangeConf{
+		Pending: []string{"running"},
+		Target:  []string{"finished"},
+		Refresh: func() (any, string, error) {
+			status, err := c.getSpeedtestStatus(ctx, site)
+			if err != nil {
+				return nil, "", err
+			}
+			if status.RunDate == before.RunDate || status.StatusSummary == speedtestRunning {
+				return status, "running", nil
+			}
+			return status, "finished", nil
+		},
+		Timeout:      timeUntilDeadline(ctx),
+		PollInterval: devicePollInterval,
+	}
+
+	out, err := wait.WaitForStateContext(ctx)
+	if err != nil {
+		var timeout *retry.TimeoutError
+		if errors.As(err, &timeout) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("the speed test did not finish in time: %w", err)
+		}
+		return nil, err
+	}
+	status, ok := out.(*speedtestStatus)
+	if !ok {
+		return nil, fmt.Errorf("unexpected speed test status %T", out)
+	}
+	return status, nil
+}
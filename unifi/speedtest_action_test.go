@@ -0,0 +1,153 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+func TestNewSpeedtestAction(t *testing.T) {
+	got := NewSpeedtestAction()
+	if got == nil {
+		t.Fatal("NewSpeedtestAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_speedtestAction_Metadata(t *testing.T) {
+	a := &speedtestAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_speedtest" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_speedtest")
+	}
+}
+
+func Test_speedtestAction_Schema(t *testing.T) {
+	a := &speedtestAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{"min_download_mbps", "min_upload_mbps", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+}
+
+func Test_speedtestAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &speedtestAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+// speedtestController fakes the speed test commands. A started test runs for
+// runningPolls status polls, unless stuck is set.
+type speedtestController struct {
+	mu           sync.Mutex
+	status       speedtestStatus
+	runningPolls int
+	stuck        bool
+}
+
+func (s *speedtestController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cmd map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&cmd)
+	data := []any{}
+	switch cmd["cmd"] {
+	case "speedtest":
+		s.status = speedtestStatus{RunDate: s.status.RunDate + 3600, StatusSummary: speedtestRunning}
+	case "speedtest-status":
+		if s.status.StatusSummary == speedtestRunning && !s.stuck {
+			if s.runningPolls--; s.runningPolls < 0 {
+				s.status.StatusSummary = 2
+				s.status.XputDownload = 940.2
+				s.status.XputUpload = 38.7
+				s.status.Latency = 9
+			}
+		}
+		data = append(data, s.status)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"rc": "ok"}, "data": data})
+}
+
+func newSpeedtestClient(t *testing.T, s *speedtestController) *Client {
+	t.Helper()
+	interval := devicePollInterval
+	devicePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { devicePollInterval = interval })
+
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+}
+
+func TestClient_runSpeedtest(t *testing.T) {
+	t.Run("finishes", func(t *testing.T) {
+		s := &speedtestController{
+			status:       speedtestStatus{RunDate: 1700000000, StatusSummary: 2, XputDownload: 1, XputUpload: 1},
+			runningPolls: 2,
+		}
+		c := newSpeedtestClient(t, s)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		got, err := c.runSpeedtest(ctx, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.RunDate != 1700003600 || got.XputDownload != 940.2 || got.XputUpload != 38.7 {
+			t.Errorf("result = %+v, want the new run", got)
+		}
+	})
+
+	t.Run("does not finish", func(t *testing.T) {
+		s := &speedtestController{stuck: true}
+		c := newSpeedtestClient(t, s)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		_, err := c.runSpeedtest(ctx, "default")
+		if err == nil || !strings.Contains(err.Error(), "did not finish in time") {
+			t.Errorf("error = %v, want a timeout", err)
+		}
+	})
+}
@@ -0,0 +1,246 @@
+package unifi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &speedtestResultsDataSource{}
+
+// defaultSpeedtestLookback is how far back results are read by default.
+const defaultSpeedtestLookback = 24 * time.Hour
+
+func NewSpeedtestResultsDataSource() datasource.DataSource {
+	return &speedtestResultsDataSource{}
+}
+
+type speedtestResultsDataSource struct {
+	client *Client
+}
+
+type speedtestResultsDataSourceModel struct {
+	Site     types.String         `tfsdk:"site"`
+	Lookback timetypes.GoDuration `tfsdk:"lookback"`
+	Results  types.List           `tfsdk:"results"`
+	Timeouts timeouts.Value       `tfsdk:"timeouts"`
+}
+
+// speedtestResultAttrTypes returns the attribute types of a result.
+func speedtestResultAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"timestamp":     timetypes.RFC3339Type{},
+		"download_mbps": types.Float64Type,
+		"upload_mbps":   types.Float64Type,
+		"latency_ms":    types.Float64Type,
+		"wan_interface": types.StringType,
+	}
+}
+
+func (d *speedtestResultsDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_speedtest_results"
+}
+
+func (d *speedtestResultsDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Retrieves the results of recent WAN speed tests, whether scheduled with " +
+			"`auto_speedtest` or run with the `unifi_speedtest` action. " +
+			directConnectionDescription,
+
+		Attributes: map[string]schema.Attribute{
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site to retrieve speed test results from.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"lookback": schema.StringAttribute{
+				MarkdownDescription: "How far back to read results, as a Go duration string (e.g. `168h`). " +
+					"Defaults to `24h`.",
+				CustomType: timetypes.GoDurationType{},
+				Optional:   true,
+			},
+			"results": schema.ListNestedAttribute{
+				MarkdownDescription: "Speed test results, newest first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"timestamp": schema.StringAttribute{
+							MarkdownDescription: "When the test ran, in RFC 3339 format.",
+							CustomType:          timetypes.RFC3339Type{},
+							Computed:            true,
+						},
+						"download_mbps": schema.Float64Attribute{
+							MarkdownDescription: "Measured download speed in Mbps.",
+							Computed:            true,
+						},
+						"upload_mbps": schema.Float64Attribute{
+							MarkdownDescription: "Measured upload speed in Mbps.",
+							Computed:            true,
+						},
+						"latency_ms": schema.Float64Attribute{
+							MarkdownDescription: "Measured latency in milliseconds.",
+							Computed:            true,
+						},
+						"wan_interface": schema.StringAttribute{
+							MarkdownDescription: "The WAN interface the test ran on, if the controller reports it.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *speedtestResultsDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	if client, ok := req.ProviderData.(*Client); ok {
+		d.client = client
+	} else {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+	}
+}
+
+func (d *speedtestResultsDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data speedtestResultsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := data.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := data.Site.ValueString()
+	if site == "" {
+		site = d.client.Site
+	}
+
+	lookback := defaultSpeedtestLookback
+	if !data.Lookback.IsNull() && !data.Lookback.IsUnknown() {
+		v, diags := data.Lookback.ValueGoDuration()
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		lookback = v
+	}
+
+	end := time.Now()
+	results, err := d.client.listSpeedtestResults(ctx, site, end.Add(-lookback), end)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Speed Test Results",
+			"Could not read speed test results: "+err.Error(),
+		)
+		return
+	}
+
+	objects := make([]attr.Value, len(results))
+	for i, r := range results {
+		o, diags := types.ObjectValue(speedtestResultAttrTypes(), map[string]attr.Value{
+			"timestamp":     timetypes.NewRFC3339TimeValue(time.UnixMilli(r.Time).UTC()),
+			"download_mbps": types.Float64Value(r.XputDownload),
+			"upload_mbps":   types.Float64Value(r.XputUpload),
+			"latency_ms":    types.Float64Value(r.Latency),
+			"wan_interface": types.StringValue(r.wanInterface()),
+		})
+		if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+			return
+		}
+		objects[i] = o
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: speedtestResultAttrTypes()}, objects)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	data.Results = list
+	data.Site = types.StringValue(site)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// speedtestResult is an entry of the `archive.speedtest` report.
+type speedtestResult struct {
+	Time            int64   `json:"time"`
+	XputDownload    float64 `json:"xput_download"`
+	XputUpload      float64 `json:"xput_upload"`
+	Latency         float64 `json:"latency"`
+	InterfaceName   string  `json:"interface_name"`
+	WANNetworkGroup string  `json:"wan_networkgroup"`
+}
+
+func (r *speedtestResult) wanInterface() string {
+	if r.WANNetworkGroup != "" {
+		return r.WANNetworkGroup
+	}
+	return r.InterfaceName
+}
+
+// listSpeedtestResults returns the speed test results recorded between start
+// and end, newest first.
+func (c *Client) listSpeedtestResults(ctx context.Context, site string, start, end time.Time) ([]speedtestResult, error) {
+	var results []speedtestResult
+	err := c.controllerRequest(
+		ctx,
+		http.MethodPost,
+		"/api/s/"+url.PathEscape(site)+"/stat/report/archive.speedtest",
+		map[string]any{
+			"attrs": []string{
+				"time", "xput_download", "xput_upload", "latency", "interface_name", "wan_networkgroup",
+			},
+			"start": start.UnixMilli(),
+			"end":   end.UnixMilli(),
+		},
+		&results,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(results, func(a, b speedtestResult) int { return cmp.Compare(b.Time, a.Time) })
+	return results, nil
+}
@@ -0,0 +1,153 @@
+package unifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSpeedtestResultsDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSpeedtestResultsDataSourceConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"data.unifi_speedtest_results.test",
+						"results.#",
+					),
+					resource.TestCheckResourceAttr(
+						"data.unifi_speedtest_results.test",
+						"site",
+						"default",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccSpeedtestResultsDataSourceConfig_basic() string {
+	return `
+data "unifi_speedtest_results" "test" {
+  lookback = "168h"
+}
+`
+}
+
+func TestNewSpeedtestResultsDataSource(t *testing.T) {
+	d := NewSpeedtestResultsDataSource()
+	if d == nil {
+		t.Fatal("NewSpeedtestResultsDataSource() returned nil")
+	}
+	if _, ok := d.(fwdatasource.DataSourceWithConfigure); !ok {
+		t.Error("expected DataSourceWithConfigure interface")
+	}
+}
+
+func Test_speedtestResultsDataSource_Metadata(t *testing.T) {
+	d := &speedtestResultsDataSource{}
+	resp := &fwdatasource.MetadataResponse{}
+	d.Metadata(context.Background(), fwdatasource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_speedtest_results" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_speedtest_results")
+	}
+}
+
+func Test_speedtestResultsDataSource_Schema(t *testing.T) {
+	d := &speedtestResultsDataSource{}
+	resp := &fwdatasource.SchemaResponse{}
+	d.Schema(context.Background(), fwdatasource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("Schema() produced errors: %v", resp.Diagnostics)
+	}
+	for _, attr := range []string{"site", "lookback", "results", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("missing attribute %q", attr)
+		}
+	}
+}
+
+func Test_speedtestResultsDataSource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      any
+		wantError bool
+	}{
+		{"nil provider data", nil, false},
+		{"wrong type", "wrong", true},
+		{"correct client type", &Client{Site: "default"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &speedtestResultsDataSource{}
+			resp := &fwdatasource.ConfigureResponse{}
+			d.Configure(
+				context.Background(),
+				fwdatasource.ConfigureRequest{ProviderData: tt.data},
+				resp,
+			)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v (diags: %v)", resp.Diagnostics.HasError(), tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestClient_listSpeedtestResults(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/s/default/stat/report/archive.speedtest" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"meta": map[string]any{"rc": "ok"},
+			"data": []map[string]any{
+				{"time": 1000, "xput_download": 90.5, "xput_upload": 20, "latency": 12, "interface_name": "eth8"},
+				{"time": 3000, "xput_download": 95, "xput_upload": 21.5, "latency": 11, "wan_networkgroup": "WAN2"},
+				{"time": 2000, "xput_download": 91, "xput_upload": 19, "latency": 13},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	httpClient, err := newHTTPClient(transportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{
+		Site:       "default",
+		controller: controllerInfo{APIBaseURL: srv.URL},
+		httpClient: httpClient,
+	}
+
+	start := time.UnixMilli(500)
+	end := time.UnixMilli(5000)
+	results, err := c.listSpeedtestResults(context.Background(), "default", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["start"] != float64(500) || body["end"] != float64(5000) {
+		t.Errorf("request = %v, want start 500 and end 5000", body)
+	}
+	if len(results) != 3 || results[0].Time != 3000 || results[2].Time != 1000 {
+		t.Fatalf("results = %+v, want newest first", results)
+	}
+	if got := results[0].wanInterface(); got != "WAN2" {
+		t.Errorf("wanInterface() = %q, want WAN2", got)
+	}
+	if got := results[2].wanInterface(); got != "eth8" {
+		t.Errorf("wanInterface() = %q, want eth8", got)
+	}
+}
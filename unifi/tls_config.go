@@ -0,0 +1,204 @@
+package unifi
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tlsSettings are the TLS provider arguments after falling back to the
+// environment. PEM and file variants of the same setting are mutually
+// exclusive.
+type tlsSettings struct {
+	CACertPEM        string
+	CACertFile       string
+	PinnedCertSHA256 []string
+	ClientCertPEM    string
+	ClientCertFile   string
+	ClientKeyPEM     string
+	ClientKeyFile    string
+}
+
+// tlsMaterial is the parsed form of tlsSettings.
+type tlsMaterial struct {
+	// RootCAs is nil to use the system roots only.
+	RootCAs *x509.CertPool
+	// PinnedCertSHA256 are SHA-256 digests of accepted leaf certificates.
+	PinnedCertSHA256 [][sha256.Size]byte
+	ClientCert       *tls.Certificate
+}
+
+// settingError is an error in a single provider argument.
+type settingError struct {
+	Attribute string
+	Err       error
+}
+
+func (e *settingError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Attribute, e.Err)
+}
+
+func (e *settingError) Unwrap() error {
+	return e.Err
+}
+
+// load reads and parses the configured certificates and keys.
+func (s tlsSettings) load() (tlsMaterial, error) {
+	var m tlsMaterial
+
+	caPEM, err := pemSetting("ca_cert_pem", s.CACertPEM, "ca_cert_file", s.CACertFile)
+	if err != nil {
+		return m, err
+	}
+	if caPEM != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return m, &settingError{
+				Attribute: pemAttribute("ca_cert", s.CACertFile),
+				Err:       errors.New("no PEM-encoded certificates found"),
+			}
+		}
+		m.RootCAs = pool
+	}
+
+	for _, pin := range s.PinnedCertSHA256 {
+		digest, err := parseCertFingerprint(pin)
+		if err != nil {
+			return m, &settingError{Attribute: "pinned_cert_sha256", Err: err}
+		}
+		m.PinnedCertSHA256 = append(m.PinnedCertSHA256, digest)
+	}
+
+	certPEM, err := pemSetting("client_cert_pem", s.ClientCertPEM, "client_cert_file", s.ClientCertFile)
+	if err != nil {
+		return m, err
+	}
+	keyPEM, err := pemSetting("client_key_pem", s.ClientKeyPEM, "client_key_file", s.ClientKeyFile)
+	if err != nil {
+		return m, err
+	}
+	switch {
+	case certPEM != nil && keyPEM != nil:
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return m, &settingError{Attribute: pemAttribute("client_cert", s.ClientCertFile), Err: err}
+		}
+		m.ClientCert = &cert
+	case certPEM != nil:
+		return m, &settingError{
+			Attribute: pemAttribute("client_key", s.ClientKeyFile),
+			Err:       errors.New("a client certificate requires a client key"),
+		}
+	case keyPEM != nil:
+		return m, &settingError{
+			Attribute: pemAttribute("client_cert", s.ClientCertFile),
+			Err:       errors.New("a client key requires a client certificate"),
+		}
+	}
+
+	return m, nil
+}
+
+// pemSetting returns the inline PEM or the contents of the file, or nil if
+// neither is set.
+func pemSetting(pemAttr, pem, fileAttr, file string) ([]byte, error) {
+	switch {
+	case pem != "" && file != "":
+		return nil, &settingError{
+			Attribute: fileAttr,
+			Err:       fmt.Errorf("only one of %s and %s can be set", pemAttr, fileAttr),
+		}
+	case pem != "":
+		return []byte(pem), nil
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, &settingError{Attribute: fileAttr, Err: err}
+		}
+		return b, nil
+	}
+	return nil, nil
+}
+
+// pemAttribute returns the name of the _file or _pem variant of a setting,
+// whichever the value came from.
+func pemAttribute(prefix, file string) string {
+	if file != "" {
+		return prefix + "_file"
+	}
+	return prefix + "_pem"
+}
+
+// parseCertFingerprint parses a SHA-256 fingerprint in hex, with or without
+// colons, such as the output of `openssl x509 -noout -fingerprint -sha256`.
+func parseCertFingerprint(s string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+
+	normalized := strings.TrimSpace(s)
+	if i := strings.Index(normalized, "="); i >= 0 {
+		// "SHA256 Fingerprint=AB:CD:..."
+		normalized = normalized[i+1:]
+	}
+	normalized = strings.NewReplacer(":", "", " ", "").Replace(normalized)
+
+	raw, err := hex.DecodeString(normalized)
+	if err != nil || len(raw) != sha256.Size {
+		return digest, fmt.Errorf("%q is not a SHA-256 fingerprint", s)
+	}
+	copy(digest[:], raw)
+	return digest, nil
+}
+
+// errPinMismatch is returned by the TLS handshake when the controller's
+// certificate is not one of the pinned certificates.
+var errPinMismatch = errors.New("server certificate does not match any pinned_cert_sha256")
+
+// verifyPinnedCert accepts a connection only if the server's leaf certificate
+// matches one of the pins.
+func verifyPinnedCert(pins [][sha256.Size]byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("%w: server presented no certificate", errPinMismatch)
+		}
+		digest := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		for _, pin := range pins {
+			if digest == pin {
+				return nil
+			}
+		}
+		return fmt.Errorf(
+			"%w: server certificate fingerprint is %s",
+			errPinMismatch,
+			strings.ToUpper(hex.EncodeToString(digest[:])),
+		)
+	}
+}
+
+// newTLSConfig returns the client TLS configuration for the controller.
+func newTLSConfig(allowInsecure bool, m tlsMaterial) *tls.Config {
+	cfg := &tls.Config{
+		InsecureSkipVerify: allowInsecure,
+		RootCAs:            m.RootCAs,
+	}
+
+	if len(m.PinnedCertSHA256) > 0 {
+		// A pinned certificate is trusted on its own, so self-signed console
+		// certificates work without allow_insecure. The chain is not verified.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = verifyPinnedCert(m.PinnedCertSHA256)
+	}
+
+	if m.ClientCert != nil {
+		cfg.Certificates = []tls.Certificate{*m.ClientCert}
+	}
+
+	return cfg
+}
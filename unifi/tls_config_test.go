@@ -0,0 +1,188 @@
+package unifi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCertPEM(t *testing.T, cert *x509.Certificate) string {
+	t.Helper()
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func testClientKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func tlsGet(t *testing.T, settings tlsSettings, url string) error {
+	t.Helper()
+	m, err := settings.load()
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	c, err := newHTTPClient(transportConfig{TLS: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func Test_parseCertFingerprint(t *testing.T) {
+	digest := sha256.Sum256([]byte("certificate"))
+	plain := hex.EncodeToString(digest[:])
+	var colons []string
+	for i := 0; i < len(plain); i += 2 {
+		colons = append(colons, strings.ToUpper(plain[i:i+2]))
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{"lowercase hex", plain, false},
+		{"uppercase with colons", strings.Join(colons, ":"), false},
+		{"openssl output", "sha256 Fingerprint=" + strings.Join(colons, ":") + "\n", false},
+		{"too short", plain[:62], true},
+		{"not hex", strings.Repeat("z", 64), true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCertFingerprint(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCertFingerprint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != digest {
+				t.Errorf("parseCertFingerprint() = %x, want %x", got, digest)
+			}
+		})
+	}
+}
+
+func Test_tlsSettings_load_errors(t *testing.T) {
+	certPEM, _ := testClientKeyPair(t)
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(file, []byte(certPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		settings      tlsSettings
+		wantAttribute string
+	}{
+		{"pem and file", tlsSettings{CACertPEM: certPEM, CACertFile: file}, "ca_cert_file"},
+		{"missing file", tlsSettings{CACertFile: file + ".missing"}, "ca_cert_file"},
+		{"not pem", tlsSettings{CACertPEM: "not a certificate"}, "ca_cert_pem"},
+		{"bad pin", tlsSettings{PinnedCertSHA256: []string{"abc"}}, "pinned_cert_sha256"},
+		{"cert without key", tlsSettings{ClientCertPEM: certPEM}, "client_key_pem"},
+		{"key without cert", tlsSettings{ClientKeyFile: file}, "client_cert_pem"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.settings.load()
+			var settingErr *settingError
+			if !errors.As(err, &settingErr) {
+				t.Fatalf("load() error = %v, want a settingError", err)
+			}
+			if settingErr.Attribute != tt.wantAttribute {
+				t.Errorf("attribute = %q, want %q", settingErr.Attribute, tt.wantAttribute)
+			}
+		})
+	}
+}
+
+func Test_tlsSettings_serverVerification(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	digest := sha256.Sum256(srv.Certificate().Raw)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(testCertPEM(t, srv.Certificate())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings tlsSettings
+		wantErr  bool
+	}{
+		{"system roots only", tlsSettings{}, true},
+		{"ca pem", tlsSettings{CACertPEM: testCertPEM(t, srv.Certificate())}, false},
+		{"ca file", tlsSettings{CACertFile: caFile}, false},
+		{"matching pin", tlsSettings{PinnedCertSHA256: []string{
+			strings.Repeat("00", sha256.Size),
+			hex.EncodeToString(digest[:]),
+		}}, false},
+		{"mismatched pin", tlsSettings{PinnedCertSHA256: []string{strings.Repeat("00", sha256.Size)}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tlsGet(t, tt.settings, srv.URL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GET error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_tlsSettings_clientCertificate(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	ca := testCertPEM(t, srv.Certificate())
+
+	if err := tlsGet(t, tlsSettings{CACertPEM: ca}, srv.URL); err == nil {
+		t.Error("expected the server to reject a connection without a client certificate")
+	}
+
+	certPEM, keyPEM := testClientKeyPair(t)
+	if err := tlsGet(t, tlsSettings{CACertPEM: ca, ClientCertPEM: certPEM, ClientKeyPEM: keyPEM}, srv.URL); err != nil {
+		t.Errorf("GET with client certificate error = %v", err)
+	}
+}
@@ -34,8 +34,13 @@
 	_ resource.Resource                = &trafficRouteResource{}
 	_ resource.ResourceWithImportState = &trafficRouteResource{}
 	_ resource.ResourceWithIdentity    = &trafficRouteResource{}
+	_ resource.ResourceWithModifyPlan  = &trafficRouteResource{}
 )
 
+// trafficRouteMinimumVersion is the oldest Network Application release with
+// the v2 traffic routes API.
+const trafficRouteMinimumVersion = "7.3.0"
+
 // Ensure provider defined types fully satisfy list interfaces.
 var (
 	_ list.ListResource              = &trafficRouteResource{}
@@ -346,6 +351,18 @@ func (r *trafficRouteResource) Configure(
 	r.client = client
 }
 
+func (r *trafficRouteResource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if req.Plan.Raw.IsNull() {
+		return // resource is being destroyed
+	}
+
+	resp.Diagnostics.Append(r.client.checkMinimumVersion("unifi_traffic_route", trafficRouteMinimumVersion)...)
+}
+
 func (r *trafficRouteResource) Create(
 	ctx context.Context,
 	req resource.CreateRequest,
@@ -0,0 +1,151 @@
+package unifi
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/cookiejar"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+)
+
+// transportConfig holds the provider settings that are enforced on the HTTP
+// client handed to go-unifi. Resources call the embedded *ui.ApiClient
+// directly, so the transport is the one place a setting reliably applies to
+// every resource, data source, list resource and action sharing a provider
+// configuration.
+type transportConfig struct {
+	AllowInsecure bool
+	TLS           tlsMaterial
+
+	// MaxConcurrentRequests caps the number of requests in flight. Zero means
+	// no limit.
+	MaxConcurrentRequests int64
+	// RequestsPerSecond is the steady-state request rate. Zero means no limit.
+	RequestsPerSecond float64
+
+	Retry retryConfig
+
+	// ReadOnly refuses every request that could change the controller.
+	ReadOnly bool
+	// AuditLog records every request that can change the controller. Nil
+	// disables auditing.
+	AuditLog *auditLog
+}
+
+// newHTTPClient returns the HTTP client used for all controller requests.
+func newHTTPClient(cfg transportConfig) (*http.Client, error) {
+	base := &http.Transport{}
+	if dt, ok := http.DefaultTransport.(*http.Transport); ok {
+		base = dt.Clone()
+	}
+	base.TLSClientConfig = newTLSConfig(cfg.AllowInsecure, cfg.TLS)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retries sit outside the rate limiter so every attempt, and any login
+	// needed to renew a session, waits for its turn.
+	var transport http.RoundTripper = base
+	transport = newRateLimitedTransport(transport, cfg.MaxConcurrentRequests, cfg.RequestsPerSecond)
+	transport = newRetryTransport(transport, jar, cfg.Retry)
+	transport = &csrfTransport{next: transport}
+	// The read cache sits outermost so cache hits skip the rate limiter.
+	transport = newReadCacheTransport(transport)
+	// Auditing outside the retries records each change once, with its final
+	// outcome.
+	transport = newAuditTransport(transport, cfg.AuditLog)
+	// Refused writes must not be audited, invalidate the read cache or use up
+	// a slot.
+	if cfg.ReadOnly {
+		transport = &readOnlyTransport{next: transport}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Jar:       jar,
+	}, nil
+}
+
+// rateLimitedTransport throttles requests with a token bucket and bounds the
+// number in flight with a semaphore. A slot is held until the response body is
+// closed, since the controller is still busy sending it until then.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+	sem     *semaphore.Weighted
+}
+
+// newRateLimitedTransport wraps next. It returns next unchanged when neither
+// limit is set.
+func newRateLimitedTransport(
+	next http.RoundTripper,
+	maxConcurrent int64,
+	requestsPerSecond float64,
+) http.RoundTripper {
+	if maxConcurrent <= 0 && requestsPerSecond <= 0 {
+		return next
+	}
+
+	t := &rateLimitedTransport{next: next}
+	if maxConcurrent > 0 {
+		t.sem = semaphore.NewWeighted(maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		// Allow a one-second burst so a refresh can start promptly, then settle
+		// to the configured rate.
+		burst := int(math.Ceil(requestsPerSecond))
+		t.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+	return t
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.sem != nil {
+		if err := t.sem.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+	release := func() {
+		if t.sem != nil {
+			t.sem.Release(1)
+		}
+	}
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseOnClose calls release exactly once, when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	closed  bool
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	if !b.closed {
+		b.closed = true
+		b.release()
+	}
+	return err
+}
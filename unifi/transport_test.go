@@ -0,0 +1,144 @@
+package unifi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_newHTTPClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "unifises", Value: "session"})
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	t.Run("verifies certificates by default", func(t *testing.T) {
+		c, err := newHTTPClient(transportConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Get(srv.URL); err == nil {
+			t.Fatal("expected a certificate error for a self-signed server")
+		}
+	})
+
+	t.Run("allow insecure", func(t *testing.T) {
+		c, err := newHTTPClient(transportConfig{AllowInsecure: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if c.Jar == nil || len(c.Jar.Cookies(resp.Request.URL)) == 0 {
+			t.Error("session cookie was not stored")
+		}
+	})
+}
+
+func Test_newRateLimitedTransport_unlimited(t *testing.T) {
+	next := http.DefaultTransport
+	if got := newRateLimitedTransport(next, 0, 0); got != next {
+		t.Errorf("expected the transport to be returned unchanged without limits, got %T", got)
+	}
+}
+
+func Test_rateLimitedTransport_maxConcurrent(t *testing.T) {
+	var inFlight, peak atomic.Int64
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: newRateLimitedTransport(http.DefaultTransport, 2, 0)}
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrent requests = %d, want 2", got)
+	}
+}
+
+func Test_rateLimitedTransport_requestsPerSecond(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// A burst of 20 is allowed immediately; the next 10 take about 0.5s at 20/s.
+	c := &http.Client{Transport: newRateLimitedTransport(http.DefaultTransport, 0, 20)}
+
+	start := time.Now()
+	for range 30 {
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("30 requests at 20/s took %s, want at least 400ms", elapsed)
+	}
+}
+
+func Test_rateLimitedTransport_contextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	tr := newRateLimitedTransport(http.DefaultTransport, 1, 0)
+
+	// Hold the only slot by leaving the body open.
+	first, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := tr.RoundTrip(req); err == nil {
+		t.Error("expected the request to fail when the context expires while waiting for a slot")
+	}
+
+	first.Body.Close()
+	// Closing twice must not release the slot twice.
+	first.Body.Close()
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
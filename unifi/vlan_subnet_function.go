@@ -0,0 +1,148 @@
+package unifi
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &vlanSubnetFunction{}
+
+// NewVLANSubnetFunction returns a new instance of the vlan_subnet function.
+func NewVLANSubnetFunction() function.Function {
+	return &vlanSubnetFunction{}
+}
+
+type vlanSubnetFunction struct{}
+
+func (f *vlanSubnetFunction) Metadata(
+	ctx context.Context,
+	req function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "vlan_subnet"
+}
+
+func (f *vlanSubnetFunction) Definition(
+	ctx context.Context,
+	req function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Compute the subnet for a VLAN",
+		MarkdownDescription: "Carves the subnet for a VLAN out of an IPv4 base range, using the VLAN ID " +
+			"as the subnet number, and returns it in the gateway form `unifi_network` `subnet` " +
+			"expects. For example, `vlan_subnet(\"10.0.0.0/16\", 20, 24)` returns `10.0.20.1/24`.\n\n" +
+			"This is `cidrhost(cidrsubnet(base_cidr, prefix_length - <base prefix>, vlan_id), 1)` " +
+			"with the prefix appended, plus validation that the VLAN ID is valid and fits in the " +
+			"base range.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name: "base_cidr",
+				MarkdownDescription: "The IPv4 range to allocate VLAN subnets from, e.g. `10.0.0.0/16`. " +
+					"Its prefix must be `/29` or shorter.",
+			},
+			function.Int64Parameter{
+				Name:                "vlan_id",
+				MarkdownDescription: "The VLAN ID, between 1 and 4094.",
+			},
+			function.Int64Parameter{
+				Name: "prefix_length",
+				MarkdownDescription: "The prefix length of each VLAN subnet, e.g. `24`. It must be " +
+					"longer than the prefix of `base_cidr` and at most `30`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *vlanSubnetFunction) Run(
+	ctx context.Context,
+	req function.RunRequest,
+	resp *function.RunResponse,
+) {
+	var baseCIDR string
+	var vlanID, prefixLength int64
+
+	resp.Error = function.ConcatFuncErrors(
+		resp.Error,
+		req.Arguments.Get(ctx, &baseCIDR, &vlanID, &prefixLength),
+	)
+	if resp.Error != nil {
+		return
+	}
+
+	subnet, funcErr := vlanSubnet(baseCIDR, vlanID, prefixLength)
+	if funcErr != nil {
+		resp.Error = funcErr
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, subnet))
+}
+
+// vlanSubnet returns the gateway address and prefix of the vlanID-th subnet of
+// length prefixLength within baseCIDR.
+func vlanSubnet(baseCIDR string, vlanID, prefixLength int64) (string, *function.FuncError) {
+	base, err := netip.ParsePrefix(baseCIDR)
+	if err != nil || !base.Addr().Is4() {
+		return "", function.NewArgumentFuncError(
+			0,
+			fmt.Sprintf("%q is not an IPv4 CIDR", baseCIDR),
+		)
+	}
+	if base.Bits() > 29 {
+		return "", function.NewArgumentFuncError(
+			0,
+			fmt.Sprintf(
+				"%s is too small to hold VLAN subnets: the prefix must be /29 or shorter",
+				base,
+			),
+		)
+	}
+	base = base.Masked()
+
+	if vlanID < 1 || vlanID > 4094 {
+		return "", function.NewArgumentFuncError(
+			1,
+			fmt.Sprintf("VLAN ID must be between 1 and 4094, got %d", vlanID),
+		)
+	}
+
+	if prefixLength <= int64(base.Bits()) || prefixLength > 30 {
+		return "", function.NewArgumentFuncError(
+			2,
+			fmt.Sprintf(
+				"prefix length must be between %d and 30, got %d",
+				base.Bits()+1,
+				prefixLength,
+			),
+		)
+	}
+
+	newBits := prefixLength - int64(base.Bits())
+	if vlanID >= 1<<newBits {
+		return "", function.NewArgumentFuncError(
+			1,
+			fmt.Sprintf(
+				"VLAN %d does not fit in %s: a /%d only has room for %d subnets of /%d",
+				vlanID,
+				base,
+				base.Bits(),
+				1<<newBits,
+				prefixLength,
+			),
+		)
+	}
+
+	start := base.Addr().As4()
+	network := binary.BigEndian.Uint32(start[:]) + uint32(vlanID)<<(32-prefixLength)
+
+	var gateway [4]byte
+	binary.BigEndian.PutUint32(gateway[:], network+1)
+
+	return fmt.Sprintf("%s/%d", netip.AddrFrom4(gateway), prefixLength), nil
+}
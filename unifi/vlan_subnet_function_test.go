@@ -0,0 +1,79 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewVLANSubnetFunction(t *testing.T) {
+	if got := NewVLANSubnetFunction(); got == nil {
+		t.Fatal("NewVLANSubnetFunction() returned nil")
+	}
+}
+
+func Test_vlanSubnetFunction_Metadata(t *testing.T) {
+	f := &vlanSubnetFunction{}
+	resp := &function.MetadataResponse{}
+	f.Metadata(context.Background(), function.MetadataRequest{}, resp)
+	if resp.Name != "vlan_subnet" {
+		t.Errorf("Name = %q, want %q", resp.Name, "vlan_subnet")
+	}
+}
+
+func Test_vlanSubnetFunction_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		baseCIDR     string
+		vlanID       int64
+		prefixLength int64
+		want         string
+		wantArg      int64
+		wantErr      bool
+	}{
+		{"third octet", "10.0.0.0/16", 20, 24, "10.0.20.1/24", 0, false},
+		{"base is masked", "10.0.5.1/16", 20, 24, "10.0.20.1/24", 0, false},
+		{"highest vlan in a /8", "10.0.0.0/8", 4094, 20, "10.255.224.1/20", 0, false},
+		{"smaller subnets", "192.168.0.0/16", 3, 26, "192.168.0.193/26", 0, false},
+		{"ipv6 base", "fd00::/48", 20, 64, "", 0, true},
+		{"not a cidr", "10.0.0.0", 20, 24, "", 0, true},
+		{"vlan zero", "10.0.0.0/16", 0, 24, "", 1, true},
+		{"vlan too large", "10.0.0.0/16", 4095, 24, "", 1, true},
+		{"vlan does not fit", "10.0.0.0/20", 20, 24, "", 1, true},
+		{"prefix not longer than base", "10.0.0.0/24", 20, 24, "", 2, true},
+		{"prefix too long", "10.0.0.0/16", 20, 31, "", 2, true},
+		{"base too small", "10.0.0.0/30", 1, 30, "", 0, true},
+		{"smallest base", "10.0.0.8/29", 1, 30, "10.0.0.13/30", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &vlanSubnetFunction{}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+			f.Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tt.baseCIDR),
+					types.Int64Value(tt.vlanID),
+					types.Int64Value(tt.prefixLength),
+				}),
+			}, resp)
+
+			if (resp.Error != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", resp.Error, tt.wantErr)
+			}
+			if tt.wantErr {
+				if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != tt.wantArg {
+					t.Errorf("error argument = %v, want %d", resp.Error.FunctionArgument, tt.wantArg)
+				}
+				return
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("Run() = %s, want %q", got, tt.want)
+			}
+		})
+	}
+}
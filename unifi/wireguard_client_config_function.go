@@ -0,0 +1,203 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &wireguardClientConfigFunction{}
+
+// wireguardClientConfigServerAttrTypes is the subset of unifi_vpn_server read
+// by wireguard_client_config. Terraform drops the other attributes when the
+// whole resource is passed.
+var wireguardClientConfigServerAttrTypes = map[string]attr.Type{
+	"wireguard": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"public_key": types.StringType,
+		"port":       types.Int64Type,
+	}},
+	"dns": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"servers": types.ListType{ElemType: types.StringType},
+	}},
+}
+
+// wireguardClientConfigPeerAttrTypes is the subset of unifi_wireguard_peer
+// read by wireguard_client_config.
+var wireguardClientConfigPeerAttrTypes = map[string]attr.Type{
+	"interface_ip": types.StringType,
+}
+
+type wireguardClientConfigServer struct {
+	WireGuard *struct {
+		PublicKey types.String `tfsdk:"public_key"`
+		Port      types.Int64  `tfsdk:"port"`
+	} `tfsdk:"wireguard"`
+	DNS *struct {
+		Servers []string `tfsdk:"servers"`
+	} `tfsdk:"dns"`
+}
+
+type wireguardClientConfigPeer struct {
+	InterfaceIP types.String `tfsdk:"interface_ip"`
+}
+
+// NewWireGuardClientConfigFunction returns a new instance of the
+// wireguard_client_config function.
+func NewWireGuardClientConfigFunction() function.Function {
+	return &wireguardClientConfigFunction{}
+}
+
+type wireguardClientConfigFunction struct{}
+
+func (f *wireguardClientConfigFunction) Metadata(
+	ctx context.Context,
+	req function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "wireguard_client_config"
+}
+
+func (f *wireguardClientConfigFunction) Definition(
+	ctx context.Context,
+	req function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Render a WireGuard client configuration for a peer",
+		MarkdownDescription: "Renders the `.conf` file a client needs to connect to a WireGuard " +
+			"`unifi_vpn_server` as the given `unifi_wireguard_peer`. The server and peer resources " +
+			"can be passed as a whole; only the attributes listed below are read.\n\n" +
+			"The output can be read back with `parse_wireguard_config`.",
+		Parameters: []function.Parameter{
+			function.ObjectParameter{
+				Name: "server",
+				MarkdownDescription: "The `unifi_vpn_server`. `wireguard.public_key` and " +
+					"`wireguard.port` are used for the `[Peer]` section, and `dns.servers`, when " +
+					"set, for the `DNS` line.",
+				AttributeTypes: wireguardClientConfigServerAttrTypes,
+			},
+			function.ObjectParameter{
+				Name: "peer",
+				MarkdownDescription: "The `unifi_wireguard_peer`. `interface_ip` becomes the " +
+					"client's `Address`.",
+				AttributeTypes: wireguardClientConfigPeerAttrTypes,
+			},
+			function.StringParameter{
+				Name: "private_key",
+				MarkdownDescription: "The client's private key, matching the peer's `public_key`. " +
+					"See `unifi_wireguard_keypair`.",
+			},
+			function.StringParameter{
+				Name: "endpoint",
+				MarkdownDescription: "The public host name or address clients connect to. The " +
+					"server's `wireguard.port` is appended unless a port is given.",
+			},
+			function.ListParameter{
+				Name: "allowed_ips",
+				MarkdownDescription: "The CIDRs to route through the tunnel, e.g. " +
+					"`[\"0.0.0.0/0\"]` for a full tunnel.",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *wireguardClientConfigFunction) Run(
+	ctx context.Context,
+	req function.RunRequest,
+	resp *function.RunResponse,
+) {
+	var server wireguardClientConfigServer
+	var peer wireguardClientConfigPeer
+	var privateKey, endpoint string
+	var allowedIPs []string
+
+	resp.Error = function.ConcatFuncErrors(
+		resp.Error,
+		req.Arguments.Get(ctx, &server, &peer, &privateKey, &endpoint, &allowedIPs),
+	)
+	if resp.Error != nil {
+		return
+	}
+
+	if server.WireGuard == nil || server.WireGuard.PublicKey.ValueString() == "" {
+		resp.Error = function.NewArgumentFuncError(
+			0,
+			"server is not a WireGuard server: wireguard.public_key is not set",
+		)
+		return
+	}
+	if peer.InterfaceIP.ValueString() == "" {
+		resp.Error = function.NewArgumentFuncError(1, "peer interface_ip is not set")
+		return
+	}
+	if privateKey == "" {
+		resp.Error = function.NewArgumentFuncError(2, "private_key must not be empty")
+		return
+	}
+	if endpoint == "" {
+		resp.Error = function.NewArgumentFuncError(3, "endpoint must not be empty")
+		return
+	}
+
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		port := server.WireGuard.Port.ValueInt64()
+		if port == 0 {
+			port = 51820
+		}
+		endpoint = net.JoinHostPort(strings.Trim(endpoint, "[]"), strconv.FormatInt(port, 10))
+	}
+
+	address := peer.InterfaceIP.ValueString()
+	if !strings.Contains(address, "/") {
+		address += "/32"
+	}
+
+	var dns []string
+	if server.DNS != nil {
+		dns = server.DNS.Servers
+	}
+
+	config := renderWireGuardConfig(&wireguardConfigParsed{
+		PrivateKey: privateKey,
+		Address:    address,
+		DNS:        dns,
+		PublicKey:  server.WireGuard.PublicKey.ValueString(),
+		Endpoint:   endpoint,
+		AllowedIPs: strings.Join(allowedIPs, ", "),
+	})
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, config))
+}
+
+// renderWireGuardConfig is the inverse of parseWireGuardConfig. Empty fields are
+// left out.
+func renderWireGuardConfig(c *wireguardConfigParsed) string {
+	var b strings.Builder
+
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s = %s\n", key, value)
+		}
+	}
+
+	b.WriteString("[Interface]\n")
+	line("PrivateKey", c.PrivateKey)
+	line("Address", c.Address)
+	line("DNS", strings.Join(c.DNS, ", "))
+
+	b.WriteString("\n[Peer]\n")
+	line("PublicKey", c.PublicKey)
+	line("PresharedKey", c.PresharedKey)
+	line("Endpoint", c.Endpoint)
+	line("AllowedIPs", c.AllowedIPs)
+
+	return b.String()
+}
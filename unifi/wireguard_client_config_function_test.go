@@ -0,0 +1,169 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewWireGuardClientConfigFunction(t *testing.T) {
+	if got := NewWireGuardClientConfigFunction(); got == nil {
+		t.Fatal("NewWireGuardClientConfigFunction() returned nil")
+	}
+}
+
+func Test_wireguardClientConfigFunction_Metadata(t *testing.T) {
+	f := &wireguardClientConfigFunction{}
+	resp := &function.MetadataResponse{}
+	f.Metadata(context.Background(), function.MetadataRequest{}, resp)
+	if resp.Name != "wireguard_client_config" {
+		t.Errorf("Name = %q, want %q", resp.Name, "wireguard_client_config")
+	}
+}
+
+func testWireGuardServerObject(publicKey string, port int64, dns []string) types.Object {
+	wgType, _ := wireguardClientConfigServerAttrTypes["wireguard"].(types.ObjectType)
+	dnsType, _ := wireguardClientConfigServerAttrTypes["dns"].(types.ObjectType)
+
+	wg := types.ObjectNull(wgType.AttrTypes)
+	if publicKey != "" {
+		wg = types.ObjectValueMust(wgType.AttrTypes, map[string]attr.Value{
+			"public_key": types.StringValue(publicKey),
+			"port":       types.Int64Value(port),
+		})
+	}
+
+	dnsObj := types.ObjectNull(dnsType.AttrTypes)
+	if dns != nil {
+		servers := make([]attr.Value, len(dns))
+		for i, s := range dns {
+			servers[i] = types.StringValue(s)
+		}
+		dnsObj = types.ObjectValueMust(dnsType.AttrTypes, map[string]attr.Value{
+			"servers": types.ListValueMust(types.StringType, servers),
+		})
+	}
+
+	return types.ObjectValueMust(wireguardClientConfigServerAttrTypes, map[string]attr.Value{
+		"wireguard": wg,
+		"dns":       dnsObj,
+	})
+}
+
+func Test_wireguardClientConfigFunction_Run(t *testing.T) {
+	peer := types.ObjectValueMust(wireguardClientConfigPeerAttrTypes, map[string]attr.Value{
+		"interface_ip": types.StringValue("10.100.0.5"),
+	})
+	fullTunnel := types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("0.0.0.0/0"),
+		types.StringValue("::/0"),
+	})
+
+	tests := []struct {
+		name     string
+		server   types.Object
+		endpoint string
+		want     string
+		wantArg  int64
+		wantErr  bool
+	}{
+		{
+			name:     "server port appended",
+			server:   testWireGuardServerObject("c2VydmVyLXB1YmxpYw==", 51821, []string{"10.100.0.1"}),
+			endpoint: "vpn.example.com",
+			want: "[Interface]\n" +
+				"PrivateKey = Y2xpZW50LXByaXZhdGU=\n" +
+				"Address = 10.100.0.5/32\n" +
+				"DNS = 10.100.0.1\n" +
+				"\n[Peer]\n" +
+				"PublicKey = c2VydmVyLXB1YmxpYw==\n" +
+				"Endpoint = vpn.example.com:51821\n" +
+				"AllowedIPs = 0.0.0.0/0, ::/0\n",
+		},
+		{
+			name:     "explicit endpoint port and no dns",
+			server:   testWireGuardServerObject("c2VydmVyLXB1YmxpYw==", 51820, nil),
+			endpoint: "198.51.100.1:443",
+			want: "[Interface]\n" +
+				"PrivateKey = Y2xpZW50LXByaXZhdGU=\n" +
+				"Address = 10.100.0.5/32\n" +
+				"\n[Peer]\n" +
+				"PublicKey = c2VydmVyLXB1YmxpYw==\n" +
+				"Endpoint = 198.51.100.1:443\n" +
+				"AllowedIPs = 0.0.0.0/0, ::/0\n",
+		},
+		{
+			name:     "ipv6 endpoint",
+			server:   testWireGuardServerObject("c2VydmVyLXB1YmxpYw==", 51820, nil),
+			endpoint: "2001:db8::1",
+			want: "[Interface]\n" +
+				"PrivateKey = Y2xpZW50LXByaXZhdGU=\n" +
+				"Address = 10.100.0.5/32\n" +
+				"\n[Peer]\n" +
+				"PublicKey = c2VydmVyLXB1YmxpYw==\n" +
+				"Endpoint = [2001:db8::1]:51820\n" +
+				"AllowedIPs = 0.0.0.0/0, ::/0\n",
+		},
+		{
+			name:     "not a wireguard server",
+			server:   testWireGuardServerObject("", 0, nil),
+			endpoint: "vpn.example.com",
+			wantArg:  0,
+			wantErr:  true,
+		},
+		{
+			name:     "empty endpoint",
+			server:   testWireGuardServerObject("c2VydmVyLXB1YmxpYw==", 51820, nil),
+			endpoint: "",
+			wantArg:  3,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &wireguardClientConfigFunction{}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+			f.Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					tt.server,
+					peer,
+					types.StringValue("Y2xpZW50LXByaXZhdGU="),
+					types.StringValue(tt.endpoint),
+					fullTunnel,
+				}),
+			}, resp)
+
+			if (resp.Error != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", resp.Error, tt.wantErr)
+			}
+			if tt.wantErr {
+				if resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != tt.wantArg {
+					t.Errorf("error argument = %v, want %d", resp.Error.FunctionArgument, tt.wantArg)
+				}
+				return
+			}
+
+			result, ok := resp.Result.Value().(types.String)
+			if !ok {
+				t.Fatalf("Run() returned %T, want types.String", resp.Result.Value())
+			}
+			got := result.ValueString()
+			if got != tt.want {
+				t.Errorf("Run() =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			parsed, err := parseWireGuardConfig(got)
+			if err != nil {
+				t.Fatalf("rendered config does not parse: %v", err)
+			}
+			if parsed.Address != "10.100.0.5/32" || parsed.PublicKey != "c2VydmVyLXB1YmxpYw==" {
+				t.Errorf("round trip mismatch: %+v", parsed)
+			}
+		})
+	}
+}
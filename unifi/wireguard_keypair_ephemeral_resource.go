@@ -0,0 +1,115 @@
+package unifi
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResource = &wireguardKeypairEphemeralResource{}
+
+// NewWireguardKeypairEphemeralResource returns a new instance of the WireGuard
+// keypair ephemeral resource.
+func NewWireguardKeypairEphemeralResource() ephemeral.EphemeralResource {
+	return &wireguardKeypairEphemeralResource{}
+}
+
+// wireguardKeypairEphemeralResource generates a Curve25519 key pair locally.
+// It never talks to the controller, so it needs no client.
+type wireguardKeypairEphemeralResource struct{}
+
+type wireguardKeypairEphemeralResourceModel struct {
+	PrivateKey types.String `tfsdk:"private_key"`
+	PublicKey  types.String `tfsdk:"public_key"`
+}
+
+func (e *wireguardKeypairEphemeralResource) Metadata(
+	ctx context.Context,
+	req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_wireguard_keypair"
+}
+
+func (e *wireguardKeypairEphemeralResource) Schema(
+	ctx context.Context,
+	req ephemeral.SchemaRequest,
+	resp *ephemeral.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Generates a WireGuard (Curve25519) key pair locally, equivalent to " +
+			"`wg genkey | tee private.key | wg pubkey`. The keys are never written to state or plan, " +
+			"so the private key can be passed to write-only attributes such as " +
+			"`unifi_vpn_client` `private_key_wo` without being persisted. A new key pair is " +
+			"generated on every run.",
+
+		Attributes: map[string]schema.Attribute{
+			"private_key": schema.StringAttribute{
+				MarkdownDescription: "The base64-encoded private key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"public_key": schema.StringAttribute{
+				MarkdownDescription: "The base64-encoded public key derived from `private_key`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (e *wireguardKeypairEphemeralResource) Open(
+	ctx context.Context,
+	req ephemeral.OpenRequest,
+	resp *ephemeral.OpenResponse,
+) {
+	var data wireguardKeypairEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	privateKey, publicKey, err := generateWireGuardKeypair()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Generating WireGuard Key Pair",
+			fmt.Sprintf("Could not generate a WireGuard key pair: %s", err),
+		)
+		return
+	}
+
+	data.PrivateKey = types.StringValue(privateKey)
+	data.PublicKey = types.StringValue(publicKey)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// generateWireGuardKeypair returns a base64-encoded private and public key in
+// the format produced by `wg genkey` and `wg pubkey`.
+func generateWireGuardKeypair() (string, string, error) {
+	var raw [32]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", "", err
+	}
+
+	// Clamp the scalar as `wg genkey` does, so the stored private key matches
+	// what WireGuard tooling would have produced.
+	raw[0] &= 248
+	raw[31] &= 127
+	raw[31] |= 64
+
+	priv, err := ecdh.X25519().NewPrivateKey(raw[:])
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(priv.Bytes()),
+		base64.StdEncoding.EncodeToString(priv.PublicKey().Bytes()),
+		nil
+}
@@ -0,0 +1,80 @@
+package unifi
+
+import (
+	"context"
+	"crypto/ecdh"
+	"encoding/base64"
+	"testing"
+
+	fwephemeral "github.com/hashicorp/terraform-plugin-framework/ephemeral"
+)
+
+func TestNewWireguardKeypairEphemeralResource(t *testing.T) {
+	if got := NewWireguardKeypairEphemeralResource(); got == nil {
+		t.Fatal("NewWireguardKeypairEphemeralResource() returned nil")
+	}
+}
+
+func Test_wireguardKeypairEphemeralResource_Metadata(t *testing.T) {
+	e := &wireguardKeypairEphemeralResource{}
+	resp := &fwephemeral.MetadataResponse{}
+	e.Metadata(
+		context.Background(),
+		fwephemeral.MetadataRequest{ProviderTypeName: "unifi"},
+		resp,
+	)
+	if resp.TypeName != "unifi_wireguard_keypair" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_wireguard_keypair")
+	}
+}
+
+func Test_wireguardKeypairEphemeralResource_Schema(t *testing.T) {
+	e := &wireguardKeypairEphemeralResource{}
+	resp := &fwephemeral.SchemaResponse{}
+	e.Schema(context.Background(), fwephemeral.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("Schema() produced errors: %v", resp.Diagnostics)
+	}
+	for _, attr := range []string{"private_key", "public_key"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("missing attribute %q", attr)
+		}
+	}
+	if !resp.Schema.Attributes["private_key"].IsSensitive() {
+		t.Error("private_key must be sensitive")
+	}
+}
+
+func Test_generateWireGuardKeypair(t *testing.T) {
+	privateKey, publicKey, err := generateWireGuardKeypair()
+	if err != nil {
+		t.Fatalf("generateWireGuardKeypair() error = %v", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		t.Fatalf("private key is not base64: %v", err)
+	}
+	if len(raw) != 32 {
+		t.Fatalf("private key is %d bytes, want 32", len(raw))
+	}
+	if raw[0]&7 != 0 || raw[31]&128 != 0 || raw[31]&64 == 0 {
+		t.Errorf("private key is not clamped: first=%08b last=%08b", raw[0], raw[31])
+	}
+
+	priv, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := base64.StdEncoding.EncodeToString(priv.PublicKey().Bytes()); publicKey != want {
+		t.Errorf("public key = %q, want %q derived from the private key", publicKey, want)
+	}
+
+	otherPrivate, _, err := generateWireGuardKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherPrivate == privateKey {
+		t.Error("two calls returned the same private key")
+	}
+}
@@ -0,0 +1,563 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &wlanDataSource{}
+
+func NewWLANDataSource() datasource.DataSource {
+	return &wlanDataSource{}
+}
+
+// wlanDataSource defines the data source implementation.
+type wlanDataSource struct {
+	client *Client
+}
+
+// wlanDataSourceModel describes the data source data model. It has the
+// attributes of the resource except the passphrases.
+type wlanDataSourceModel struct {
+	// Lookup keys
+	ID   types.String `tfsdk:"id"`
+	Site types.String `tfsdk:"site"`
+	Name types.String `tfsdk:"name"`
+
+	NetworkID                   types.String `tfsdk:"network_id"`
+	UserGroupID                 types.String `tfsdk:"user_group_id"`
+	Security                    types.String `tfsdk:"security"`
+	WPA3Support                 types.Bool   `tfsdk:"wpa3_support"`
+	WPA3Transition              types.Bool   `tfsdk:"wpa3_transition"`
+	PMFMode                     types.String `tfsdk:"pmf_mode"`
+	HideSSID                    types.Bool   `tfsdk:"hide_ssid"`
+	IsGuest                     types.Bool   `tfsdk:"is_guest"`
+	Enabled                     types.Bool   `tfsdk:"enabled"`
+	ApGroupIDs                  types.Set    `tfsdk:"ap_group_ids"`
+	ApGroupMode                 types.String `tfsdk:"ap_group_mode"`
+	VLANEnabled                 types.Bool   `tfsdk:"vlan_enabled"`
+	VLAN                        types.Int64  `tfsdk:"vlan"`
+	WLANBand                    types.String `tfsdk:"wlan_band"`
+	WLANBands                   types.Set    `tfsdk:"wlan_bands"`
+	MulticastEnhance            types.Bool   `tfsdk:"multicast_enhance"`
+	MacFilter                   types.Object `tfsdk:"mac_filter"`
+	PrivatePresharedKeysEnabled types.Bool   `tfsdk:"private_preshared_keys_enabled"`
+	PrivatePresharedKeys        types.List   `tfsdk:"private_preshared_keys"`
+	RadiusProfileID             types.String `tfsdk:"radius_profile_id"`
+	NasIDentifierType           types.String `tfsdk:"nas_identifier_type"`
+	Schedule                    types.List   `tfsdk:"schedule"`
+	No2GhzOui                   types.Bool   `tfsdk:"no2ghz_oui"`
+	L2Isolation                 types.Bool   `tfsdk:"l2_isolation"`
+	ProxyArp                    types.Bool   `tfsdk:"proxy_arp"`
+	BssTransition               types.Bool   `tfsdk:"bss_transition"`
+	Uapsd                       types.Bool   `tfsdk:"uapsd"`
+	FastRoamingEnabled          types.Bool   `tfsdk:"fast_roaming_enabled"`
+	MinimumDataRate2GKbps       types.Int64  `tfsdk:"minimum_data_rate_2g_kbps"`
+	MinimumDataRate5GKbps       types.Int64  `tfsdk:"minimum_data_rate_5g_kbps"`
+	MinrateSettingPreference    types.String `tfsdk:"minrate_setting_preference"`
+	WPAMode                     types.String `tfsdk:"wpa_mode"`
+	WPAEnc                      types.String `tfsdk:"wpa_enc"`
+	DTIMMode                    types.String `tfsdk:"dtim_mode"`
+	DTIMNg                      types.Int64  `tfsdk:"dtim_ng"`
+	DTIMNa                      types.Int64  `tfsdk:"dtim_na"`
+	DTIM6E                      types.Int64  `tfsdk:"dtim_6e"`
+	GroupRekey                  types.Int64  `tfsdk:"group_rekey"`
+	IappEnabled                 types.Bool   `tfsdk:"iapp_enabled"`
+	WPA3FastRoaming             types.Bool   `tfsdk:"wpa3_fast_roaming"`
+	WPA3Enhanced192             types.Bool   `tfsdk:"wpa3_enhanced_192"`
+	RADIUSMacAuthEnabled        types.Bool   `tfsdk:"radius_mac_auth_enabled"`
+	EnhancedIot                 types.Bool   `tfsdk:"enhanced_iot"`
+	Hotspot2ConfEnabled         types.Bool   `tfsdk:"hotspot2conf_enabled"`
+	MloEnabled                  types.Bool   `tfsdk:"mlo_enabled"`
+	BroadcastFilterList         types.Set    `tfsdk:"bc_filter_list"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// wlanDataSourcePrivatePresharedKeyAttrTypes returns the attribute types of a
+// private pre-shared key without its password.
+func wlanDataSourcePrivatePresharedKeyAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"network_id": types.StringType,
+	}
+}
+
+func (d *wlanDataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_wlan"
+}
+
+func (d *wlanDataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`unifi_wlan` data source can be used to retrieve settings for a WiFi network " +
+			"by name or ID. Passphrases are not exported.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the WLAN.",
+				Computed:            true,
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("name"),
+					}...),
+				},
+			},
+			"site": schema.StringAttribute{
+				MarkdownDescription: "The name of the site the WLAN is associated with.",
+				Computed:            true,
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The SSID of the network.",
+				Computed:            true,
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("id"),
+					}...),
+				},
+			},
+			"network_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the network for this WLAN.",
+				Computed:            true,
+			},
+			"user_group_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the user group used for this network.",
+				Computed:            true,
+			},
+			"security": schema.StringAttribute{
+				MarkdownDescription: "The type of WiFi security for this network.",
+				Computed:            true,
+			},
+			"wpa3_support": schema.BoolAttribute{
+				MarkdownDescription: "Whether WPA 3 support is enabled.",
+				Computed:            true,
+			},
+			"wpa3_transition": schema.BoolAttribute{
+				MarkdownDescription: "Whether WPA 3 and WPA 2 are both supported.",
+				Computed:            true,
+			},
+			"pmf_mode": schema.StringAttribute{
+				MarkdownDescription: "The Protected Management Frames mode.",
+				Computed:            true,
+			},
+			"hide_ssid": schema.BoolAttribute{
+				MarkdownDescription: "Whether the SSID is hidden from broadcast.",
+				Computed:            true,
+			},
+			"is_guest": schema.BoolAttribute{
+				MarkdownDescription: "Whether this is a guest WLAN.",
+				Computed:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the WLAN is enabled.",
+				Computed:            true,
+			},
+			"ap_group_ids": schema.SetAttribute{
+				MarkdownDescription: "IDs of the AP groups this WLAN is applied to.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"ap_group_mode": schema.StringAttribute{
+				MarkdownDescription: "Access point group mode.",
+				Computed:            true,
+			},
+			"vlan_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether VLAN tagging is enabled.",
+				Computed:            true,
+			},
+			"vlan": schema.Int64Attribute{
+				MarkdownDescription: "VLAN ID.",
+				Computed:            true,
+			},
+			"wlan_band": schema.StringAttribute{
+				MarkdownDescription: "WLAN band.",
+				Computed:            true,
+			},
+			"wlan_bands": schema.SetAttribute{
+				MarkdownDescription: "List of WLAN bands.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"multicast_enhance": schema.BoolAttribute{
+				MarkdownDescription: "Whether Multicast Enhance is turned on for the network.",
+				Computed:            true,
+			},
+			"mac_filter": schema.SingleNestedAttribute{
+				MarkdownDescription: "MAC address filtering configuration.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						MarkdownDescription: "Whether the MAC filter is turned on for the network.",
+						Computed:            true,
+					},
+					"list": schema.SetAttribute{
+						MarkdownDescription: "List of MAC addresses to filter.",
+						Computed:            true,
+						ElementType:         types.StringType,
+					},
+					"policy": schema.StringAttribute{
+						MarkdownDescription: "MAC address filter policy.",
+						Computed:            true,
+					},
+				},
+			},
+			"private_preshared_keys_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether per-key (PPSK) passphrases are enabled for this WLAN.",
+				Computed:            true,
+			},
+			"private_preshared_keys": schema.ListNestedAttribute{
+				MarkdownDescription: "Private pre-shared keys (PPSK) of the WLAN, without their passphrases.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"network_id": schema.StringAttribute{
+							MarkdownDescription: "ID of the network/VLAN this key is bound to.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"radius_profile_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the RADIUS profile used when security is `wpaeap`.",
+				Computed:            true,
+			},
+			"nas_identifier_type": schema.StringAttribute{
+				MarkdownDescription: "NAS identifier type for RADIUS.",
+				Computed:            true,
+			},
+			"schedule": schema.ListNestedAttribute{
+				MarkdownDescription: "Start and stop schedules for the WLAN.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"day_of_week": schema.StringAttribute{
+							MarkdownDescription: "Day of week for the block.",
+							Computed:            true,
+						},
+						"start_hour": schema.Int64Attribute{
+							MarkdownDescription: "Start hour for the block (0-23).",
+							Computed:            true,
+						},
+						"start_minute": schema.Int64Attribute{
+							MarkdownDescription: "Start minute for the block (0-59).",
+							Computed:            true,
+						},
+						"duration": schema.StringAttribute{
+							MarkdownDescription: "Length of the block, as a Go duration string.",
+							CustomType:          timetypes.GoDurationType{},
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the block.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"no2ghz_oui": schema.BoolAttribute{
+				MarkdownDescription: "Whether high performance clients are connected to 5 GHz only.",
+				Computed:            true,
+			},
+			"l2_isolation": schema.BoolAttribute{
+				MarkdownDescription: "Whether stations are isolated on layer 2 (ethernet) level.",
+				Computed:            true,
+			},
+			"proxy_arp": schema.BoolAttribute{
+				MarkdownDescription: "Whether APs \"proxy\" common broadcast frames as unicast.",
+				Computed:            true,
+			},
+			"bss_transition": schema.BoolAttribute{
+				MarkdownDescription: "Whether clients are given connection details of nearby APs.",
+				Computed:            true,
+			},
+			"uapsd": schema.BoolAttribute{
+				MarkdownDescription: "Whether Unscheduled Automatic Power Save Delivery is enabled.",
+				Computed:            true,
+			},
+			"fast_roaming_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether fast roaming, aka 802.11r, is enabled.",
+				Computed:            true,
+			},
+			"minimum_data_rate_2g_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Minimum data rate for 2G clients in Kbps.",
+				Computed:            true,
+			},
+			"minimum_data_rate_5g_kbps": schema.Int64Attribute{
+				MarkdownDescription: "Minimum data rate for 5G clients in Kbps.",
+				Computed:            true,
+			},
+			"minrate_setting_preference": schema.StringAttribute{
+				MarkdownDescription: "Minimum rate setting preference.",
+				Computed:            true,
+			},
+			"wpa_mode": schema.StringAttribute{
+				MarkdownDescription: "WPA mode. One of `auto`, `wpa1`, or `wpa2`.",
+				Computed:            true,
+			},
+			"wpa_enc": schema.StringAttribute{
+				MarkdownDescription: "WPA encryption. One of `auto`, `ccmp`, `gcmp`, `ccmp-256`, or `gcmp-256`.",
+				Computed:            true,
+			},
+			"dtim_mode": schema.StringAttribute{
+				MarkdownDescription: "DTIM mode. One of `default` or `custom`.",
+				Computed:            true,
+			},
+			"dtim_ng": schema.Int64Attribute{
+				MarkdownDescription: "DTIM period for the 2.4 GHz band.",
+				Computed:            true,
+			},
+			"dtim_na": schema.Int64Attribute{
+				MarkdownDescription: "DTIM period for the 5 GHz band.",
+				Computed:            true,
+			},
+			"dtim_6e": schema.Int64Attribute{
+				MarkdownDescription: "DTIM period for the 6 GHz band.",
+				Computed:            true,
+			},
+			"group_rekey": schema.Int64Attribute{
+				MarkdownDescription: "Group rekey interval in seconds (0 when disabled).",
+				Computed:            true,
+			},
+			"iapp_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether Inter-Access Point Protocol (802.11f) is enabled.",
+				Computed:            true,
+			},
+			"wpa3_fast_roaming": schema.BoolAttribute{
+				MarkdownDescription: "Whether WPA3 fast roaming (802.11r) is enabled.",
+				Computed:            true,
+			},
+			"wpa3_enhanced_192": schema.BoolAttribute{
+				MarkdownDescription: "Whether WPA3 Enterprise 192-bit mode is enabled.",
+				Computed:            true,
+			},
+			"radius_mac_auth_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether RADIUS MAC authentication is enabled.",
+				Computed:            true,
+			},
+			"enhanced_iot": schema.BoolAttribute{
+				MarkdownDescription: "Whether enhanced IoT connectivity is enabled.",
+				Computed:            true,
+			},
+			"hotspot2conf_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether Hotspot 2.0 configuration is enabled.",
+				Computed:            true,
+			},
+			"mlo_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether Multi-Link Operation (6 GHz) is enabled.",
+				Computed:            true,
+			},
+			"bc_filter_list": schema.SetAttribute{
+				MarkdownDescription: "List of MAC addresses for the broadcast filter.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *wlanDataSource) Configure(
+	ctx context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *wlanDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var config wlanDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, timeoutDiags := config.Timeouts.Read(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	site := config.Site.ValueString()
+	if site == "" {
+		site = d.client.Site
+	}
+
+	var wlan *unifi.WLAN
+	var err error
+
+	switch {
+	case !config.ID.IsNull() && !config.ID.IsUnknown():
+		id := config.ID.ValueString()
+		wlan, err = d.client.GetWLAN(ctx, site, id)
+		if err != nil {
+			if _, ok := err.(*unifi.NotFoundError); ok {
+				resp.Diagnostics.AddError(
+					"WLAN Not Found",
+					fmt.Sprintf("WLAN with ID %s not found: %s", id, err),
+				)
+				return
+			}
+			resp.Diagnostics.AddError(
+				"Error Reading WLAN",
+				fmt.Sprintf("Could not read WLAN with ID %s: %s", id, err),
+			)
+			return
+		}
+	case !config.Name.IsNull() && !config.Name.IsUnknown():
+		name := config.Name.ValueString()
+		wlan, err = d.client.GetWLANByName(ctx, site, name)
+		if err != nil {
+			if _, ok := err.(*unifi.NotFoundError); ok {
+				resp.Diagnostics.AddError(
+					"WLAN Not Found",
+					fmt.Sprintf("WLAN with name %s not found", name),
+				)
+				return
+			}
+			resp.Diagnostics.AddError(
+				"Error Reading WLAN",
+				fmt.Sprintf("Could not read WLAN with name %s: %s", name, err),
+			)
+			return
+		}
+	default:
+		resp.Diagnostics.AddError(
+			"Missing Required Attribute",
+			"Either 'id' or 'name' must be specified",
+		)
+		return
+	}
+
+	state, diags := wlanDataSourceState(ctx, wlan, site)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	state.Timeouts = config.Timeouts
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// wlanDataSourceState converts a WLAN to the data source model. It reuses the
+// resource's conversion and drops the passphrases.
+func wlanDataSourceState(
+	ctx context.Context,
+	wlan *unifi.WLAN,
+	site string,
+) (wlanDataSourceModel, diag.Diagnostics) {
+	var m wlanFrameworkResourceModel
+	m.PrivatePresharedKeys = types.ListNull(types.ObjectType{AttrTypes: wlanPrivatePresharedKeyModel{}.AttributeTypes()})
+	diags := (&wlanFrameworkResource{}).wlanToModel(ctx, wlan, &m, site)
+
+	ppskType := types.ObjectType{AttrTypes: wlanDataSourcePrivatePresharedKeyAttrTypes()}
+	ppsk := types.ListNull(ppskType)
+	if wlan.PrivatePresharedKeysEnabled && len(wlan.PrivatePresharedKeys) > 0 {
+		keys := make([]attr.Value, len(wlan.PrivatePresharedKeys))
+		for i, key := range wlan.PrivatePresharedKeys {
+			o, d := types.ObjectValue(ppskType.AttrTypes, map[string]attr.Value{
+				"network_id": types.StringValue(key.NetworkID),
+			})
+			diags.Append(d...)
+			keys[i] = o
+		}
+		var d diag.Diagnostics
+		ppsk, d = types.ListValue(ppskType, keys)
+		diags.Append(d...)
+	}
+
+	return wlanDataSourceModel{
+		ID:                          m.ID,
+		Site:                        m.Site,
+		Name:                        m.Name,
+		NetworkID:                   m.NetworkID,
+		UserGroupID:                 m.UserGroupID,
+		Security:                    m.Security,
+		WPA3Support:                 m.WPA3Support,
+		WPA3Transition:              m.WPA3Transition,
+		PMFMode:                     m.PMFMode,
+		HideSSID:                    m.HideSSID,
+		IsGuest:                     m.IsGuest,
+		Enabled:                     m.Enabled,
+		ApGroupIDs:                  m.ApGroupIDs,
+		ApGroupMode:                 m.ApGroupMode,
+		VLANEnabled:                 m.VLANEnabled,
+		VLAN:                        m.VLAN,
+		WLANBand:                    m.WLANBand,
+		WLANBands:                   m.WLANBands,
+		MulticastEnhance:            m.MulticastEnhance,
+		MacFilter:                   m.MacFilter,
+		PrivatePresharedKeysEnabled: m.PrivatePresharedKeysEnabled,
+		PrivatePresharedKeys:        ppsk,
+		RadiusProfileID:             m.RadiusProfileID,
+		NasIDentifierType:           m.NasIDentifierType,
+		Schedule:                    m.Schedule,
+		No2GhzOui:                   m.No2GhzOui,
+		L2Isolation:                 m.L2Isolation,
+		ProxyArp:                    m.ProxyArp,
+		BssTransition:               m.BssTransition,
+		Uapsd:                       m.Uapsd,
+		FastRoamingEnabled:          m.FastRoamingEnabled,
+		MinimumDataRate2GKbps:       m.MinimumDataRate2GKbps,
+		MinimumDataRate5GKbps:       m.MinimumDataRate5GKbps,
+		MinrateSettingPreference:    m.MinrateSettingPreference,
+		WPAMode:                     m.WPAMode,
+		WPAEnc:                      m.WPAEnc,
+		DTIMMode:                    m.DTIMMode,
+		DTIMNg:                      m.DTIMNg,
+		DTIMNa:                      m.DTIMNa,
+		DTIM6E:                      m.DTIM6E,
+		GroupRekey:                  m.GroupRekey,
+		IappEnabled:                 m.IappEnabled,
+		WPA3FastRoaming:             m.WPA3FastRoaming,
+		WPA3Enhanced192:             m.WPA3Enhanced192,
+		RADIUSMacAuthEnabled:        m.RADIUSMacAuthEnabled,
+		EnhancedIot:                 m.EnhancedIot,
+		Hotspot2ConfEnabled:         m.Hotspot2ConfEnabled,
+		MloEnabled:                  m.MloEnabled,
+		BroadcastFilterList:         m.BroadcastFilterList,
+	}, diags
+}
@@ -0,0 +1,134 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+func TestAccWLANDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWLANDataSourceConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.unifi_wlan.test", "id"),
+					resource.TestCheckResourceAttr("data.unifi_wlan.test", "security", "wpapsk"),
+					resource.TestCheckNoResourceAttr("data.unifi_wlan.test", "passphrase"),
+				),
+			},
+		},
+	})
+}
+
+func testAccWLANDataSourceConfig_basic() string {
+	return `
+data "unifi_wlan" "test" {
+	name = "wlan1"
+}
+`
+}
+
+func TestNewWLANDataSource(t *testing.T) {
+	d := NewWLANDataSource()
+	if d == nil {
+		t.Fatal("NewWLANDataSource() returned nil")
+	}
+	if _, ok := d.(fwdatasource.DataSourceWithConfigure); !ok {
+		t.Error("expected DataSourceWithConfigure interface")
+	}
+}
+
+func Test_wlanDataSource_Metadata(t *testing.T) {
+	d := &wlanDataSource{}
+	resp := &fwdatasource.MetadataResponse{}
+	d.Metadata(context.Background(), fwdatasource.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_wlan" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_wlan")
+	}
+}
+
+func Test_wlanDataSource_Schema(t *testing.T) {
+	d := &wlanDataSource{}
+	resp := &fwdatasource.SchemaResponse{}
+	d.Schema(context.Background(), fwdatasource.SchemaRequest{}, resp)
+	for _, attr := range []string{"id", "site", "name", "security", "mac_filter", "schedule", "private_preshared_keys", "timeouts"} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	for _, attr := range []string{"passphrase", "passphrase_wo"} {
+		if _, ok := resp.Schema.Attributes[attr]; ok {
+			t.Errorf("attribute %q should not be in the data source schema", attr)
+		}
+	}
+}
+
+func Test_wlanDataSource_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwdatasource.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwdatasource.ConfigureRequest{}, false},
+		{"wrong_type", fwdatasource.ConfigureRequest{ProviderData: "wrong"}, true},
+		{"correct_client", fwdatasource.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &wlanDataSource{}
+			resp := &fwdatasource.ConfigureResponse{}
+			d.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_wlanDataSourceState(t *testing.T) {
+	wlan := &unifi.WLAN{
+		ID:                          "wlan-id",
+		Name:                        "corp",
+		Security:                    "wpapsk",
+		Passphrase:                  "supersecret",
+		Enabled:                     true,
+		PrivatePresharedKeysEnabled: true,
+		PrivatePresharedKeys: []unifi.WLANPrivatePresharedKeys{
+			{NetworkID: "net-a", Password: "key-a"},
+			{NetworkID: "net-b", Password: "key-b"},
+		},
+	}
+
+	state, diags := wlanDataSourceState(context.Background(), wlan, "default")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if state.ID.ValueString() != "wlan-id" || state.Name.ValueString() != "corp" {
+		t.Errorf("ID, Name = %s, %s", state.ID, state.Name)
+	}
+	if state.Site.ValueString() != "default" {
+		t.Errorf("Site = %s, want default", state.Site)
+	}
+	if !state.Enabled.ValueBool() {
+		t.Error("Enabled = false, want true")
+	}
+
+	keys := state.PrivatePresharedKeys.Elements()
+	if len(keys) != 2 {
+		t.Fatalf("got %d private pre-shared keys, want 2", len(keys))
+	}
+	attrs := keys[1].(types.Object).Attributes()
+	if got := attrs["network_id"].(types.String).ValueString(); got != "net-b" {
+		t.Errorf("network_id = %q, want %q", got, "net-b")
+	}
+	if _, ok := attrs["password"]; ok {
+		t.Error("private pre-shared keys should not include the password")
+	}
+}
@@ -0,0 +1,334 @@
+package unifi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/action/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+// Ensure the implementation satisfies framework interfaces.
+var (
+	_ action.Action                   = &wlanRotatePassphraseAction{}
+	_ action.ActionWithConfigure      = &wlanRotatePassphraseAction{}
+	_ action.ActionWithValidateConfig = &wlanRotatePassphraseAction{}
+)
+
+// NewWLANRotatePassphraseAction returns a new instance of the WLAN passphrase
+// rotation action.
+func NewWLANRotatePassphraseAction() action.Action {
+	return &wlanRotatePassphraseAction{}
+}
+
+type wlanRotatePassphraseAction struct {
+	client *Client
+}
+
+// wlanRotatePassphraseActionModel describes the action request data model.
+type wlanRotatePassphraseActionModel struct {
+	WLANID                   types.String   `tfsdk:"wlan_id"`
+	PrivatePresharedKeyIndex types.Int64    `tfsdk:"private_preshared_key_index"`
+	Passphrase               types.String   `tfsdk:"passphrase"`
+	Length                   types.Int64    `tfsdk:"length"`
+	Special                  types.Bool     `tfsdk:"special"`
+	ExcludeAmbiguous         types.Bool     `tfsdk:"exclude_ambiguous"`
+	Path                     types.String   `tfsdk:"path"`
+	Timeouts                 timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (a *wlanRotatePassphraseAction) Metadata(
+	ctx context.Context,
+	req action.MetadataRequest,
+	resp *action.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_wlan_rotate_passphrase"
+}
+
+func (a *wlanRotatePassphraseAction) Schema(
+	ctx context.Context,
+	req action.SchemaRequest,
+	resp *action.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sets a new passphrase on a WLAN, or on one of its private pre-shared keys. " +
+			"Unless `passphrase` is given, a random passphrase is generated and written to the file at " +
+			"`path`; it is never shown in the action's output or written to plan or state. " +
+			"If the WLAN is managed with `unifi_wlan`, use `passphrase_wo` there rather than `passphrase`, " +
+			"which the next apply would set back; `passphrase_wo` is only sent when the resource is updated.",
+
+		Attributes: map[string]schema.Attribute{
+			"wlan_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the WLAN.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"private_preshared_key_index": schema.Int64Attribute{
+				MarkdownDescription: "The position of the key to rotate in the WLAN's private pre-shared " +
+					"keys, starting at `0`. When unset, the WLAN's passphrase is rotated.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"passphrase": schema.StringAttribute{
+				MarkdownDescription: "The new passphrase, for example from the `unifi_generated_passphrase` " +
+					"ephemeral resource. It is not reported in the action's output. When unset, one is generated.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(passphraseMinLength, passphraseMaxLength),
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("length"),
+						path.MatchRoot("special"),
+						path.MatchRoot("exclude_ambiguous"),
+						path.MatchRoot("path"),
+					),
+				},
+			},
+			"length": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf(
+					"Length of the generated passphrase, between %d and %d characters. Default: `%d`.",
+					passphraseMinLength,
+					passphraseMaxLength,
+					passphraseDefaultLength,
+				),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(passphraseMinLength, passphraseMaxLength),
+				},
+			},
+			"special": schema.BoolAttribute{
+				MarkdownDescription: "Whether the generated passphrase includes punctuation characters. Default: `false`.",
+				Optional:            true,
+			},
+			"exclude_ambiguous": schema.BoolAttribute{
+				MarkdownDescription: "Whether to leave out characters that are easily confused when typed " +
+					"from a printout (`0`, `O`, `1`, `l`, `I`, `|`). Default: `false`.",
+				Optional: true,
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Local path to write the generated passphrase to. The file is written " +
+					"with mode `0600` and replaces any existing file only once the passphrase is set, so a " +
+					"failed rotation keeps the previous passphrase. Parent directories are created as needed. " +
+					"Required unless `passphrase` is set.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (a *wlanRotatePassphraseAction) Configure(
+	ctx context.Context,
+	req action.ConfigureRequest,
+	resp *action.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+func (a *wlanRotatePassphraseAction) ValidateConfig(
+	ctx context.Context,
+	req action.ValidateConfigRequest,
+	resp *action.ValidateConfigResponse,
+) {
+	var config wlanRotatePassphraseActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Passphrase.IsNull() && config.Path.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Missing Required Attribute",
+			"\"path\" is required when the passphrase is generated, so the new passphrase is not lost. "+
+				"Set \"passphrase\" instead to rotate to a passphrase generated elsewhere.",
+		)
+	}
+}
+
+func (a *wlanRotatePassphraseAction) Invoke(
+	ctx context.Context,
+	req action.InvokeRequest,
+	resp *action.InvokeResponse,
+) {
+	resp.Diagnostics.Append(a.client.checkWritable("unifi_wlan_rotate_passphrase")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config wlanRotatePassphraseActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	invokeTimeout, timeoutDiags := config.Timeouts.Invoke(ctx, 2*time.Minute)
+	resp.Diagnostics.Append(timeoutDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, invokeTimeout)
+	defer cancel()
+
+	site := a.client.Site
+	id := config.WLANID.ValueString()
+
+	wlan, err := a.client.GetWLAN(ctx, site, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading WLAN",
+			"Could not read WLAN with ID "+id+": "+err.Error(),
+		)
+		return
+	}
+
+	passphrase := config.Passphrase.ValueString()
+	generated := passphrase == ""
+	if generated {
+		length := int64(passphraseDefaultLength)
+		if !config.Length.IsNull() {
+			length = config.Length.ValueInt64()
+		}
+		passphrase, err = generatePassphrase(int(length), config.Special.ValueBool(), config.ExcludeAmbiguous.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Generating Passphrase",
+				fmt.Sprintf("Could not generate a passphrase: %s", err),
+			)
+			return
+		}
+	}
+
+	target, err := setWLANPassphrase(wlan, config.PrivatePresharedKeyIndex.ValueInt64Pointer(), passphrase)
+	if err != nil {
+		resp.Diagnostics.AddError("Cannot Rotate Passphrase", err.Error())
+		return
+	}
+
+	// Stage the generated passphrase next to path before setting it, so it is
+	// never set without being kept, and only replace path once it is set.
+	dest := config.Path.ValueString()
+	var staged string
+	if generated {
+		staged, err = stagePrivateFile(dest, []byte(passphrase+"\n"))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("path"),
+				"Error Writing Passphrase",
+				fmt.Sprintf("Could not write the new passphrase next to %s: %s", dest, err),
+			)
+			return
+		}
+	}
+
+	if _, err := a.client.UpdateWLAN(ctx, site, wlan); err != nil {
+		if staged != "" {
+			_ = os.Remove(staged)
+		}
+		resp.Diagnostics.AddError(
+			"Error Updating WLAN",
+			"Could not update WLAN with ID "+id+": "+err.Error(),
+		)
+		return
+	}
+
+	if staged != "" {
+		if err := os.Rename(staged, dest); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("path"),
+				"Error Writing Passphrase",
+				fmt.Sprintf(
+					"The passphrase of %s was rotated, but could not be moved to %s: %s. "+
+						"The new passphrase is in %s.",
+					target,
+					dest,
+					err,
+					staged,
+				),
+			)
+			return
+		}
+	}
+
+	if generated {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf(
+				"Passphrase of %s rotated; the new passphrase was written to %s",
+				target,
+				dest,
+			),
+		})
+	} else {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Passphrase of %s rotated", target),
+		})
+	}
+	tflog.Debug(ctx, "WLAN passphrase rotated", map[string]any{
+		"wlan_id": id,
+		"target":  target,
+	})
+}
+
+// setWLANPassphrase sets passphrase on wlan, or on its private pre-shared key
+// at index if index is not nil, and describes what was changed.
+func setWLANPassphrase(wlan *unifi.WLAN, index *int64, passphrase string) (string, error) {
+	if index == nil {
+		switch wlan.Security {
+		case "open", "wpaeap", "osen":
+			return "", fmt.Errorf(
+				"WLAN %q uses %s security, which has no passphrase",
+				wlan.Name,
+				wlan.Security,
+			)
+		}
+		wlan.Passphrase = passphrase
+		return fmt.Sprintf("WLAN %q", wlan.Name), nil
+	}
+
+	if !wlan.PrivatePresharedKeysEnabled {
+		return "", fmt.Errorf("WLAN %q does not have private pre-shared keys enabled", wlan.Name)
+	}
+	if *index >= int64(len(wlan.PrivatePresharedKeys)) {
+		return "", fmt.Errorf(
+			"WLAN %q has %d private pre-shared keys; there is no key at index %d",
+			wlan.Name,
+			len(wlan.PrivatePresharedKeys),
+			*index,
+		)
+	}
+	wlan.PrivatePresharedKeys[*index].Password = passphrase
+	return fmt.Sprintf("private pre-shared key %d of WLAN %q", *index, wlan.Name), nil
+}
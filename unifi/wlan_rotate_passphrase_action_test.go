@@ -0,0 +1,170 @@
+package unifi
+
+import (
+	"context"
+	"testing"
+
+	fwaction "github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/ubiquiti-community/go-unifi/unifi"
+)
+
+func TestNewWLANRotatePassphraseAction(t *testing.T) {
+	got := NewWLANRotatePassphraseAction()
+	if got == nil {
+		t.Fatal("NewWLANRotatePassphraseAction() returned nil")
+	}
+	if _, ok := got.(fwaction.ActionWithConfigure); !ok {
+		t.Error("expected ActionWithConfigure interface")
+	}
+}
+
+func Test_wlanRotatePassphraseAction_Metadata(t *testing.T) {
+	a := &wlanRotatePassphraseAction{}
+	resp := &fwaction.MetadataResponse{}
+	a.Metadata(context.Background(), fwaction.MetadataRequest{ProviderTypeName: "unifi"}, resp)
+	if resp.TypeName != "unifi_wlan_rotate_passphrase" {
+		t.Errorf("TypeName = %q, want %q", resp.TypeName, "unifi_wlan_rotate_passphrase")
+	}
+}
+
+func Test_wlanRotatePassphraseAction_Schema(t *testing.T) {
+	a := &wlanRotatePassphraseAction{}
+	resp := &fwaction.SchemaResponse{}
+	a.Schema(context.Background(), fwaction.SchemaRequest{}, resp)
+	for _, attr := range []string{
+		"wlan_id", "private_preshared_key_index", "passphrase", "length", "special", "exclude_ambiguous", "path",
+		"timeouts",
+	} {
+		if _, ok := resp.Schema.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %q in schema", attr)
+		}
+	}
+	if !resp.Schema.Attributes["passphrase"].IsSensitive() {
+		t.Error("passphrase should be sensitive")
+	}
+}
+
+func Test_wlanRotatePassphraseAction_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       fwaction.ConfigureRequest
+		wantError bool
+	}{
+		{"nil_provider_data", fwaction.ConfigureRequest{}, false},
+		{"wrong_type", fwaction.ConfigureRequest{ProviderData: "not-a-client"}, true},
+		{"correct_client", fwaction.ConfigureRequest{ProviderData: &Client{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &wlanRotatePassphraseAction{}
+			resp := &fwaction.ConfigureResponse{}
+			a.Configure(context.Background(), tt.req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v", resp.Diagnostics.HasError(), tt.wantError)
+			}
+		})
+	}
+}
+
+func Test_wlanRotatePassphraseAction_ValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	str := func(v any) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+
+	schemaResp := &fwaction.SchemaResponse{}
+	(&wlanRotatePassphraseAction{}).Schema(ctx, fwaction.SchemaRequest{}, schemaResp)
+	typ, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("schema type is not an object")
+	}
+
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		wantError bool
+	}{
+		{"generated_to_path", map[string]tftypes.Value{"path": str("/tmp/guest-passphrase")}, false},
+		{"given_passphrase", map[string]tftypes.Value{"passphrase": str("correct horse battery")}, false},
+		{"unknown_passphrase", map[string]tftypes.Value{"passphrase": str(tftypes.UnknownValue)}, false},
+		{"generated_without_path", map[string]tftypes.Value{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+			for name, attrType := range typ.AttributeTypes {
+				attrs[name] = tftypes.NewValue(attrType, nil)
+			}
+			attrs["wlan_id"] = str("wlan")
+			for k, v := range tt.values {
+				attrs[k] = v
+			}
+
+			resp := &fwaction.ValidateConfigResponse{}
+			(&wlanRotatePassphraseAction{}).ValidateConfig(
+				ctx,
+				fwaction.ValidateConfigRequest{
+					Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(typ, attrs)},
+				},
+				resp,
+			)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("hasError = %v, want %v (diags: %v)", resp.Diagnostics.HasError(), tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func Test_setWLANPassphrase(t *testing.T) {
+	index := func(i int64) *int64 { return &i }
+
+	t.Run("wlan", func(t *testing.T) {
+		wlan := &unifi.WLAN{Name: "guests", Security: "wpapsk", Passphrase: "old-passphrase"}
+		if _, err := setWLANPassphrase(wlan, nil, "new-passphrase"); err != nil {
+			t.Fatal(err)
+		}
+		if wlan.Passphrase != "new-passphrase" {
+			t.Errorf("Passphrase = %q, want new-passphrase", wlan.Passphrase)
+		}
+	})
+
+	t.Run("open wlan", func(t *testing.T) {
+		wlan := &unifi.WLAN{Name: "guests", Security: "open"}
+		if _, err := setWLANPassphrase(wlan, nil, "new-passphrase"); err == nil {
+			t.Error("setWLANPassphrase() on an open WLAN succeeded, want an error")
+		}
+	})
+
+	t.Run("private pre-shared key", func(t *testing.T) {
+		wlan := &unifi.WLAN{
+			Name:                        "residents",
+			Security:                    "wpapsk",
+			Passphrase:                  "wlan-passphrase",
+			PrivatePresharedKeysEnabled: true,
+			PrivatePresharedKeys: []unifi.WLANPrivatePresharedKeys{
+				{NetworkID: "n1", Password: "unit-101-key"},
+				{NetworkID: "n2", Password: "unit-102-key"},
+			},
+		}
+		if _, err := setWLANPassphrase(wlan, index(1), "new-passphrase"); err != nil {
+			t.Fatal(err)
+		}
+		if got := wlan.PrivatePresharedKeys[1].Password; got != "new-passphrase" {
+			t.Errorf("key 1 password = %q, want new-passphrase", got)
+		}
+		if wlan.PrivatePresharedKeys[0].Password != "unit-101-key" || wlan.Passphrase != "wlan-passphrase" {
+			t.Errorf("other secrets changed: %+v", wlan)
+		}
+
+		if _, err := setWLANPassphrase(wlan, index(2), "new-passphrase"); err == nil {
+			t.Error("setWLANPassphrase() with an index out of range succeeded, want an error")
+		}
+	})
+
+	t.Run("private pre-shared keys disabled", func(t *testing.T) {
+		wlan := &unifi.WLAN{Name: "guests", Security: "wpapsk"}
+		if _, err := setWLANPassphrase(wlan, index(0), "new-passphrase"); err == nil {
+			t.Error("setWLANPassphrase() without private pre-shared keys succeeded, want an error")
+		}
+	})
+}